@@ -0,0 +1,92 @@
+package calculator
+
+// Span is a half-open range of rune offsets into the original expression
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Node is an element of a parsed expression tree
+type Node interface {
+	Span() Span
+}
+
+// NumberNode is a numeric literal
+type NumberNode struct {
+	Value float64
+	Text  string
+	span  Span
+}
+
+// IdentNode is a reference to a named constant or variable
+type IdentNode struct {
+	Name string
+	span Span
+}
+
+// UnaryNode is a prefix operator applied to an operand
+type UnaryNode struct {
+	Op      string
+	Operand Node
+	span    Span
+}
+
+// PostfixNode is a postfix operator such as factorial
+type PostfixNode struct {
+	Op      string
+	Operand Node
+	span    Span
+}
+
+// BinaryNode is an infix operator applied to two operands
+type BinaryNode struct {
+	Op    string
+	Left  Node
+	Right Node
+	span  Span
+}
+
+// CallNode is a function call
+type CallNode struct {
+	Name string
+	Args []Node
+	span Span
+}
+
+// MatrixNode is a matrix literal; every row has the same number of entries
+type MatrixNode struct {
+	Rows [][]Node
+	span Span
+}
+
+func (n *NumberNode) Span() Span  { return n.span }
+func (n *IdentNode) Span() Span   { return n.span }
+func (n *UnaryNode) Span() Span   { return n.span }
+func (n *PostfixNode) Span() Span { return n.span }
+func (n *BinaryNode) Span() Span  { return n.span }
+func (n *CallNode) Span() Span    { return n.span }
+func (n *MatrixNode) Span() Span  { return n.span }
+
+// Walk calls fn for node and each of its descendants in depth-first order
+func Walk(node Node, fn func(Node)) {
+	fn(node)
+	switch n := node.(type) {
+	case *UnaryNode:
+		Walk(n.Operand, fn)
+	case *PostfixNode:
+		Walk(n.Operand, fn)
+	case *BinaryNode:
+		Walk(n.Left, fn)
+		Walk(n.Right, fn)
+	case *CallNode:
+		for _, arg := range n.Args {
+			Walk(arg, fn)
+		}
+	case *MatrixNode:
+		for _, row := range n.Rows {
+			for _, entry := range row {
+				Walk(entry, fn)
+			}
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package calculator
 
 import (
-	"errors"
 	"math"
 )
 
@@ -14,68 +13,106 @@ func NewBasicOperations() *BasicOperations {
 }
 
 // Add performs addition
-func (b *BasicOperations) Add(a, b float64) float64 {
+func (o *BasicOperations) Add(a, b float64) float64 {
 	return a + b
 }
 
 // Subtract performs subtraction
-func (b *BasicOperations) Subtract(a, b float64) float64 {
+func (o *BasicOperations) Subtract(a, b float64) float64 {
 	return a - b
 }
 
 // Multiply performs multiplication
-func (b *BasicOperations) Multiply(a, b float64) float64 {
+func (o *BasicOperations) Multiply(a, b float64) float64 {
 	return a * b
 }
 
 // Divide performs division with zero check
-func (b *BasicOperations) Divide(a, b float64) (float64, error) {
+func (o *BasicOperations) Divide(a, b float64) (float64, error) {
 	if b == 0 {
-		return 0, errors.New("division by zero")
+		return 0, newError(ErrDivisionByZero, "division by zero")
 	}
 	return a / b, nil
 }
 
 // Power calculates a^b
-func (b *BasicOperations) Power(a, b float64) (float64, error) {
+func (o *BasicOperations) Power(a, b float64) (float64, error) {
 	result := math.Pow(a, b)
-	if math.IsNaN(result) || math.IsInf(result, 0) {
-		return 0, errors.New("invalid power operation")
+	switch {
+	case math.IsNaN(result):
+		return 0, newError(ErrDomain, "invalid power operation")
+	case math.IsInf(result, 0) && a == 0:
+		return 0, newError(ErrDivisionByZero, "division by zero")
+	case math.IsInf(result, 0):
+		return 0, newError(ErrOverflow, "power result too large")
 	}
 	return result, nil
 }
 
 // Percentage calculates percentage
-func (b *BasicOperations) Percentage(value, percentage float64) float64 {
+func (o *BasicOperations) Percentage(value, percentage float64) float64 {
 	return (value * percentage) / 100
 }
 
 // SquareRoot calculates square root
-func (b *BasicOperations) SquareRoot(value float64) (float64, error) {
+func (o *BasicOperations) SquareRoot(value float64) (float64, error) {
 	if value < 0 {
-		return 0, errors.New("cannot calculate square root of negative number")
+		return 0, newError(ErrDomain, "cannot calculate square root of negative number")
 	}
 	return math.Sqrt(value), nil
 }
 
 // CubeRoot calculates cube root
-func (b *BasicOperations) CubeRoot(value float64) float64 {
+func (o *BasicOperations) CubeRoot(value float64) float64 {
 	if value >= 0 {
 		return math.Pow(value, 1.0/3.0)
 	}
 	return -math.Pow(-value, 1.0/3.0)
 }
 
+// NthRoot calculates the nth root of value. Odd roots of negative numbers
+// are real and negative.
+func (o *BasicOperations) NthRoot(n, value float64) (float64, error) {
+	switch {
+	case n == 0:
+		return 0, newError(ErrDomain, "root degree must not be zero")
+	case n == 2:
+		return o.SquareRoot(value)
+	case n == 3:
+		return math.Cbrt(value), nil
+	case value < 0:
+		if n != math.Trunc(n) || math.Mod(n, 2) == 0 {
+			return 0, newError(ErrDomain, "cannot calculate even root of negative number")
+		}
+		return -math.Pow(-value, 1/n), nil
+	default:
+		return math.Pow(value, 1/n), nil
+	}
+}
+
+// Modulo calculates the floored remainder of a divided by b, which takes
+// the sign of b
+func (o *BasicOperations) Modulo(a, b float64) (float64, error) {
+	if b == 0 {
+		return 0, newError(ErrDivisionByZero, "modulo by zero")
+	}
+	result := math.Mod(a, b)
+	if result != 0 && (result < 0) != (b < 0) {
+		result += b
+	}
+	return result, nil
+}
+
 // Factorial calculates factorial (for integers up to reasonable limit)
-func (b *BasicOperations) Factorial(n float64) (float64, error) {
+func (o *BasicOperations) Factorial(n float64) (float64, error) {
 	if n < 0 {
-		return 0, errors.New("factorial not defined for negative numbers")
+		return 0, newError(ErrDomain, "factorial not defined for negative numbers")
 	}
 	if n != math.Floor(n) {
-		return 0, errors.New("factorial only defined for integers")
+		return 0, newError(ErrDomain, "factorial only defined for integers")
 	}
 	if n > 170 {
-		return 0, errors.New("factorial result too large")
+		return 0, newError(ErrOverflow, "factorial result too large")
 	}
 	
 	result := 1.0
@@ -86,11 +123,11 @@ func (b *BasicOperations) Factorial(n float64) (float64, error) {
 }
 
 // Absolute calculates absolute value
-func (b *BasicOperations) Absolute(value float64) float64 {
+func (o *BasicOperations) Absolute(value float64) float64 {
 	return math.Abs(value)
 }
 
 // Negate returns negative of value
-func (b *BasicOperations) Negate(value float64) float64 {
+func (o *BasicOperations) Negate(value float64) float64 {
 	return -value
 }
\ No newline at end of file
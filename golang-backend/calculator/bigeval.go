@@ -0,0 +1,479 @@
+package calculator
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MaxPrecision is the largest mantissa size, in bits, accepted for
+// arbitrary-precision evaluation
+const MaxPrecision = 8192
+
+// bigFunction is a built-in function evaluated with big.Float arguments.
+// Arity is checked against the float64 function table of the same name.
+type bigFunction func(ev *bigEvaluation, args []*big.Float) (*big.Float, error)
+
+// bigFunctions implements the built-in function table in arbitrary precision
+var bigFunctions = map[string]bigFunction{
+	"sin": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		if ev.opts.AngleMode.IsDegree() {
+			sin, _ := ev.degreeSinCos(args[0])
+			return sin, nil
+		}
+		return bigSin(args[0], ev.prec), nil
+	},
+	"cos": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		if ev.opts.AngleMode.IsDegree() {
+			_, cos := ev.degreeSinCos(args[0])
+			return cos, nil
+		}
+		return bigCos(args[0], ev.prec), nil
+	},
+	"tan": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		if !ev.opts.AngleMode.IsDegree() {
+			return bigTan(args[0], ev.prec)
+		}
+		sin, cos := ev.degreeSinCos(args[0])
+		if cos.Sign() == 0 {
+			return nil, newError(ErrDomain, "tangent is undefined at this point")
+		}
+		return ev.newFloat().Quo(sin, cos), nil
+	},
+	"asin": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		result, err := bigAsin(args[0], ev.prec)
+		if err != nil {
+			return nil, err
+		}
+		return ev.fromRadians(result), nil
+	},
+	"acos": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		result, err := bigAcos(args[0], ev.prec)
+		if err != nil {
+			return nil, err
+		}
+		return ev.fromRadians(result), nil
+	},
+	"atan": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		return ev.fromRadians(bigAtan(args[0], ev.prec)), nil
+	},
+	"atan2": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		result, err := bigAtan2(args[0], args[1], ev.prec)
+		if err != nil {
+			return nil, err
+		}
+		return ev.fromRadians(result), nil
+	},
+	"log": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		base := bigFromInt(10, ev.prec)
+		if len(args) == 2 {
+			base = args[1]
+		}
+		return ev.logBase(args[0], base)
+	},
+	"ln": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		return bigLog(args[0], ev.prec)
+	},
+	"exp": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		return bigExp(args[0], ev.prec)
+	},
+	"exp2": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		return bigPow(bigFromInt(2, ev.prec), args[0], ev.prec)
+	},
+	"exp10": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		return bigPow(bigFromInt(10, ev.prec), args[0], ev.prec)
+	},
+	"pow": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		return bigPow(args[0], args[1], ev.prec)
+	},
+	"sqrt": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		return bigSqrt(args[0], ev.prec)
+	},
+	"cbrt": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		return ev.nthRoot(bigFromInt(3, ev.prec), args[0])
+	},
+	"root": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		return ev.nthRoot(args[0], args[1])
+	},
+	"hypot": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		sum := ev.newFloat()
+		for _, arg := range args {
+			sum.Add(sum, ev.newFloat().Mul(arg, arg))
+		}
+		return bigSqrt(sum, ev.prec)
+	},
+	"abs": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		return ev.newFloat().Abs(args[0]), nil
+	},
+	"factorial": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		return bigFactorial(args[0], ev.prec)
+	},
+	"mod": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		a, b := args[0], args[1]
+		if b.Sign() == 0 {
+			return nil, newError(ErrDivisionByZero, "modulo by zero")
+		}
+		w := ev.prec + guardBits + uint(max(bigExponent(a)-bigExponent(b), 0))
+		q := bigFloor(new(big.Float).SetPrec(w).Quo(a, b))
+		q.Mul(q, b)
+		return ev.newFloat().Sub(a, q), nil
+	},
+	"min": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		result := args[0]
+		for _, arg := range args[1:] {
+			if arg.Cmp(result) < 0 {
+				result = arg
+			}
+		}
+		return result, nil
+	},
+	"max": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		result := args[0]
+		for _, arg := range args[1:] {
+			if arg.Cmp(result) > 0 {
+				result = arg
+			}
+		}
+		return result, nil
+	},
+	"floor": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		return bigFloor(args[0]), nil
+	},
+	"ceil": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		return bigCeil(args[0]), nil
+	},
+	"round": func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		if len(args) == 1 {
+			return bigRound(args[0]), nil
+		}
+		digits, acc := args[1].Int64()
+		if acc != big.Exact {
+			return nil, newError(ErrDomain, "round digits must be an integer")
+		}
+		if digits > MaxPrecision || digits < -MaxPrecision {
+			return args[0], nil
+		}
+		scale, _ := bigPow(bigFromInt(10, ev.prec), bigFromInt(digits, ev.prec), ev.prec+guardBits)
+		scaled := new(big.Float).SetPrec(ev.prec+guardBits).Mul(args[0], scale)
+		return ev.newFloat().Quo(bigRound(scaled), scale), nil
+	},
+}
+
+// bigEvaluation carries the state of a single arbitrary-precision evaluation
+type bigEvaluation struct {
+	parser *ExpressionParser
+	opts   EvalOptions
+	prec   uint
+	pi     *big.Float
+}
+
+// EvaluateBig parses and evaluates an expression with big.Float arithmetic
+// at opts.Precision bits of mantissa
+func (p *ExpressionParser) EvaluateBig(expression string, opts EvalOptions) (*big.Float, error) {
+	prog, err := p.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	return prog.RunBig(opts)
+}
+
+// RunBig evaluates the program with big.Float arithmetic at opts.Precision
+// bits of mantissa
+func (prog *Program) RunBig(opts EvalOptions) (*big.Float, error) {
+	if opts.Precision == 0 || opts.Precision > MaxPrecision {
+		return nil, newErrorf(ErrUnsupported, "precision must be between 1 and %d bits", MaxPrecision)
+	}
+	opts = prog.parser.withDefaults(opts)
+
+	ev := &bigEvaluation{parser: prog.parser, opts: opts, prec: opts.Precision}
+	return ev.eval(prog.tree)
+}
+
+// FormatBig renders x as a decimal string carrying all the significant
+// digits its precision supports, or digits significant digits if positive
+func FormatBig(x *big.Float, digits int) string {
+	if digits <= 0 {
+		digits = int(float64(x.Prec()) * math.Log10(2))
+	}
+	text := x.Text('g', digits)
+	if mant, exp, found := strings.Cut(text, "e"); found {
+		if strings.Contains(mant, ".") {
+			mant = strings.TrimRight(strings.TrimRight(mant, "0"), ".")
+		}
+		return mant + "e" + exp
+	}
+	return text
+}
+
+func (ev *bigEvaluation) newFloat() *big.Float {
+	return new(big.Float).SetPrec(ev.prec)
+}
+
+// piValue returns π at the evaluation's precision, computing it once
+func (ev *bigEvaluation) piValue() *big.Float {
+	if ev.pi == nil {
+		ev.pi = bigPi(ev.prec + guardBits)
+	}
+	return ev.pi
+}
+
+// degreeSinCos returns the sine and cosine of an angle in degrees. As in
+// the float64 path, the angle is reduced exactly modulo 360 and then to
+// within 45° of a quadrant boundary, so multiples of 30° and 45° give
+// exact values and the poles of tangent an exact zero cosine.
+func (ev *bigEvaluation) degreeSinCos(x *big.Float) (sin, cos *big.Float) {
+	w := ev.prec + guardBits
+	angle, _ := x.Rat(nil)
+	full := big.NewInt(360)
+	turns := new(big.Int).Quo(angle.Num(), new(big.Int).Mul(angle.Denom(), full))
+	reduced := new(big.Rat).Sub(angle, new(big.Rat).SetInt(turns.Mul(turns, full)))
+
+	quarters, _ := new(big.Rat).Quo(reduced, big.NewRat(90, 1)).Float64()
+	quadrant := int64(math.Round(quarters))
+	offset := new(big.Rat).Sub(reduced, big.NewRat(90*quadrant, 1))
+	magnitude := new(big.Rat).Abs(offset)
+
+	switch {
+	case magnitude.Sign() == 0:
+		sin, cos = new(big.Float).SetPrec(w), bigFromInt(1, w)
+	case magnitude.Cmp(big.NewRat(30, 1)) == 0:
+		sin = new(big.Float).SetPrec(w).SetRat(big.NewRat(1, 2))
+		cos, _ = bigSqrt(bigFromInt(3, w), w)
+		cos.Quo(cos, bigFromInt(2, w))
+	case magnitude.Cmp(big.NewRat(45, 1)) == 0:
+		sin, _ = bigSqrt(bigFromInt(2, w), w)
+		sin.Quo(sin, bigFromInt(2, w))
+		cos = new(big.Float).SetPrec(w).Set(sin)
+	default:
+		radians := new(big.Float).SetPrec(w).SetRat(magnitude)
+		radians.Mul(radians, ev.piValue())
+		radians.Quo(radians, bigFromInt(180, w))
+		sin, cos = bigSin(radians, w), bigCos(radians, w)
+	}
+	if offset.Sign() < 0 {
+		sin.Neg(sin)
+	}
+
+	switch quadrant & 3 {
+	case 1:
+		sin, cos = cos, sin.Neg(sin)
+	case 2:
+		sin, cos = sin.Neg(sin), cos.Neg(cos)
+	case 3:
+		sin, cos = cos.Neg(cos), sin
+	}
+	return ev.exactZero(sin), ev.exactZero(cos)
+}
+
+// exactZero rounds x to the evaluation's precision, folding -0 into 0 so
+// sin(-180) and cos(90) are plain zeros
+func (ev *bigEvaluation) exactZero(x *big.Float) *big.Float {
+	if x.Sign() == 0 {
+		return ev.newFloat()
+	}
+	return ev.newFloat().Set(x)
+}
+
+// fromRadians converts an angle result according to the angle mode
+func (ev *bigEvaluation) fromRadians(x *big.Float) *big.Float {
+	if !ev.opts.AngleMode.IsDegree() {
+		return x
+	}
+	w := ev.prec + guardBits
+	result := new(big.Float).SetPrec(w).Mul(x, bigFromInt(180, w))
+	result.Quo(result, ev.piValue())
+	return ev.newFloat().Set(result)
+}
+
+// fromFloat64 converts a bound value through its shortest decimal form, so
+// that 0.1 means one tenth at full precision rather than its binary
+// approximation
+func (ev *bigEvaluation) fromFloat64(value float64) *big.Float {
+	x, _, err := ev.newFloat().Parse(strconv.FormatFloat(value, 'g', -1, 64), 10)
+	if err != nil {
+		return ev.newFloat().SetFloat64(value)
+	}
+	return x
+}
+
+// logBase computes the logarithm of x in the given base
+func (ev *bigEvaluation) logBase(x, base *big.Float) (*big.Float, error) {
+	if x.Sign() <= 0 {
+		return nil, newError(ErrDomain, "logarithm domain error: value must be positive")
+	}
+	if base.Sign() <= 0 || base.Cmp(bigFromInt(1, ev.prec)) == 0 {
+		return nil, newError(ErrDomain, "logarithm base error: base must be positive and not equal to 1")
+	}
+
+	w := ev.prec + guardBits
+	num, _ := bigLog(x, w)
+	den, _ := bigLog(base, w)
+	return ev.newFloat().Quo(num, den), nil
+}
+
+// nthRoot computes the nth root of x; odd roots of negatives are negative
+func (ev *bigEvaluation) nthRoot(n, x *big.Float) (*big.Float, error) {
+	if n.Sign() == 0 {
+		return nil, newError(ErrDomain, "root degree must not be zero")
+	}
+	if n.Cmp(bigFromInt(2, ev.prec)) == 0 {
+		return bigSqrt(x, ev.prec)
+	}
+
+	negative := x.Sign() < 0
+	if negative {
+		degree, _ := n.Int(nil)
+		if !n.IsInt() || degree.Bit(0) == 0 {
+			return nil, newError(ErrDomain, "cannot calculate even root of negative number")
+		}
+	}
+	if x.Sign() == 0 {
+		return ev.newFloat(), nil
+	}
+
+	w := ev.prec + guardBits
+	abs := new(big.Float).SetPrec(w).Abs(x)
+	ln, err := bigLog(abs, w)
+	if err != nil {
+		return nil, err
+	}
+	ln.Quo(ln, n)
+	result, err := bigExp(ln, ev.prec)
+	if err != nil {
+		return nil, err
+	}
+	if negative {
+		result.Neg(result)
+	}
+	return result, nil
+}
+
+// eval walks an expression tree and computes its value
+func (ev *bigEvaluation) eval(node Node) (*big.Float, error) {
+	switch n := node.(type) {
+	case *NumberNode:
+		value, _, err := new(big.Float).SetPrec(ev.prec).Parse(strings.ReplaceAll(n.Text, "_", ""), 0)
+		if err != nil {
+			return nil, errorAt(ErrSyntax, n.span, "invalid number '%s'", n.Text)
+		}
+		return value, nil
+
+	case *IdentNode:
+		if value, ok := ev.opts.lookup(n.Name); ok {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return nil, errorAt(ErrDomain, n.span, "variable '%s' is not a finite number", n.Name)
+			}
+			return ev.fromFloat64(value), nil
+		}
+		switch n.Name {
+		case "pi", "π":
+			return ev.newFloat().Set(ev.piValue()), nil
+		case "e":
+			return bigExp(bigFromInt(1, ev.prec), ev.prec)
+		}
+		if value, ok := ev.parser.constants[n.Name]; ok {
+			return ev.fromFloat64(value), nil
+		}
+		return nil, errorAt(ErrUnknownVariable, n.span, "unknown identifier '%s'", n.Name)
+
+	case *UnaryNode:
+		operand, err := ev.eval(n.Operand)
+		if err != nil {
+			return nil, err
+		}
+		if bitwiseOperators[n.Op] {
+			return nil, unsupportedOperator(n.Op, n.span)
+		}
+		if n.Op == "-" {
+			return ev.newFloat().Neg(operand), nil
+		}
+		return operand, nil
+
+	case *PostfixNode:
+		operand, err := ev.eval(n.Operand)
+		if err != nil {
+			return nil, err
+		}
+		result, err := bigFactorial(operand, ev.prec)
+		if err != nil {
+			return nil, locate(err, n.span, "factorial error: ")
+		}
+		return result, nil
+
+	case *BinaryNode:
+		return ev.evalBinary(n)
+
+	case *CallNode:
+		return ev.evalCall(n)
+	}
+
+	return nil, unsupportedNode(node, "arbitrary-precision mode")
+}
+
+// evalBinary evaluates both operands and applies an infix operator
+func (ev *bigEvaluation) evalBinary(n *BinaryNode) (*big.Float, error) {
+	left, err := ev.eval(n.Left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := ev.eval(n.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Op {
+	case "+":
+		return ev.newFloat().Add(left, right), nil
+	case "-":
+		return ev.newFloat().Sub(left, right), nil
+	case "*":
+		return ev.newFloat().Mul(left, right), nil
+	case "/":
+		if right.Sign() == 0 {
+			return nil, errorAt(ErrDivisionByZero, n.span, "division by zero")
+		}
+		return ev.newFloat().Quo(left, right), nil
+	case "^":
+		result, err := bigPow(left, right, ev.prec)
+		if err != nil {
+			return nil, locate(err, n.span, "")
+		}
+		return result, nil
+	}
+
+	return nil, unsupportedOperator(n.Op, n.span)
+}
+
+// evalCall evaluates a function call
+func (ev *bigEvaluation) evalCall(n *CallNode) (*big.Float, error) {
+	spec, ok := ev.parser.functions[n.Name]
+	if !ok {
+		if _, user := ev.opts.userFunction(n.Name); user {
+			return nil, errorAt(ErrUnsupported, n.span, "user-defined function %s is not supported in arbitrary-precision mode", n.Name)
+		}
+		return nil, errorAt(ErrUnknownFunction, n.span, "unknown function '%s'", n.Name)
+	}
+	fn, ok := bigFunctions[n.Name]
+	if !ok {
+		return nil, errorAt(ErrUnsupported, n.span, "function %s is not supported in arbitrary-precision mode", n.Name)
+	}
+	if err := spec.checkArity(n.Name, len(n.Args)); err != nil {
+		return nil, locate(err, n.span, "")
+	}
+
+	args := make([]*big.Float, len(n.Args))
+	for i, argNode := range n.Args {
+		arg, err := ev.eval(argNode)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = arg
+	}
+
+	result, err := fn(ev, args)
+	if err != nil {
+		return nil, locate(err, n.span, "error in "+n.Name+" function: ")
+	}
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package calculator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEvaluateBig(t *testing.T) {
+	cases := []struct {
+		expression string
+		mode       AngleMode
+		want       string // leading digits of the result
+	}{
+		{"2^100 + 1 - 2^100", AngleRadian, "1"},
+		{"30!", AngleRadian, "265252859812191058636308480000000"},
+		{"factorial(200) / factorial(199)", AngleRadian, "200"},
+		{"pi", AngleRadian, "3.14159265358979323846264338327950288419716939937510"},
+		{"e", AngleRadian, "2.71828182845904523536028747135266249775724709369995"},
+		{"exp(1)", AngleRadian, "2.71828182845904523536028747135266249775724709369995"},
+		{"sqrt(2)", AngleRadian, "1.41421356237309504880168872420969807856967187537694"},
+		{"ln(2)", AngleRadian, "0.69314718055994530941723212145817656807550013436025"},
+		{"sin(1)", AngleRadian, "0.84147098480789650665250232163029899962256306079837"},
+		{"cos(1)", AngleRadian, "0.54030230586813971740093660744297660373231042061792"},
+		{"4*atan(1)", AngleRadian, "3.14159265358979323846264338327950288419716939937510"},
+		{"sin(30)", AngleDegree, "0.5"},
+		{"1e-400 * 1e400", AngleRadian, "1"},
+	}
+	for _, tc := range cases {
+		value, err := New().EvaluateBig(tc.expression, EvalOptions{AngleMode: tc.mode, Precision: 200})
+		if err != nil {
+			t.Errorf("%s: %v", tc.expression, err)
+			continue
+		}
+		// 200 bits carry 60 significant digits; the last may be rounded
+		if got := FormatBig(value, 55); !strings.HasPrefix(got, tc.want) {
+			t.Errorf("%s = %s, want %s", tc.expression, got, tc.want)
+		}
+	}
+}
+
+func TestEvaluateBigDegrees(t *testing.T) {
+	cases := []struct {
+		expression string
+		want       string
+	}{
+		{"sin(180)", "0"},
+		{"sin(-180)", "0"},
+		{"cos(90)", "0"},
+		{"cos(270)", "0"},
+		{"tan(45)", "1"},
+		{"tan(-135)", "1"},
+		{"sin(30)", "0.5"},
+		{"cos(120)", "-0.5"},
+		{"sin(150 + 360*10^40)", "0.5"},
+		{"sin(45)^2", "0.5"},
+		{"sin(10)", "0.1736481776669303488517166267693147960003756771840693872362413781320658221390147354215166131573995"},
+	}
+	for _, tc := range cases {
+		// 333 bits carry 100 significant digits
+		value, err := New().EvaluateBig(tc.expression, EvalOptions{AngleMode: AngleDegree, Precision: 333})
+		if err != nil {
+			t.Errorf("%s: %v", tc.expression, err)
+			continue
+		}
+		if got := FormatBig(value, 0); !strings.HasPrefix(got, tc.want) || (!strings.Contains(tc.want, ".") && got != tc.want) {
+			t.Errorf("%s = %s, want %s", tc.expression, got, tc.want)
+		}
+	}
+
+	for _, expr := range []string{"tan(90)", "tan(-270)"} {
+		if _, err := New().EvaluateBig(expr, EvalOptions{AngleMode: AngleDegree, Precision: 333}); !errors.Is(err, ErrDomain) {
+			t.Errorf("%s: error %v, want %v", expr, err, ErrDomain)
+		}
+	}
+}
+
+func TestEvaluateBigErrors(t *testing.T) {
+	cases := []struct {
+		expression string
+		precision  uint
+		kind       error
+	}{
+		{"1", 0, ErrUnsupported},
+		{"1", MaxPrecision + 1, ErrUnsupported},
+		{"1/0", 100, ErrDivisionByZero},
+		{"sqrt(-1)", 100, ErrDomain},
+		{"ln(0)", 100, ErrDomain},
+		{"zeta(2)", 100, ErrUnsupported},
+		{"foo(1)", 100, ErrUnknownFunction},
+		{"sin(1, 2)", 100, ErrArgumentCount},
+	}
+	for _, tc := range cases {
+		if _, err := New().EvaluateBig(tc.expression, EvalOptions{Precision: tc.precision}); !errors.Is(err, tc.kind) {
+			t.Errorf("%s at %d bits: error %v, want %v", tc.expression, tc.precision, err, tc.kind)
+		}
+	}
+}
+
+func TestFormatBig(t *testing.T) {
+	value, err := New().EvaluateBig("1/3", EvalOptions{Precision: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FormatBig(value, 0); got != "0.3333333333333333333" {
+		t.Errorf("1/3 at 64 bits = %s", got)
+	}
+	if got := FormatBig(value, 5); got != "0.33333" {
+		t.Errorf("1/3 to 5 digits = %s", got)
+	}
+	value, err = New().EvaluateBig("2^-200", EvalOptions{Precision: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FormatBig(value, 5); got != "6.223e-61" {
+		t.Errorf("2^-200 to 5 digits = %s", got)
+	}
+}
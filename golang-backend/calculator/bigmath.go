@@ -0,0 +1,457 @@
+package calculator
+
+import (
+	"math/big"
+)
+
+// guardBits is the extra working precision used inside series evaluations
+const guardBits = 32
+
+// bigFromInt returns v as a big.Float with the given precision
+func bigFromInt(v int64, prec uint) *big.Float {
+	return new(big.Float).SetPrec(prec).SetInt64(v)
+}
+
+// bigExponent returns the binary exponent of x, or 0 for zero
+func bigExponent(x *big.Float) int {
+	return x.MantExp(nil)
+}
+
+// negligible reports whether term no longer affects sum at prec bits
+func negligible(term, sum *big.Float, prec uint) bool {
+	if term.Sign() == 0 {
+		return true
+	}
+	if sum.Sign() == 0 {
+		return false
+	}
+	return bigExponent(term) < bigExponent(sum)-int(prec)
+}
+
+// bigAtanInv computes atan(1/n) for an integer n > 1 by its Taylor series
+func bigAtanInv(n int64, prec uint) *big.Float {
+	term := new(big.Float).SetPrec(prec).Quo(bigFromInt(1, prec), bigFromInt(n, prec))
+	sum := new(big.Float).SetPrec(prec).Set(term)
+	nSquared := bigFromInt(n*n, prec)
+
+	for k := int64(1); ; k++ {
+		term.Quo(term, nSquared)
+		t := new(big.Float).SetPrec(prec).Quo(term, bigFromInt(2*k+1, prec))
+		if negligible(t, sum, prec) {
+			return sum
+		}
+		if k%2 == 1 {
+			sum.Sub(sum, t)
+		} else {
+			sum.Add(sum, t)
+		}
+	}
+}
+
+// bigPi computes π with Machin's formula π = 16·atan(1/5) − 4·atan(1/239)
+func bigPi(prec uint) *big.Float {
+	w := prec + guardBits
+	a := bigAtanInv(5, w)
+	a.Mul(a, bigFromInt(16, w))
+	b := bigAtanInv(239, w)
+	b.Mul(b, bigFromInt(4, w))
+	return new(big.Float).SetPrec(prec).Sub(a, b)
+}
+
+// bigSqrt computes the square root of a non-negative x
+func bigSqrt(x *big.Float, prec uint) (*big.Float, error) {
+	if x.Sign() < 0 {
+		return nil, newError(ErrDomain, "cannot calculate square root of negative number")
+	}
+	return new(big.Float).SetPrec(prec).Sqrt(x), nil
+}
+
+// bigExp computes e^x. The argument is halved until it is small, summed
+// as a Taylor series and the result squared back up.
+func bigExp(x *big.Float, prec uint) (*big.Float, error) {
+	if x.Sign() == 0 {
+		return bigFromInt(1, prec), nil
+	}
+	if bigExponent(x) > 40 {
+		if x.Sign() < 0 {
+			return new(big.Float).SetPrec(prec), nil
+		}
+		return nil, newError(ErrOverflow, "exponential overflow")
+	}
+
+	halvings := bigExponent(x) + 8
+	if halvings < 0 {
+		halvings = 0
+	}
+	w := prec + guardBits + uint(halvings)
+
+	r := new(big.Float).SetPrec(w).SetMantExp(x, -halvings)
+	sum := bigFromInt(1, w)
+	term := bigFromInt(1, w)
+	for n := int64(1); ; n++ {
+		term.Mul(term, r)
+		term.Quo(term, bigFromInt(n, w))
+		if negligible(term, sum, w) {
+			break
+		}
+		sum.Add(sum, term)
+	}
+
+	for i := 0; i < halvings; i++ {
+		sum.Mul(sum, sum)
+	}
+	if sum.IsInf() {
+		return nil, newError(ErrOverflow, "exponential overflow")
+	}
+	return new(big.Float).SetPrec(prec).Set(sum), nil
+}
+
+// bigAtanh computes atanh(z) for |z| < 1 by its Taylor series
+func bigAtanh(z *big.Float, prec uint) *big.Float {
+	sum := new(big.Float).SetPrec(prec).Set(z)
+	power := new(big.Float).SetPrec(prec).Set(z)
+	zSquared := new(big.Float).SetPrec(prec).Mul(z, z)
+
+	for k := int64(1); ; k++ {
+		power.Mul(power, zSquared)
+		t := new(big.Float).SetPrec(prec).Quo(power, bigFromInt(2*k+1, prec))
+		if negligible(t, sum, prec) {
+			return sum
+		}
+		sum.Add(sum, t)
+	}
+}
+
+// bigLogNear1 computes ln(x) = 2·atanh((x−1)/(x+1)), which converges
+// quickly for x close to 1
+func bigLogNear1(x *big.Float, prec uint) *big.Float {
+	one := bigFromInt(1, prec)
+	num := new(big.Float).SetPrec(prec).Sub(x, one)
+	den := new(big.Float).SetPrec(prec).Add(x, one)
+	z := new(big.Float).SetPrec(prec).Quo(num, den)
+	result := bigAtanh(z, prec)
+	return result.Mul(result, bigFromInt(2, prec))
+}
+
+// bigLog computes the natural logarithm of a positive x
+func bigLog(x *big.Float, prec uint) (*big.Float, error) {
+	if x.Sign() <= 0 {
+		return nil, newError(ErrDomain, "natural logarithm domain error: value must be positive")
+	}
+	w := prec + guardBits
+
+	// Arguments in [0.5, 2) are handled directly to avoid cancellation
+	exp := bigExponent(x)
+	if exp == 0 || exp == 1 {
+		return new(big.Float).SetPrec(prec).Set(bigLogNear1(x, w)), nil
+	}
+
+	// x = m·2^exp with m in [0.5, 1), so ln x = ln m + exp·ln 2
+	mant := new(big.Float).SetPrec(w)
+	x.MantExp(mant)
+	result := bigLogNear1(mant, w)
+
+	ln2 := bigLogNear1(bigFromInt(2, w), w)
+	ln2.Mul(ln2, bigFromInt(int64(exp), w))
+	result.Add(result, ln2)
+
+	return new(big.Float).SetPrec(prec).Set(result), nil
+}
+
+// bigReduceAngle reduces x into [−π, π]
+func bigReduceAngle(x *big.Float, prec uint) *big.Float {
+	extra := bigExponent(x)
+	if extra < 0 {
+		extra = 0
+	}
+	w := prec + guardBits + uint(extra)
+
+	twoPi := bigPi(w)
+	twoPi.Mul(twoPi, bigFromInt(2, w))
+
+	q := new(big.Float).SetPrec(w).Quo(x, twoPi)
+	n := bigRound(q)
+	n.Mul(n, twoPi)
+	return new(big.Float).SetPrec(w).Sub(x, n)
+}
+
+// bigSin computes sin(x) for x in radians
+func bigSin(x *big.Float, prec uint) *big.Float {
+	r := bigReduceAngle(x, prec)
+	if r.Sign() == 0 {
+		return new(big.Float).SetPrec(prec)
+	}
+	w := r.Prec()
+
+	sum := new(big.Float).SetPrec(w).Set(r)
+	term := new(big.Float).SetPrec(w).Set(r)
+	rSquared := new(big.Float).SetPrec(w).Mul(r, r)
+	for n := int64(1); ; n++ {
+		term.Mul(term, rSquared)
+		term.Quo(term, bigFromInt((2*n)*(2*n+1), w))
+		term.Neg(term)
+		if negligible(term, sum, w) {
+			break
+		}
+		sum.Add(sum, term)
+	}
+	return new(big.Float).SetPrec(prec).Set(sum)
+}
+
+// bigCos computes cos(x) for x in radians
+func bigCos(x *big.Float, prec uint) *big.Float {
+	r := bigReduceAngle(x, prec)
+	w := r.Prec()
+
+	sum := bigFromInt(1, w)
+	term := bigFromInt(1, w)
+	rSquared := new(big.Float).SetPrec(w).Mul(r, r)
+	for n := int64(1); ; n++ {
+		term.Mul(term, rSquared)
+		term.Quo(term, bigFromInt((2*n-1)*(2*n), w))
+		term.Neg(term)
+		if term.Sign() == 0 || bigExponent(term) < -int(w) {
+			break
+		}
+		sum.Add(sum, term)
+	}
+	return new(big.Float).SetPrec(prec).Set(sum)
+}
+
+// bigTan computes tan(x) for x in radians
+func bigTan(x *big.Float, prec uint) (*big.Float, error) {
+	w := prec + guardBits
+	cos := bigCos(x, w)
+	if cos.Sign() == 0 || bigExponent(cos) < -int(prec) {
+		return nil, newError(ErrDomain, "tangent is undefined at this point")
+	}
+	sin := bigSin(x, w)
+	return new(big.Float).SetPrec(prec).Quo(sin, cos), nil
+}
+
+// bigAtan computes atan(x) in radians. The argument is folded into [0, 1]
+// and halved with atan(a) = 2·atan(a/(1+√(1+a²))) before summing the series.
+func bigAtan(x *big.Float, prec uint) *big.Float {
+	if x.Sign() == 0 {
+		return new(big.Float).SetPrec(prec)
+	}
+	w := prec + guardBits
+	one := bigFromInt(1, w)
+
+	a := new(big.Float).SetPrec(w).Abs(x)
+	invert := a.Cmp(one) > 0
+	if invert {
+		a.Quo(one, a)
+	}
+
+	eighth := new(big.Float).SetPrec(w).SetFloat64(0.125)
+	doublings := 0
+	for a.Cmp(eighth) > 0 {
+		den := new(big.Float).SetPrec(w).Mul(a, a)
+		den.Add(den, one)
+		den.Sqrt(den)
+		den.Add(den, one)
+		a.Quo(a, den)
+		doublings++
+	}
+
+	sum := new(big.Float).SetPrec(w).Set(a)
+	power := new(big.Float).SetPrec(w).Set(a)
+	aSquared := new(big.Float).SetPrec(w).Mul(a, a)
+	for k := int64(1); ; k++ {
+		power.Mul(power, aSquared)
+		t := new(big.Float).SetPrec(w).Quo(power, bigFromInt(2*k+1, w))
+		if negligible(t, sum, w) {
+			break
+		}
+		if k%2 == 1 {
+			sum.Sub(sum, t)
+		} else {
+			sum.Add(sum, t)
+		}
+	}
+	sum.SetMantExp(sum, doublings)
+
+	if invert {
+		halfPi := bigPi(w)
+		halfPi.SetMantExp(halfPi, -1)
+		sum.Sub(halfPi, sum)
+	}
+	if x.Sign() < 0 {
+		sum.Neg(sum)
+	}
+	return new(big.Float).SetPrec(prec).Set(sum)
+}
+
+// bigAsin computes asin(x) in radians
+func bigAsin(x *big.Float, prec uint) (*big.Float, error) {
+	w := prec + guardBits
+	one := bigFromInt(1, w)
+	abs := new(big.Float).Abs(x)
+
+	switch abs.Cmp(one) {
+	case 1:
+		return nil, newError(ErrDomain, "arcsin domain error: value must be between -1 and 1")
+	case 0:
+		halfPi := bigPi(prec)
+		halfPi.SetMantExp(halfPi, -1)
+		if x.Sign() < 0 {
+			halfPi.Neg(halfPi)
+		}
+		return halfPi, nil
+	}
+
+	// asin(x) = atan(x/√(1−x²))
+	den := new(big.Float).SetPrec(w).Mul(x, x)
+	den.Sub(one, den)
+	den.Sqrt(den)
+	ratio := new(big.Float).SetPrec(w).Quo(x, den)
+	return bigAtan(ratio, prec), nil
+}
+
+// bigAcos computes acos(x) in radians
+func bigAcos(x *big.Float, prec uint) (*big.Float, error) {
+	w := prec + guardBits
+	asin, err := bigAsin(x, w)
+	if err != nil {
+		return nil, newError(ErrDomain, "arccos domain error: value must be between -1 and 1")
+	}
+	halfPi := bigPi(w)
+	halfPi.SetMantExp(halfPi, -1)
+	return new(big.Float).SetPrec(prec).Sub(halfPi, asin), nil
+}
+
+// bigAtan2 computes the angle of the point (x, y) in radians
+func bigAtan2(y, x *big.Float, prec uint) (*big.Float, error) {
+	w := prec + guardBits
+	switch {
+	case x.Sign() == 0 && y.Sign() == 0:
+		return nil, newError(ErrDomain, "atan2 is undefined at the origin")
+	case x.Sign() == 0:
+		halfPi := bigPi(prec)
+		halfPi.SetMantExp(halfPi, -1)
+		if y.Sign() < 0 {
+			halfPi.Neg(halfPi)
+		}
+		return halfPi, nil
+	}
+
+	result := bigAtan(new(big.Float).SetPrec(w).Quo(y, x), w)
+	if x.Sign() < 0 {
+		if y.Sign() < 0 {
+			result.Sub(result, bigPi(w))
+		} else {
+			result.Add(result, bigPi(w))
+		}
+	}
+	return new(big.Float).SetPrec(prec).Set(result), nil
+}
+
+// bigFloor returns the greatest integer less than or equal to x
+func bigFloor(x *big.Float) *big.Float {
+	i, _ := x.Int(nil)
+	result := new(big.Float).SetPrec(x.Prec()).SetInt(i)
+	if x.Sign() < 0 && !x.IsInt() {
+		result.Sub(result, bigFromInt(1, x.Prec()))
+	}
+	return result
+}
+
+// bigCeil returns the least integer greater than or equal to x
+func bigCeil(x *big.Float) *big.Float {
+	i, _ := x.Int(nil)
+	result := new(big.Float).SetPrec(x.Prec()).SetInt(i)
+	if x.Sign() > 0 && !x.IsInt() {
+		result.Add(result, bigFromInt(1, x.Prec()))
+	}
+	return result
+}
+
+// bigRound rounds x to the nearest integer, halves away from zero
+func bigRound(x *big.Float) *big.Float {
+	if x.IsInt() {
+		return new(big.Float).Set(x)
+	}
+	half := new(big.Float).SetPrec(x.Prec()).SetFloat64(0.5)
+	shifted := new(big.Float).SetPrec(x.Prec() + 1)
+	if x.Sign() < 0 {
+		shifted.Sub(x, half)
+		return bigCeil(shifted).SetPrec(x.Prec())
+	}
+	shifted.Add(x, half)
+	return bigFloor(shifted).SetPrec(x.Prec())
+}
+
+// bigPow computes x^y. Integer exponents are computed by repeated
+// squaring; other exponents use exp(y·ln x).
+func bigPow(x, y *big.Float, prec uint) (*big.Float, error) {
+	if y.IsInt() && bigExponent(y) <= 32 {
+		n, _ := y.Int64()
+		if n < 0 && x.Sign() == 0 {
+			return nil, newError(ErrDivisionByZero, "division by zero")
+		}
+
+		abs := n
+		if abs < 0 {
+			abs = -abs
+		}
+		w := prec + guardBits + uint(bigExponent(y))
+		result := bigFromInt(1, w)
+		base := new(big.Float).SetPrec(w).Set(x)
+		for ; abs > 0; abs >>= 1 {
+			if abs&1 == 1 {
+				result.Mul(result, base)
+			}
+			base.Mul(base, base)
+		}
+		if n < 0 {
+			result.Quo(bigFromInt(1, w), result)
+		}
+		if result.IsInf() {
+			return nil, newError(ErrOverflow, "power result too large")
+		}
+		return new(big.Float).SetPrec(prec).Set(result), nil
+	}
+
+	switch x.Sign() {
+	case 0:
+		if y.Sign() > 0 {
+			return new(big.Float).SetPrec(prec), nil
+		}
+		return nil, newError(ErrDivisionByZero, "division by zero")
+	case -1:
+		return nil, newError(ErrDomain, "invalid power operation")
+	}
+
+	w := prec + guardBits
+	ln, err := bigLog(x, w)
+	if err != nil {
+		return nil, err
+	}
+	ln.Mul(ln, y)
+	result, err := bigExp(ln, prec)
+	if err != nil {
+		return nil, newError(ErrOverflow, "power result too large")
+	}
+	return result, nil
+}
+
+// maxBigFactorial bounds factorial arguments in arbitrary-precision mode
+const maxBigFactorial = 20000
+
+// bigFactorial computes n! exactly for a non-negative integer n
+func bigFactorial(n *big.Float, prec uint) (*big.Float, error) {
+	if n.Sign() < 0 {
+		return nil, newError(ErrDomain, "factorial not defined for negative numbers")
+	}
+	if !n.IsInt() {
+		return nil, newError(ErrDomain, "factorial only defined for integers")
+	}
+	if n.Cmp(bigFromInt(maxBigFactorial, 64)) > 0 {
+		return nil, newError(ErrOverflow, "factorial result too large")
+	}
+
+	v, _ := n.Int64()
+	product := new(big.Int).MulRange(1, v)
+	return new(big.Float).SetPrec(prec).SetInt(product), nil
+}
@@ -0,0 +1,790 @@
+package calculator
+
+import "sort"
+
+// constantDescriptions documents the built-in constants
+var constantDescriptions = map[string]string{
+	"pi": "Ratio of a circle's circumference to its diameter",
+	"π":  "Ratio of a circle's circumference to its diameter",
+	"e":  "Base of the natural logarithm",
+}
+
+// builtinInfo documents the built-in functions
+var builtinInfo = map[string]FunctionInfo{
+	"sin": {
+		Category:    "trigonometric",
+		Description: "Sine of an angle",
+		Domain:      "all real x",
+		Angle:       AngleArgument,
+		Examples:    []string{"sin(30)", "sin(pi/2)"},
+	},
+	"cos": {
+		Category:    "trigonometric",
+		Description: "Cosine of an angle",
+		Domain:      "all real x",
+		Angle:       AngleArgument,
+		Examples:    []string{"cos(60)", "cos(pi)"},
+	},
+	"tan": {
+		Category:    "trigonometric",
+		Description: "Tangent of an angle",
+		Domain:      "x not an odd multiple of 90° (π/2)",
+		Angle:       AngleArgument,
+		Examples:    []string{"tan(45)"},
+	},
+	"sec": {
+		Category:    "trigonometric",
+		Description: "Secant of an angle, 1/cos(x)",
+		Domain:      "x not an odd multiple of 90° (π/2)",
+		Angle:       AngleArgument,
+		Examples:    []string{"sec(60)"},
+	},
+	"csc": {
+		Category:    "trigonometric",
+		Description: "Cosecant of an angle, 1/sin(x)",
+		Domain:      "x not a multiple of 180° (π)",
+		Angle:       AngleArgument,
+		Examples:    []string{"csc(30)"},
+	},
+	"cot": {
+		Category:    "trigonometric",
+		Description: "Cotangent of an angle, cos(x)/sin(x)",
+		Domain:      "x not a multiple of 180° (π)",
+		Angle:       AngleArgument,
+		Examples:    []string{"cot(45)"},
+	},
+	"asin": {
+		Category:    "inverse trigonometric",
+		Description: "Inverse sine; the angle whose sine is x",
+		Domain:      "-1 ≤ x ≤ 1",
+		Angle:       AngleResult,
+		Examples:    []string{"asin(0.5)"},
+	},
+	"acos": {
+		Category:    "inverse trigonometric",
+		Description: "Inverse cosine; the angle whose cosine is x",
+		Domain:      "-1 ≤ x ≤ 1",
+		Angle:       AngleResult,
+		Examples:    []string{"acos(0.5)"},
+	},
+	"atan": {
+		Category:    "inverse trigonometric",
+		Description: "Inverse tangent; the angle whose tangent is x",
+		Domain:      "all real x",
+		Angle:       AngleResult,
+		Examples:    []string{"atan(1)"},
+	},
+	"asec": {
+		Category:    "inverse trigonometric",
+		Description: "Inverse secant; the angle between 0 and π whose secant is x",
+		Domain:      "x ≤ -1 or x ≥ 1",
+		Angle:       AngleResult,
+		Examples:    []string{"asec(2)"},
+	},
+	"acsc": {
+		Category:    "inverse trigonometric",
+		Description: "Inverse cosecant; the angle between -π/2 and π/2 whose cosecant is x",
+		Domain:      "x ≤ -1 or x ≥ 1",
+		Angle:       AngleResult,
+		Examples:    []string{"acsc(2)"},
+	},
+	"acot": {
+		Category:    "inverse trigonometric",
+		Description: "Inverse cotangent; the angle between 0 and π whose cotangent is x",
+		Domain:      "all real x",
+		Angle:       AngleResult,
+		Examples:    []string{"acot(1)"},
+	},
+	"atan2": {
+		Category:    "inverse trigonometric",
+		Description: "Angle of the point (x, y) from the positive x axis",
+		Domain:      "(y, x) ≠ (0, 0)",
+		Angle:       AngleResult,
+		Examples:    []string{"atan2(1, -1)"},
+	},
+	"log": {
+		Category:    "logarithmic",
+		Description: "Base-10 logarithm, or logarithm in the base given as second argument",
+		Domain:      "x > 0; base > 0 and base ≠ 1",
+		Examples:    []string{"log(1000)", "log(8, 2)"},
+	},
+	"ln": {
+		Category:    "logarithmic",
+		Description: "Natural logarithm",
+		Domain:      "x > 0",
+		Examples:    []string{"ln(e^2)"},
+	},
+	"exp": {
+		Category:    "exponential",
+		Description: "e raised to the power x",
+		Domain:      "x ≤ 709",
+		Examples:    []string{"exp(1)"},
+	},
+	"exp2": {
+		Category:    "exponential",
+		Description: "2 raised to the power x",
+		Domain:      "x < 1024",
+		Examples:    []string{"exp2(10)"},
+	},
+	"exp10": {
+		Category:    "exponential",
+		Description: "10 raised to the power x",
+		Domain:      "x ≤ 308",
+		Examples:    []string{"exp10(3)"},
+	},
+	"pow": {
+		Category:    "exponential",
+		Description: "x raised to the power y, the same as x^y",
+		Domain:      "real result",
+		Examples:    []string{"pow(2, 10)"},
+	},
+	"sinh": {
+		Category:    "hyperbolic",
+		Description: "Hyperbolic sine",
+		Domain:      "|x| ≤ 710",
+		Examples:    []string{"sinh(1)"},
+	},
+	"cosh": {
+		Category:    "hyperbolic",
+		Description: "Hyperbolic cosine",
+		Domain:      "|x| ≤ 710",
+		Examples:    []string{"cosh(1)"},
+	},
+	"tanh": {
+		Category:    "hyperbolic",
+		Description: "Hyperbolic tangent",
+		Domain:      "all real x",
+		Examples:    []string{"tanh(0.5)"},
+	},
+	"asinh": {
+		Category:    "hyperbolic",
+		Description: "Inverse hyperbolic sine",
+		Domain:      "all real x",
+		Examples:    []string{"asinh(1)"},
+	},
+	"acosh": {
+		Category:    "hyperbolic",
+		Description: "Inverse hyperbolic cosine",
+		Domain:      "x ≥ 1; any z in complex mode",
+		Examples:    []string{"acosh(2)"},
+	},
+	"atanh": {
+		Category:    "hyperbolic",
+		Description: "Inverse hyperbolic tangent",
+		Domain:      "-1 < x < 1; z ≠ ±1 in complex mode",
+		Examples:    []string{"atanh(0.5)"},
+	},
+	"sqrt": {
+		Category:    "roots",
+		Description: "Square root",
+		Domain:      "x ≥ 0",
+		Examples:    []string{"sqrt(16)"},
+	},
+	"cbrt": {
+		Category:    "roots",
+		Description: "Cube root; real for negative numbers",
+		Domain:      "all real x",
+		Examples:    []string{"cbrt(27)", "cbrt(-8)"},
+	},
+	"root": {
+		Category:    "roots",
+		Description: "nth root of x; odd roots of negative numbers are negative",
+		Domain:      "n ≠ 0; x ≥ 0 unless n is an odd integer",
+		Examples:    []string{"root(3, 27)", "root(3, -8)"},
+	},
+	"hypot": {
+		Category:    "arithmetic",
+		Description: "Square root of the sum of squares of the arguments",
+		Domain:      "two or more real numbers",
+		Examples:    []string{"hypot(3, 4)"},
+	},
+	"abs": {
+		Category:    "arithmetic",
+		Description: "Absolute value",
+		Domain:      "all real x",
+		Examples:    []string{"abs(-5)"},
+	},
+	"factorial": {
+		Category:    "arithmetic",
+		Description: "Factorial of a non-negative integer, the same as x!",
+		Domain:      "integers 0 ≤ x ≤ 170",
+		Examples:    []string{"factorial(5)"},
+	},
+	"gamma": {
+		Category:    "special",
+		Description: "Gamma function; Γ(n) = (n-1)! for positive integers",
+		Domain:      "x not a non-positive integer",
+		Examples:    []string{"gamma(5)", "gamma(0.5)"},
+	},
+	"lgamma": {
+		Category:    "special",
+		Description: "Natural logarithm of the absolute value of the gamma function",
+		Domain:      "x not a non-positive integer",
+		Examples:    []string{"lgamma(100)"},
+	},
+	"beta": {
+		Category:    "special",
+		Description: "Beta function Γ(a)Γ(b)/Γ(a+b)",
+		Domain:      "a and b not non-positive integers",
+		Examples:    []string{"beta(2, 3)"},
+	},
+	"digamma": {
+		Category:    "special",
+		Description: "Digamma function ψ(x), the derivative of ln Γ(x)",
+		Domain:      "x not a non-positive integer",
+		Examples:    []string{"digamma(1)"},
+	},
+	"zeta": {
+		Category:    "special",
+		Description: "Riemann zeta function",
+		Domain:      "x ≠ 1",
+		Examples:    []string{"zeta(2)", "zeta(-1)"},
+	},
+	"erf": {
+		Category:    "special",
+		Description: "Error function",
+		Domain:      "all real x",
+		Examples:    []string{"erf(1)"},
+	},
+	"erfc": {
+		Category:    "special",
+		Description: "Complementary error function, 1 - erf(x)",
+		Domain:      "all real x",
+		Examples:    []string{"erfc(1)"},
+	},
+	"erfinv": {
+		Category:    "special",
+		Description: "Inverse error function",
+		Domain:      "-1 < x < 1",
+		Examples:    []string{"erfinv(0.5)"},
+	},
+	"j0": {
+		Category:    "special",
+		Description: "Bessel function of the first kind of order 0",
+		Domain:      "all real x",
+		Examples:    []string{"j0(2.5)"},
+	},
+	"j1": {
+		Category:    "special",
+		Description: "Bessel function of the first kind of order 1",
+		Domain:      "all real x",
+		Examples:    []string{"j1(2.5)"},
+	},
+	"jn": {
+		Category:    "special",
+		Description: "Bessel function of the first kind of integer order n",
+		Domain:      "n an integer",
+		Examples:    []string{"jn(2, 2.5)"},
+	},
+	"y0": {
+		Category:    "special",
+		Description: "Bessel function of the second kind of order 0",
+		Domain:      "x > 0",
+		Examples:    []string{"y0(2.5)"},
+	},
+	"y1": {
+		Category:    "special",
+		Description: "Bessel function of the second kind of order 1",
+		Domain:      "x > 0",
+		Examples:    []string{"y1(2.5)"},
+	},
+	"yn": {
+		Category:    "special",
+		Description: "Bessel function of the second kind of integer order n",
+		Domain:      "n an integer; x > 0",
+		Examples:    []string{"yn(2, 2.5)"},
+	},
+	"mod": {
+		Category:    "arithmetic",
+		Description: "Remainder of a divided by b, with the sign of b",
+		Domain:      "b ≠ 0",
+		Examples:    []string{"mod(10, 3)", "mod(-7, 3)"},
+	},
+	"rem": {
+		Category:    "arithmetic",
+		Description: "Remainder of a divided by b, with the sign of a",
+		Domain:      "integers; b ≠ 0",
+		Examples:    []string{"rem(-7, 3)"},
+	},
+	"gcd": {
+		Category:    "number theory",
+		Description: "Greatest common divisor of the arguments",
+		Domain:      "integers",
+		Examples:    []string{"gcd(12, 18)", "gcd(12, 18, 8)"},
+	},
+	"lcm": {
+		Category:    "number theory",
+		Description: "Least common multiple of the arguments",
+		Domain:      "integers",
+		Examples:    []string{"lcm(4, 6)"},
+	},
+	"isPrime": {
+		Category:    "number theory",
+		Description: "1 if x is prime, otherwise 0",
+		Domain:      "integers",
+		Examples:    []string{"isPrime(97)"},
+	},
+	"nextPrime": {
+		Category:    "number theory",
+		Description: "Smallest prime greater than x",
+		Domain:      "integers",
+		Examples:    []string{"nextPrime(100)"},
+	},
+	"modpow": {
+		Category:    "number theory",
+		Description: "b raised to the power e modulo m; a negative e uses the modular inverse of b",
+		Domain:      "integers; m > 0",
+		Examples:    []string{"modpow(4, 13, 497)"},
+	},
+	"modinv": {
+		Category:    "number theory",
+		Description: "Modular inverse: the x in [0, m) with a·x ≡ 1 (mod m)",
+		Domain:      "integers; m > 0 and a coprime to m",
+		Examples:    []string{"modinv(3, 11)"},
+	},
+	"nCr": {
+		Category:    "combinatorics",
+		Description: "Number of ways to choose r of n items, the binomial coefficient",
+		Domain:      "integers 0 ≤ n ≤ 100000 and r ≥ 0",
+		Examples:    []string{"nCr(5, 2)"},
+	},
+	"nPr": {
+		Category:    "combinatorics",
+		Description: "Number of ordered arrangements of r of n items",
+		Domain:      "integers 0 ≤ n ≤ 100000 and r ≥ 0",
+		Examples:    []string{"nPr(5, 2)"},
+	},
+	"fib": {
+		Category:    "combinatorics",
+		Description: "nth Fibonacci number, with fib(0) = 0 and fib(1) = 1",
+		Domain:      "integers 0 ≤ n ≤ 1000000",
+		Examples:    []string{"fib(10)"},
+	},
+	"min": {
+		Category:    "arithmetic",
+		Description: "Smallest of the arguments",
+		Domain:      "one or more real numbers",
+		Examples:    []string{"min(3, 1, 2)"},
+	},
+	"max": {
+		Category:    "arithmetic",
+		Description: "Largest of the arguments",
+		Domain:      "one or more real numbers",
+		Examples:    []string{"max(3, 1, 2)"},
+	},
+	"floor": {
+		Category:    "rounding",
+		Description: "Largest integer not greater than x",
+		Domain:      "all real x",
+		Examples:    []string{"floor(2.7)"},
+	},
+	"ceil": {
+		Category:    "rounding",
+		Description: "Smallest integer not less than x",
+		Domain:      "all real x",
+		Examples:    []string{"ceil(2.1)"},
+	},
+	"round": {
+		Category:    "rounding",
+		Description: "Round to the nearest integer, or to the given number of decimal places",
+		Domain:      "digits must be an integer",
+		Examples:    []string{"round(2.5)", "round(pi, 2)"},
+	},
+	"re": {
+		Category:    "complex",
+		Description: "Real part of a complex number",
+		Domain:      "all complex z",
+		Examples:    []string{"re(3+4i)"},
+	},
+	"im": {
+		Category:    "complex",
+		Description: "Imaginary part of a complex number",
+		Domain:      "all complex z",
+		Examples:    []string{"im(3+4i)"},
+	},
+	"arg": {
+		Category:    "complex",
+		Description: "Argument of a complex number, the angle from the positive real axis",
+		Domain:      "z ≠ 0",
+		Angle:       AngleResult,
+		Examples:    []string{"arg(1+i)"},
+	},
+	"conj": {
+		Category:    "complex",
+		Description: "Complex conjugate",
+		Domain:      "all complex z",
+		Examples:    []string{"conj(3+4i)"},
+	},
+	"polar": {
+		Category:    "complex",
+		Description: "Complex number with the given modulus and argument",
+		Domain:      "real modulus and angle",
+		Angle:       AngleArgument,
+		Examples:    []string{"polar(2, 90)"},
+	},
+	"rol": {
+		Category:    "bitwise",
+		Description: "Rotate the bits of x left by n places within the width of the type",
+		Domain:      "integer mode",
+		Examples:    []string{"rol(0x81, 1)"},
+	},
+	"ror": {
+		Category:    "bitwise",
+		Description: "Rotate the bits of x right by n places within the width of the type",
+		Domain:      "integer mode",
+		Examples:    []string{"ror(0x81, 1)"},
+	},
+	"det": {
+		Category:    "matrix",
+		Description: "Determinant of a square matrix",
+		Domain:      "square matrices",
+		Examples:    []string{"det([1, 2; 3, 4])"},
+	},
+	"inv": {
+		Category:    "matrix",
+		Description: "Inverse of a square matrix",
+		Domain:      "square matrices that are not singular or ill-conditioned",
+		Examples:    []string{"inv([4, 7; 2, 6])"},
+	},
+	"transpose": {
+		Category:    "matrix",
+		Description: "Transpose of a matrix, swapping rows and columns",
+		Domain:      "all matrices",
+		Examples:    []string{"transpose([1, 2, 3; 4, 5, 6])"},
+	},
+	"rank": {
+		Category:    "matrix",
+		Description: "Rank of a matrix, the number of linearly independent rows",
+		Domain:      "all matrices",
+		Examples:    []string{"rank([1, 2; 2, 4])"},
+	},
+	"trace": {
+		Category:    "matrix",
+		Description: "Sum of the diagonal entries of a square matrix",
+		Domain:      "square matrices",
+		Examples:    []string{"trace([1, 2; 3, 4])"},
+	},
+	"cond": {
+		Category:    "matrix",
+		Description: "Condition number of a square matrix in the 1-norm",
+		Domain:      "square matrices that are not singular",
+		Examples:    []string{"cond([1, 2; 3, 4])"},
+	},
+	"solve": {
+		Category:    "matrix",
+		Description: "Solution x of the linear system A·x = b",
+		Domain:      "square A that is not singular or ill-conditioned; b with as many rows",
+		Examples:    []string{"solve([2, 1; 1, 3], [3; 5])"},
+	},
+	"identity": {
+		Category:    "matrix",
+		Description: "The n×n identity matrix",
+		Domain:      "integers 1 ≤ n ≤ 1000",
+		Examples:    []string{"identity(3)"},
+	},
+}
+
+// operatorInfo documents the operators, keyed by notation and symbol
+var operatorInfo = map[string]FunctionInfo{
+	"infix +": {
+		Description: "Addition",
+		Domain:      "all real x",
+		Examples:    []string{"2 + 3"},
+	},
+	"infix -": {
+		Description: "Subtraction",
+		Domain:      "all real x",
+		Examples:    []string{"5 - 8"},
+	},
+	"infix *": {
+		Description: "Multiplication; also written × or implied by juxtaposition, as in 2π or 3(4)",
+		Domain:      "all real x",
+		Examples:    []string{"6 * 7", "2π"},
+	},
+	"infix /": {
+		Description: "Division; also written ÷",
+		Domain:      "divisor ≠ 0",
+		Examples:    []string{"1 / 4"},
+	},
+	"infix ^": {
+		Description: "Exponentiation, grouping right to left and binding tighter than unary minus",
+		Domain:      "real result",
+		Examples:    []string{"2^10", "2^3^2", "-2^2"},
+	},
+	"prefix +": {
+		Description: "Unary plus; leaves the value unchanged",
+		Domain:      "all real x",
+		Examples:    []string{"+5"},
+	},
+	"prefix -": {
+		Description: "Negation",
+		Domain:      "all real x",
+		Examples:    []string{"-5", "-(2 + 3)"},
+	},
+	"postfix !": {
+		Description: "Factorial",
+		Domain:      "integers 0 ≤ x ≤ 170",
+		Examples:    []string{"5!"},
+	},
+	"infix &": {
+		Description: "Bitwise AND",
+		Domain:      "integer mode",
+		Examples:    []string{"0xF0 & 0x3C"},
+	},
+	"infix |": {
+		Description: "Bitwise OR",
+		Domain:      "integer mode",
+		Examples:    []string{"0xF0 | 0x0F"},
+	},
+	"infix xor": {
+		Description: "Bitwise exclusive OR; spelled as a word because ^ is exponentiation",
+		Domain:      "integer mode",
+		Examples:    []string{"0xFF xor 0x0F"},
+	},
+	"infix <<": {
+		Description: "Left shift; bits shifted past the width are lost",
+		Domain:      "integer mode; count ≥ 0",
+		Examples:    []string{"1 << 4"},
+	},
+	"infix >>": {
+		Description: "Right shift, keeping the sign of signed types",
+		Domain:      "integer mode; count ≥ 0",
+		Examples:    []string{"-16 >> 2"},
+	},
+	"prefix ~": {
+		Description: "Bitwise NOT",
+		Domain:      "integer mode",
+		Examples:    []string{"~0"},
+	},
+}
+
+// descriptionsID translates the descriptions of the built-in functions and
+// operators into Indonesian
+var descriptionsID = map[string]string{
+	"sin":       "Sinus suatu sudut",
+	"cos":       "Kosinus suatu sudut",
+	"tan":       "Tangen suatu sudut",
+	"sec":       "Sekan suatu sudut, 1/cos(x)",
+	"csc":       "Kosekan suatu sudut, 1/sin(x)",
+	"cot":       "Kotangen suatu sudut, cos(x)/sin(x)",
+	"asin":      "Invers sinus; sudut yang sinusnya x",
+	"acos":      "Invers kosinus; sudut yang kosinusnya x",
+	"atan":      "Invers tangen; sudut yang tangennya x",
+	"asec":      "Invers sekan; sudut antara 0 dan π yang sekannya x",
+	"acsc":      "Invers kosekan; sudut antara -π/2 dan π/2 yang kosekannya x",
+	"acot":      "Invers kotangen; sudut antara 0 dan π yang kotangennya x",
+	"atan2":     "Sudut titik (x, y) terhadap sumbu x positif",
+	"log":       "Logaritma basis 10, atau logaritma dengan basis pada argumen kedua",
+	"ln":        "Logaritma natural",
+	"exp":       "e dipangkatkan x",
+	"exp2":      "2 dipangkatkan x",
+	"exp10":     "10 dipangkatkan x",
+	"pow":       "x dipangkatkan y, sama dengan x^y",
+	"sinh":      "Sinus hiperbolik",
+	"cosh":      "Kosinus hiperbolik",
+	"tanh":      "Tangen hiperbolik",
+	"asinh":     "Invers sinus hiperbolik",
+	"acosh":     "Invers kosinus hiperbolik",
+	"atanh":     "Invers tangen hiperbolik",
+	"sqrt":      "Akar kuadrat",
+	"cbrt":      "Akar pangkat tiga; bernilai real untuk bilangan negatif",
+	"root":      "Akar pangkat n dari x; akar ganjil bilangan negatif bernilai negatif",
+	"hypot":     "Akar kuadrat dari jumlah kuadrat argumen",
+	"abs":       "Nilai mutlak",
+	"factorial": "Faktorial bilangan bulat tak negatif, sama dengan x!",
+	"gamma":     "Fungsi gamma; Γ(n) = (n-1)! untuk bilangan bulat positif",
+	"lgamma":    "Logaritma natural dari nilai mutlak fungsi gamma",
+	"beta":      "Fungsi beta Γ(a)Γ(b)/Γ(a+b)",
+	"digamma":   "Fungsi digamma ψ(x), turunan dari ln Γ(x)",
+	"zeta":      "Fungsi zeta Riemann",
+	"erf":       "Fungsi galat",
+	"erfc":      "Fungsi galat komplementer, 1 - erf(x)",
+	"erfinv":    "Invers fungsi galat",
+	"j0":        "Fungsi Bessel jenis pertama orde 0",
+	"j1":        "Fungsi Bessel jenis pertama orde 1",
+	"jn":        "Fungsi Bessel jenis pertama orde bilangan bulat n",
+	"y0":        "Fungsi Bessel jenis kedua orde 0",
+	"y1":        "Fungsi Bessel jenis kedua orde 1",
+	"yn":        "Fungsi Bessel jenis kedua orde bilangan bulat n",
+	"mod":       "Sisa pembagian a oleh b, bertanda sama dengan b",
+	"rem":       "Sisa pembagian a oleh b, bertanda sama dengan a",
+	"gcd":       "Faktor persekutuan terbesar (FPB) dari argumen",
+	"lcm":       "Kelipatan persekutuan terkecil (KPK) dari argumen",
+	"isPrime":   "1 jika x bilangan prima, selain itu 0",
+	"nextPrime": "Bilangan prima terkecil yang lebih besar dari x",
+	"modpow":    "b dipangkatkan e modulo m; e negatif memakai invers modular b",
+	"modinv":    "Invers modular: x dalam [0, m) dengan a·x ≡ 1 (mod m)",
+	"nCr":       "Banyak cara memilih r dari n benda, yaitu koefisien binomial",
+	"nPr":       "Banyak susunan berurutan r dari n benda",
+	"fib":       "Bilangan Fibonacci ke-n, dengan fib(0) = 0 dan fib(1) = 1",
+	"min":       "Nilai terkecil dari argumen",
+	"max":       "Nilai terbesar dari argumen",
+	"floor":     "Bilangan bulat terbesar yang tidak lebih dari x",
+	"ceil":      "Bilangan bulat terkecil yang tidak kurang dari x",
+	"round":     "Pembulatan ke bilangan bulat terdekat, atau ke jumlah angka desimal yang diberikan",
+	"re":        "Bagian real bilangan kompleks",
+	"im":        "Bagian imajiner bilangan kompleks",
+	"arg":       "Argumen bilangan kompleks, yaitu sudutnya terhadap sumbu real positif",
+	"conj":      "Konjugat bilangan kompleks",
+	"polar":     "Bilangan kompleks dengan modulus dan argumen yang diberikan",
+	"infix +":   "Penjumlahan",
+	"infix -":   "Pengurangan",
+	"infix *":   "Perkalian; dapat ditulis × atau tersirat dengan penjajaran, seperti 2π atau 3(4)",
+	"infix /":   "Pembagian; dapat ditulis ÷",
+	"infix ^":   "Perpangkatan, dikelompokkan dari kanan ke kiri dan mengikat lebih kuat daripada minus uner",
+	"prefix +":  "Plus uner; nilai tidak berubah",
+	"prefix -":  "Negasi",
+	"postfix !": "Faktorial",
+	"infix &":   "AND bitwise",
+	"infix |":   "OR bitwise",
+	"infix xor": "XOR bitwise; ditulis sebagai kata karena ^ adalah perpangkatan",
+	"infix <<":  "Geser kiri; bit yang melewati lebar tipe hilang",
+	"infix >>":  "Geser kanan, mempertahankan tanda pada tipe bertanda",
+	"prefix ~":  "NOT bitwise",
+	"rol":       "Rotasi bit x ke kiri sebanyak n posisi dalam lebar tipe",
+	"ror":       "Rotasi bit x ke kanan sebanyak n posisi dalam lebar tipe",
+	"det":       "Determinan matriks persegi",
+	"inv":       "Invers matriks persegi",
+	"transpose": "Transpos matriks, menukar baris dan kolom",
+	"rank":      "Rank matriks, banyak baris yang bebas linear",
+	"trace":     "Jumlah entri diagonal matriks persegi",
+	"cond":      "Bilangan kondisi matriks persegi dalam norma-1",
+	"solve":     "Penyelesaian x dari sistem linear A·x = b",
+	"identity":  "Matriks identitas berukuran n×n",
+}
+
+// builtinDoc returns the documentation of a built-in function or operator
+// with its translations attached
+func builtinDoc(key string, info FunctionInfo) FunctionInfo {
+	if text, ok := descriptionsID[key]; ok {
+		info.Descriptions = map[string]string{"id": text}
+	}
+	return info
+}
+
+// CatalogEntry describes a function or operator an engine supports
+type CatalogEntry struct {
+	Name     string
+	Kind     string // "function" or "operator"
+	Notation string // "infix", "prefix" or "postfix" for operators
+	Arity    Arity
+	Info     FunctionInfo
+	// Modes lists the number modes that evaluate the entry: "float",
+	// "precision", "rational", "complex" and "int". Functions without
+	// "rational" still work in rational mode by falling back to floating
+	// point. "matrix" marks functions of matrix expressions, which are
+	// evaluated in float mode.
+	Modes []string
+}
+
+// allModes is the Modes of entries every number mode evaluates
+var allModes = []string{"float", "precision", "rational", "complex", "int"}
+
+// operatorModes returns the Modes of an operator
+func operatorModes(op string) []string {
+	if bitwiseOperators[op] {
+		return []string{"int"}
+	}
+	return allModes
+}
+
+// hasIntMode reports whether a function is available in integer mode
+func hasIntMode(name string) bool {
+	_, fixed := intFunctions[name]
+	_, exact := integerFunctions[name]
+	return fixed || exact
+}
+
+// Catalog lists the operators and functions the engine evaluates, built
+// from its own tables: the operator tables of the parser, its function
+// table and the functions only available in complex or integer mode or
+// on matrices
+func (p *ExpressionParser) Catalog() []CatalogEntry {
+	var entries []CatalogEntry
+	addOperators := func(notation string, operators map[string]bool, arity Arity) {
+		for op := range operators {
+			key := notation + " " + op
+			info := builtinDoc(key, operatorInfo[key])
+			info.Category = "operator"
+			entries = append(entries, CatalogEntry{
+				Name:     op,
+				Kind:     "operator",
+				Notation: notation,
+				Arity:    arity,
+				Info:     info,
+				Modes:    operatorModes(op),
+			})
+		}
+	}
+	infix := make(map[string]bool, len(binaryPrecedence))
+	for op := range binaryPrecedence {
+		infix[op] = true
+	}
+	addOperators("infix", infix, Arity{2, 2})
+	addOperators("prefix", prefixOperators, Arity{1, 1})
+	addOperators("postfix", postfixOperators, Arity{1, 1})
+
+	for name, fn := range p.functions {
+		modes := []string{"float"}
+		if _, ok := bigFunctions[name]; ok {
+			modes = append(modes, "precision")
+		}
+		if _, ok := rationalFunctions[name]; ok {
+			modes = append(modes, "rational")
+		}
+		if _, ok := complexFunctions[name]; ok {
+			modes = append(modes, "complex")
+		}
+		if hasIntMode(name) {
+			modes = append(modes, "int")
+		}
+		entries = append(entries, CatalogEntry{Name: name, Kind: "function", Arity: fn.Arity, Info: fn.info, Modes: modes})
+	}
+	for name, fn := range complexFunctions {
+		if _, ok := p.functions[name]; ok {
+			continue
+		}
+		entries = append(entries, CatalogEntry{
+			Name:  name,
+			Kind:  "function",
+			Arity: fn.Arity,
+			Info:  builtinDoc(name, builtinInfo[name]),
+			Modes: []string{"complex"},
+		})
+	}
+
+	for name, fn := range intFunctions {
+		if _, ok := p.functions[name]; ok {
+			continue
+		}
+		entries = append(entries, CatalogEntry{
+			Name:  name,
+			Kind:  "function",
+			Arity: fn.Arity,
+			Info:  builtinDoc(name, builtinInfo[name]),
+			Modes: []string{"int"},
+		})
+	}
+
+	for name, fn := range matrixFunctions {
+		entries = append(entries, CatalogEntry{
+			Name:  name,
+			Kind:  "function",
+			Arity: fn.Arity,
+			Info:  builtinDoc(name, builtinInfo[name]),
+			Modes: []string{"matrix"},
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Kind != b.Kind {
+			return a.Kind == "operator"
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Notation < b.Notation
+	})
+	return entries
+}
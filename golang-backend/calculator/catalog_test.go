@@ -0,0 +1,158 @@
+package calculator
+
+import "testing"
+
+func TestCatalogCoversEngine(t *testing.T) {
+	p := New()
+	entries := make(map[string]CatalogEntry)
+	for _, entry := range p.Catalog() {
+		key := entry.Name
+		if entry.Kind == "operator" {
+			key = entry.Notation + " " + entry.Name
+		}
+		if _, dup := entries[key]; dup {
+			t.Errorf("%s is listed twice", key)
+		}
+		entries[key] = entry
+	}
+
+	var names []string
+	for name := range p.functions {
+		names = append(names, name)
+	}
+	for name := range complexFunctions {
+		names = append(names, name)
+	}
+	for name := range intFunctions {
+		names = append(names, name)
+	}
+	for name := range matrixFunctions {
+		names = append(names, name)
+	}
+	for op := range binaryPrecedence {
+		names = append(names, "infix "+op)
+	}
+	for op := range prefixOperators {
+		names = append(names, "prefix "+op)
+	}
+	for op := range postfixOperators {
+		names = append(names, "postfix "+op)
+	}
+	for _, name := range names {
+		entry, ok := entries[name]
+		if !ok {
+			t.Errorf("%s is missing from the catalog", name)
+			continue
+		}
+		if entry.Info.Description == "" {
+			t.Errorf("%s has no description", name)
+		}
+		if entry.Info.DescriptionIn("id") == entry.Info.Description {
+			t.Errorf("%s has no Indonesian description", name)
+		}
+		if len(entry.Modes) == 0 {
+			t.Errorf("%s lists no number modes", name)
+		}
+	}
+}
+
+func TestCatalogExamples(t *testing.T) {
+	p := New()
+	for _, entry := range p.Catalog() {
+		if entry.Kind != "function" {
+			continue
+		}
+		if len(entry.Info.Examples) == 0 {
+			t.Errorf("%s has no examples", entry.Name)
+		}
+		for _, example := range entry.Info.Examples {
+			var err error
+			switch entry.Modes[0] {
+			case "float":
+				_, err = p.Evaluate(example, EvalOptions{})
+			case "matrix":
+				_, err = p.EvaluateMatrix(example, EvalOptions{})
+			case "complex":
+				_, err = p.EvaluateComplex(example, EvalOptions{})
+			case "int":
+				_, err = p.EvaluateInt(example, EvalOptions{})
+			}
+			if err != nil {
+				t.Errorf("%s example %s: %v", entry.Name, example, err)
+			}
+		}
+	}
+}
+
+func TestCatalogEntries(t *testing.T) {
+	cases := []struct {
+		name  string
+		arity Arity
+		angle AngleUse
+		modes []string
+	}{
+		{"sin", Arity{1, 1}, AngleArgument, []string{"float", "precision", "complex"}},
+		{"asin", Arity{1, 1}, AngleResult, nil},
+		{"gamma", Arity{1, 1}, AngleNone, nil},
+		{"exp2", Arity{1, 1}, AngleNone, nil},
+		{"exp10", Arity{1, 1}, AngleNone, nil},
+		{"log", Arity{1, 2}, AngleNone, nil},
+		{"max", Arity{1, -1}, AngleNone, nil},
+		{"det", Arity{1, 1}, AngleNone, []string{"matrix"}},
+	}
+	catalog := New().Catalog()
+	for _, tc := range cases {
+		var found *CatalogEntry
+		for i := range catalog {
+			if catalog[i].Kind == "function" && catalog[i].Name == tc.name {
+				found = &catalog[i]
+			}
+		}
+		if found == nil {
+			t.Errorf("%s is missing", tc.name)
+			continue
+		}
+		if found.Arity != tc.arity || found.Info.Angle != tc.angle {
+			t.Errorf("%s: arity %v angle %v, want %v %v", tc.name, found.Arity, found.Info.Angle, tc.arity, tc.angle)
+		}
+		for _, mode := range tc.modes {
+			if !contains(found.Modes, mode) {
+				t.Errorf("%s: modes %v lack %s", tc.name, found.Modes, mode)
+			}
+		}
+	}
+
+	// Operators come first, then functions, each sorted by name
+	for i := 1; i < len(catalog); i++ {
+		a, b := catalog[i-1], catalog[i]
+		if a.Kind == b.Kind && a.Name > b.Name || a.Kind == "function" && b.Kind == "operator" {
+			t.Fatalf("%s %s sorts before %s %s", a.Kind, a.Name, b.Kind, b.Name)
+		}
+	}
+}
+
+func TestCatalogCustomFunctions(t *testing.T) {
+	reg := NewRegistry()
+	info := FunctionInfo{Category: "custom", Description: "Twice x", Descriptions: map[string]string{"id": "Dua kali x"}}
+	if err := reg.RegisterFunction("double", Arity{1, 1}, double, info); err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range New(WithFunctions(reg)).Catalog() {
+		if entry.Name == "double" {
+			if entry.Info.DescriptionIn("id") != "Dua kali x" || entry.Info.DescriptionIn("fr") != "Twice x" {
+				t.Errorf("double: descriptions %v", entry.Info)
+			}
+			return
+		}
+	}
+	t.Error("a registered function is missing from the catalog")
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
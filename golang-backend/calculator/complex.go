@@ -0,0 +1,228 @@
+package calculator
+
+import (
+	"math"
+	"math/cmplx"
+	"strconv"
+	"strings"
+)
+
+// ComplexOperations provides mathematical operations on complex numbers
+type ComplexOperations struct{}
+
+// NewComplexOperations creates a new ComplexOperations instance
+func NewComplexOperations() *ComplexOperations {
+	return &ComplexOperations{}
+}
+
+// checkComplex rejects results with infinite or NaN components
+func checkComplex(z complex128, kind error, message string) (complex128, error) {
+	if cmplx.IsInf(z) || cmplx.IsNaN(z) {
+		return 0, newError(kind, message)
+	}
+	return z, nil
+}
+
+// Divide performs complex division with zero check
+func (c *ComplexOperations) Divide(a, b complex128) (complex128, error) {
+	if b == 0 {
+		return 0, newError(ErrDivisionByZero, "division by zero")
+	}
+	return checkComplex(a/b, ErrOverflow, "complex division overflow")
+}
+
+// Power calculates a^b. Small integer exponents use repeated
+// multiplication so that results such as i^2 are exact.
+func (c *ComplexOperations) Power(a, b complex128) (complex128, error) {
+	if imag(b) == 0 && real(b) == math.Trunc(real(b)) && math.Abs(real(b)) <= 64 {
+		n := int(real(b))
+		if n < 0 && a == 0 {
+			return 0, newError(ErrDivisionByZero, "division by zero")
+		}
+
+		result := complex(1, 0)
+		for i := 0; i < absInt(n); i++ {
+			result *= a
+		}
+		if n < 0 {
+			result = 1 / result
+		}
+		return checkComplex(result, ErrOverflow, "power result too large")
+	}
+
+	if a == 0 {
+		if real(b) > 0 {
+			return 0, nil
+		}
+		return 0, newError(ErrDomain, "invalid power operation")
+	}
+	return checkComplex(cmplx.Pow(a, b), ErrDomain, "invalid power operation")
+}
+
+// absInt returns the absolute value of n
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Sqrt calculates the principal square root
+func (c *ComplexOperations) Sqrt(z complex128) complex128 {
+	return cmplx.Sqrt(z)
+}
+
+// NthRoot calculates the principal nth root
+func (c *ComplexOperations) NthRoot(n, z complex128) (complex128, error) {
+	if n == 0 {
+		return 0, newError(ErrDomain, "root degree must not be zero")
+	}
+	if z == 0 {
+		return 0, nil
+	}
+	return checkComplex(cmplx.Exp(cmplx.Log(z)/n), ErrDomain, "invalid root operation")
+}
+
+// Exp calculates e^z
+func (c *ComplexOperations) Exp(z complex128) (complex128, error) {
+	return checkComplex(cmplx.Exp(z), ErrOverflow, "exponential overflow")
+}
+
+// Ln calculates the principal natural logarithm, so ln(-1) = πi
+func (c *ComplexOperations) Ln(z complex128) (complex128, error) {
+	if z == 0 {
+		return 0, newError(ErrDomain, "natural logarithm domain error: value must not be zero")
+	}
+	return cmplx.Log(z), nil
+}
+
+// LogBase calculates the principal logarithm in the given base
+func (c *ComplexOperations) LogBase(z, base complex128) (complex128, error) {
+	if z == 0 {
+		return 0, newError(ErrDomain, "logarithm domain error: value must not be zero")
+	}
+	if base == 0 || base == 1 {
+		return 0, newError(ErrDomain, "logarithm base error: base must not be 0 or 1")
+	}
+	return cmplx.Log(z) / cmplx.Log(base), nil
+}
+
+// Sin calculates complex sine
+func (c *ComplexOperations) Sin(z complex128) (complex128, error) {
+	return checkComplex(cmplx.Sin(z), ErrOverflow, "complex sine overflow")
+}
+
+// Cos calculates complex cosine
+func (c *ComplexOperations) Cos(z complex128) (complex128, error) {
+	return checkComplex(cmplx.Cos(z), ErrOverflow, "complex cosine overflow")
+}
+
+// Tan calculates complex tangent
+func (c *ComplexOperations) Tan(z complex128) (complex128, error) {
+	return checkComplex(cmplx.Tan(z), ErrDomain, "tangent is undefined at this point")
+}
+
+// Asin calculates complex inverse sine
+func (c *ComplexOperations) Asin(z complex128) complex128 {
+	return cmplx.Asin(z)
+}
+
+// Acos calculates complex inverse cosine
+func (c *ComplexOperations) Acos(z complex128) complex128 {
+	return cmplx.Acos(z)
+}
+
+// Atan calculates complex inverse tangent
+func (c *ComplexOperations) Atan(z complex128) (complex128, error) {
+	if z == complex(0, 1) || z == complex(0, -1) {
+		return 0, newError(ErrDomain, "arctan is undefined at ±i")
+	}
+	return cmplx.Atan(z), nil
+}
+
+// Sinh calculates complex hyperbolic sine
+func (c *ComplexOperations) Sinh(z complex128) (complex128, error) {
+	return checkComplex(cmplx.Sinh(z), ErrOverflow, "hyperbolic sine overflow")
+}
+
+// Cosh calculates complex hyperbolic cosine
+func (c *ComplexOperations) Cosh(z complex128) (complex128, error) {
+	return checkComplex(cmplx.Cosh(z), ErrOverflow, "hyperbolic cosine overflow")
+}
+
+// Tanh calculates complex hyperbolic tangent
+func (c *ComplexOperations) Tanh(z complex128) (complex128, error) {
+	return checkComplex(cmplx.Tanh(z), ErrDomain, "hyperbolic tangent is undefined at this point")
+}
+
+// Asinh calculates complex inverse hyperbolic sine
+func (c *ComplexOperations) Asinh(z complex128) complex128 {
+	return cmplx.Asinh(z)
+}
+
+// Acosh calculates complex inverse hyperbolic cosine
+func (c *ComplexOperations) Acosh(z complex128) complex128 {
+	return cmplx.Acosh(z)
+}
+
+// Atanh calculates complex inverse hyperbolic tangent
+func (c *ComplexOperations) Atanh(z complex128) (complex128, error) {
+	if z == 1 || z == -1 {
+		return 0, newError(ErrDomain, "inverse hyperbolic tangent is undefined at ±1")
+	}
+	return cmplx.Atanh(z), nil
+}
+
+// Polar builds a complex number from its modulus and argument in radians
+func (c *ComplexOperations) Polar(r, theta float64) complex128 {
+	return cmplx.Rect(r, theta)
+}
+
+// complexEpsilon is the relative size below which a component of a
+// complex result is treated as rounding noise
+const complexEpsilon = 1e-15
+
+// CleanComplex zeroes a component that is negligible relative to the
+// modulus, so that e^(iπ) reports -1 rather than -1 + 1.2e-16i
+func CleanComplex(z complex128) complex128 {
+	modulus := cmplx.Abs(z)
+	re, im := real(z), imag(z)
+	if math.Abs(re) < complexEpsilon*modulus {
+		re = 0
+	}
+	if math.Abs(im) < complexEpsilon*modulus {
+		im = 0
+	}
+	return complex(re, im)
+}
+
+// FormatComplex renders z in rectangular form such as "3 - 2i"
+func FormatComplex(z complex128) string {
+	re, im := real(z), imag(z)
+	if im == 0 {
+		return strconv.FormatFloat(re, 'g', -1, 64)
+	}
+
+	imText := strconv.FormatFloat(math.Abs(im), 'g', -1, 64)
+	if imText == "1" {
+		imText = ""
+	}
+	imText += "i"
+
+	if re == 0 {
+		if im < 0 {
+			return "-" + imText
+		}
+		return imText
+	}
+
+	var b strings.Builder
+	b.WriteString(strconv.FormatFloat(re, 'g', -1, 64))
+	if im < 0 {
+		b.WriteString(" - ")
+	} else {
+		b.WriteString(" + ")
+	}
+	b.WriteString(imText)
+	return b.String()
+}
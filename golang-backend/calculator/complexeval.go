@@ -0,0 +1,303 @@
+package calculator
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// complexFunction is a built-in function evaluated with complex arguments
+type complexFunction struct {
+	Arity
+	eval func(ev *complexEvaluation, args []complex128) (complex128, error)
+}
+
+// complexUnary adapts a single-argument complex function
+func complexUnary(fn func(ev *complexEvaluation, z complex128) (complex128, error)) complexFunction {
+	return complexFunction{
+		Arity: Arity{1, 1},
+		eval: func(ev *complexEvaluation, args []complex128) (complex128, error) {
+			return fn(ev, args[0])
+		},
+	}
+}
+
+// complexFunctions is the function table used in complex mode. Angles
+// passed to or returned from trigonometric functions follow the angle mode.
+var complexFunctions = map[string]complexFunction{
+	"sin": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return ev.ops.Sin(ev.toRadians(z))
+	}),
+	"cos": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return ev.ops.Cos(ev.toRadians(z))
+	}),
+	"tan": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return ev.ops.Tan(ev.toRadians(z))
+	}),
+	"asin": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return ev.fromRadians(ev.ops.Asin(z)), nil
+	}),
+	"acos": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return ev.fromRadians(ev.ops.Acos(z)), nil
+	}),
+	"atan": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		result, err := ev.ops.Atan(z)
+		return ev.fromRadians(result), err
+	}),
+	"sinh": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return ev.ops.Sinh(z)
+	}),
+	"cosh": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return ev.ops.Cosh(z)
+	}),
+	"tanh": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return ev.ops.Tanh(z)
+	}),
+	"asinh": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return ev.ops.Asinh(z), nil
+	}),
+	"acosh": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return ev.ops.Acosh(z), nil
+	}),
+	"atanh": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return ev.ops.Atanh(z)
+	}),
+	"exp": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return ev.ops.Exp(z)
+	}),
+	"ln": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return ev.ops.Ln(z)
+	}),
+	"log": {
+		Arity: Arity{1, 2},
+		eval: func(ev *complexEvaluation, args []complex128) (complex128, error) {
+			if len(args) == 2 {
+				return ev.ops.LogBase(args[0], args[1])
+			}
+			return ev.ops.LogBase(args[0], 10)
+		},
+	},
+	"sqrt": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return ev.ops.Sqrt(z), nil
+	}),
+	"root": {
+		Arity: Arity{2, 2},
+		eval: func(ev *complexEvaluation, args []complex128) (complex128, error) {
+			return ev.ops.NthRoot(args[0], args[1])
+		},
+	},
+	"pow": {
+		Arity: Arity{2, 2},
+		eval: func(ev *complexEvaluation, args []complex128) (complex128, error) {
+			return ev.ops.Power(args[0], args[1])
+		},
+	},
+	"abs": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return complex(cmplx.Abs(z), 0), nil
+	}),
+	"re": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return complex(real(z), 0), nil
+	}),
+	"im": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return complex(imag(z), 0), nil
+	}),
+	"arg": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		if z == 0 {
+			return 0, newError(ErrDomain, "argument of zero is undefined")
+		}
+		return ev.fromRadians(complex(cmplx.Phase(z), 0)), nil
+	}),
+	"conj": complexUnary(func(ev *complexEvaluation, z complex128) (complex128, error) {
+		return cmplx.Conj(z), nil
+	}),
+	"polar": {
+		Arity: Arity{2, 2},
+		eval: func(ev *complexEvaluation, args []complex128) (complex128, error) {
+			if imag(args[0]) != 0 || imag(args[1]) != 0 {
+				return 0, newError(ErrDomain, "polar expects a real modulus and argument")
+			}
+			return ev.ops.Polar(real(args[0]), real(ev.toRadians(args[1]))), nil
+		},
+	},
+}
+
+// ComplexResult is the outcome of a complex evaluation
+type ComplexResult struct {
+	Value    complex128
+	Modulus  float64
+	Argument float64 // in the evaluation's angle mode
+}
+
+// complexEvaluation carries the state of a single complex evaluation
+type complexEvaluation struct {
+	parser *ExpressionParser
+	ops    *ComplexOperations
+	opts   EvalOptions
+}
+
+// EvaluateComplex parses and evaluates an expression over the complex
+// numbers, where i is the imaginary unit
+func (p *ExpressionParser) EvaluateComplex(expression string, opts EvalOptions) (*ComplexResult, error) {
+	prog, err := p.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	return prog.RunComplex(opts)
+}
+
+// RunComplex evaluates the program over the complex numbers
+func (prog *Program) RunComplex(opts EvalOptions) (*ComplexResult, error) {
+	opts = prog.parser.withDefaults(opts)
+	ev := &complexEvaluation{parser: prog.parser, ops: NewComplexOperations(), opts: opts}
+	value, err := ev.eval(prog.tree)
+	if err != nil {
+		return nil, err
+	}
+
+	value = CleanComplex(value)
+	value = complex(roundSignificant(real(value), opts.Digits), roundSignificant(imag(value), opts.Digits))
+	return &ComplexResult{
+		Value:    value,
+		Modulus:  cmplx.Abs(value),
+		Argument: real(ev.fromRadians(complex(cmplx.Phase(value), 0))),
+	}, nil
+}
+
+// toRadians converts an angle argument according to the angle mode
+func (ev *complexEvaluation) toRadians(z complex128) complex128 {
+	if ev.opts.AngleMode.IsDegree() {
+		return z * complex(PI/180, 0)
+	}
+	return z
+}
+
+// fromRadians converts an angle result according to the angle mode
+func (ev *complexEvaluation) fromRadians(z complex128) complex128 {
+	if ev.opts.AngleMode.IsDegree() {
+		return z * complex(180/PI, 0)
+	}
+	return z
+}
+
+// eval walks an expression tree and computes its complex value
+func (ev *complexEvaluation) eval(node Node) (complex128, error) {
+	switch n := node.(type) {
+	case *NumberNode:
+		if math.IsInf(n.Value, 0) {
+			return 0, errorAt(ErrOverflow, n.span, "number out of range '%s'", n.Text)
+		}
+		return complex(n.Value, 0), nil
+
+	case *IdentNode:
+		if value, ok := ev.opts.lookup(n.Name); ok {
+			return complex(value, 0), nil
+		}
+		if n.Name == "i" {
+			return complex(0, 1), nil
+		}
+		if value, ok := ev.parser.constants[n.Name]; ok {
+			return complex(value, 0), nil
+		}
+		return 0, errorAt(ErrUnknownVariable, n.span, "unknown identifier '%s'", n.Name)
+
+	case *UnaryNode:
+		operand, err := ev.eval(n.Operand)
+		if err != nil {
+			return 0, err
+		}
+		if bitwiseOperators[n.Op] {
+			return 0, unsupportedOperator(n.Op, n.span)
+		}
+		if n.Op == "-" {
+			// Subtract from zero rather than negate so that -4 has a +0
+			// imaginary part and sqrt(-4) lands on 2i, not -2i
+			return 0 - operand, nil
+		}
+		return operand, nil
+
+	case *PostfixNode:
+		operand, err := ev.eval(n.Operand)
+		if err != nil {
+			return 0, err
+		}
+		if imag(operand) != 0 {
+			return 0, errorAt(ErrDomain, n.span, "factorial error: factorial only defined for real integers")
+		}
+		result, err := ev.parser.basic.Factorial(real(operand))
+		if err != nil {
+			return 0, locate(err, n.span, "factorial error: ")
+		}
+		return complex(result, 0), nil
+
+	case *BinaryNode:
+		return ev.evalBinary(n)
+
+	case *CallNode:
+		return ev.evalCall(n)
+	}
+
+	return 0, unsupportedNode(node, "complex mode")
+}
+
+// evalBinary evaluates both operands and applies an infix operator
+func (ev *complexEvaluation) evalBinary(n *BinaryNode) (complex128, error) {
+	left, err := ev.eval(n.Left)
+	if err != nil {
+		return 0, err
+	}
+	right, err := ev.eval(n.Right)
+	if err != nil {
+		return 0, err
+	}
+
+	var result complex128
+	switch n.Op {
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	case "/":
+		result, err = ev.ops.Divide(left, right)
+	case "^":
+		result, err = ev.ops.Power(left, right)
+	default:
+		return 0, unsupportedOperator(n.Op, n.span)
+	}
+	if err != nil {
+		return 0, locate(err, n.span, "")
+	}
+	return result, nil
+}
+
+// evalCall evaluates a function call
+func (ev *complexEvaluation) evalCall(n *CallNode) (complex128, error) {
+	fn, ok := complexFunctions[n.Name]
+	if !ok {
+		if _, known := ev.parser.functions[n.Name]; known {
+			return 0, errorAt(ErrUnsupported, n.span, "function %s is not supported in complex mode", n.Name)
+		}
+		if _, user := ev.opts.userFunction(n.Name); user {
+			return 0, errorAt(ErrUnsupported, n.span, "user-defined function %s is not supported in complex mode", n.Name)
+		}
+		return 0, errorAt(ErrUnknownFunction, n.span, "unknown function '%s'", n.Name)
+	}
+	if err := fn.checkArity(n.Name, len(n.Args)); err != nil {
+		return 0, locate(err, n.span, "")
+	}
+
+	args := make([]complex128, len(n.Args))
+	for i, argNode := range n.Args {
+		arg, err := ev.eval(argNode)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = arg
+	}
+
+	result, err := fn.eval(ev, args)
+	if err != nil {
+		return 0, locate(err, n.span, "error in "+n.Name+" function: ")
+	}
+	return result, nil
+}
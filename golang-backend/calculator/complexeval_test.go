@@ -0,0 +1,97 @@
+package calculator
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestEvaluateComplex(t *testing.T) {
+	cases := []struct {
+		expression string
+		mode       AngleMode
+		want       complex128
+	}{
+		{"sqrt(-4)", AngleRadian, 2i},
+		{"ln(-1)", AngleRadian, complex(0, math.Pi)},
+		{"e^(i*pi)", AngleRadian, -1},
+		{"cos(180) + i*sin(180)", AngleDegree, -1},
+		{"i^2", AngleRadian, -1},
+		{"(1 + 2i)(3 - i)", AngleRadian, 5 + 5i},
+		{"(1 + i)/(1 - i)", AngleRadian, 1i},
+		{"2i", AngleRadian, 2i},
+		{"re(3 - 4i) + im(3 - 4i)", AngleRadian, -1},
+		{"abs(3 + 4i)", AngleRadian, 5},
+		{"conj(1 + 2i)", AngleRadian, 1 - 2i},
+		{"arg(i)", AngleRadian, math.Pi / 2},
+		{"arg(i)", AngleDegree, 90},
+		{"polar(2, 90)", AngleDegree, 2i},
+		{"polar(1, pi)", AngleRadian, -1},
+		{"sin(i)", AngleRadian, complex(0, math.Sinh(1))},
+		{"cosh(i*pi)", AngleRadian, -1},
+		{"asin(2)", AngleRadian, cmplx.Asin(2)},
+		{"acos(2)", AngleDegree, cmplx.Acos(2) * 180 / math.Pi},
+		{"i^i", AngleRadian, complex(math.Exp(-math.Pi/2), 0)},
+		{"log(-100)", AngleRadian, complex(2, math.Pi/math.Ln10)},
+		{"root(3, -8)", AngleRadian, cmplx.Pow(-8, 1.0/3)},
+		{"pow(2i, 2)", AngleRadian, -4},
+	}
+	for _, tc := range cases {
+		result, err := New().EvaluateComplex(tc.expression, EvalOptions{AngleMode: tc.mode})
+		if err != nil {
+			t.Errorf("%s: %v", tc.expression, err)
+			continue
+		}
+		if cmplx.Abs(result.Value-tc.want) > 1e-12*math.Max(1, cmplx.Abs(tc.want)) {
+			t.Errorf("%s = %v, want %v", tc.expression, result.Value, tc.want)
+		}
+	}
+}
+
+func TestEvaluateComplexPolar(t *testing.T) {
+	result, err := New().EvaluateComplex("-1 - i", EvalOptions{AngleMode: AngleDegree})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.Modulus-math.Sqrt2) > 1e-15 || math.Abs(result.Argument+135) > 1e-12 {
+		t.Errorf("-1 - i: modulus %v, argument %v; want √2, -135", result.Modulus, result.Argument)
+	}
+}
+
+func TestEvaluateComplexErrors(t *testing.T) {
+	cases := []struct {
+		expression string
+		kind       error
+	}{
+		{"1/0", ErrDivisionByZero},
+		{"ln(0)", ErrDomain},
+		{"i & 1", ErrUnsupported},
+		{"gamma(i)", ErrUnsupported},
+		{"re(1, 2)", ErrArgumentCount},
+	}
+	for _, tc := range cases {
+		if _, err := New().EvaluateComplex(tc.expression, EvalOptions{}); !errors.Is(err, tc.kind) {
+			t.Errorf("%s: error %v, want %v", tc.expression, err, tc.kind)
+		}
+	}
+}
+
+func TestFormatComplex(t *testing.T) {
+	cases := []struct {
+		z    complex128
+		want string
+	}{
+		{3 - 2i, "3 - 2i"},
+		{1 + 1i, "1 + i"},
+		{-1i, "-i"},
+		{2.5i, "2.5i"},
+		{-4, "-4"},
+		{CleanComplex(cmplx.Exp(complex(0, math.Pi))), "-1"},
+	}
+	for _, tc := range cases {
+		if got := FormatComplex(tc.z); got != tc.want {
+			t.Errorf("FormatComplex(%v) = %s, want %s", tc.z, got, tc.want)
+		}
+	}
+}
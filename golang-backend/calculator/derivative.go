@@ -0,0 +1,801 @@
+package calculator
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// differentiator builds the derivative of expression trees with respect to
+// one variable. Trigonometric functions follow the angle mode, so in
+// degrees d/dx sin(x) is cos(x)·π/180.
+type differentiator struct {
+	parser   *ExpressionParser
+	variable string
+	degrees  bool
+}
+
+// derivativeRule returns the derivative of a call to a function with the
+// given arguments
+type derivativeRule func(d *differentiator, args []Node) (Node, error)
+
+// chain adapts the derivative f' of a one-argument function to the chain
+// rule, f'(u)·u'
+func chain(outer func(d *differentiator, u Node) Node) derivativeRule {
+	return func(d *differentiator, args []Node) (Node, error) {
+		du, err := d.diff(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return mul(outer(d, args[0]), du), nil
+	}
+}
+
+// derivativeRules lists the derivatives of the built-in functions. Those
+// missing, such as min or zeta, cannot be written in closed form with the
+// functions available; noDerivativeReason says why.
+var derivativeRules map[string]derivativeRule
+
+func init() {
+	derivativeRules = map[string]derivativeRule{
+		"sin": chain(func(d *differentiator, u Node) Node {
+			return mul(d.angleIn(), call("cos", u))
+		}),
+		"cos": chain(func(d *differentiator, u Node) Node {
+			return neg(mul(d.angleIn(), call("sin", u)))
+		}),
+		"tan": chain(func(d *differentiator, u Node) Node {
+			return mul(d.angleIn(), pow(call("sec", u), integer(2)))
+		}),
+		"sec": chain(func(d *differentiator, u Node) Node {
+			return mul(d.angleIn(), mul(call("sec", u), call("tan", u)))
+		}),
+		"csc": chain(func(d *differentiator, u Node) Node {
+			return neg(mul(d.angleIn(), mul(call("csc", u), call("cot", u))))
+		}),
+		"cot": chain(func(d *differentiator, u Node) Node {
+			return neg(mul(d.angleIn(), pow(call("csc", u), integer(2))))
+		}),
+		"asin": chain(func(d *differentiator, u Node) Node {
+			return div(d.angleOut(), call("sqrt", sub(integer(1), pow(u, integer(2)))))
+		}),
+		"acos": chain(func(d *differentiator, u Node) Node {
+			return neg(div(d.angleOut(), call("sqrt", sub(integer(1), pow(u, integer(2))))))
+		}),
+		"atan": chain(func(d *differentiator, u Node) Node {
+			return div(d.angleOut(), add(integer(1), pow(u, integer(2))))
+		}),
+		"asec": chain(func(d *differentiator, u Node) Node {
+			return div(d.angleOut(), mul(call("abs", u), call("sqrt", sub(pow(u, integer(2)), integer(1)))))
+		}),
+		"acsc": chain(func(d *differentiator, u Node) Node {
+			return neg(div(d.angleOut(), mul(call("abs", u), call("sqrt", sub(pow(u, integer(2)), integer(1))))))
+		}),
+		"acot": chain(func(d *differentiator, u Node) Node {
+			return neg(div(d.angleOut(), add(integer(1), pow(u, integer(2)))))
+		}),
+		"atan2": func(d *differentiator, args []Node) (Node, error) {
+			y, x := args[0], args[1]
+			dy, dx, err := d.diff2(y, x)
+			if err != nil {
+				return nil, err
+			}
+			num := sub(mul(x, dy), mul(y, dx))
+			return mul(d.angleOut(), div(num, add(pow(x, integer(2)), pow(y, integer(2))))), nil
+		},
+		"sinh": chain(func(d *differentiator, u Node) Node {
+			return call("cosh", u)
+		}),
+		"cosh": chain(func(d *differentiator, u Node) Node {
+			return call("sinh", u)
+		}),
+		"tanh": chain(func(d *differentiator, u Node) Node {
+			return div(integer(1), pow(call("cosh", u), integer(2)))
+		}),
+		"asinh": chain(func(d *differentiator, u Node) Node {
+			return div(integer(1), call("sqrt", add(pow(u, integer(2)), integer(1))))
+		}),
+		"acosh": chain(func(d *differentiator, u Node) Node {
+			return div(integer(1), call("sqrt", sub(pow(u, integer(2)), integer(1))))
+		}),
+		"atanh": chain(func(d *differentiator, u Node) Node {
+			return div(integer(1), sub(integer(1), pow(u, integer(2))))
+		}),
+		"log": func(d *differentiator, args []Node) (Node, error) {
+			if len(args) == 2 {
+				// log(x, b) = ln(x)/ln(b), with the base free to vary too
+				return d.diff(div(call("ln", args[0]), call("ln", args[1])))
+			}
+			return chain(func(d *differentiator, u Node) Node {
+				return div(integer(1), mul(u, call("ln", integer(10))))
+			})(d, args)
+		},
+		"ln": chain(func(d *differentiator, u Node) Node {
+			return div(integer(1), u)
+		}),
+		"exp": chain(func(d *differentiator, u Node) Node {
+			return call("exp", u)
+		}),
+		"exp2": chain(func(d *differentiator, u Node) Node {
+			return mul(call("exp2", u), call("ln", integer(2)))
+		}),
+		"exp10": chain(func(d *differentiator, u Node) Node {
+			return mul(call("exp10", u), call("ln", integer(10)))
+		}),
+		"pow": func(d *differentiator, args []Node) (Node, error) {
+			return d.diff(&BinaryNode{Op: "^", Left: args[0], Right: args[1]})
+		},
+		"sqrt": chain(func(d *differentiator, u Node) Node {
+			return div(integer(1), mul(integer(2), call("sqrt", u)))
+		}),
+		"cbrt": chain(func(d *differentiator, u Node) Node {
+			return div(integer(1), mul(integer(3), pow(call("cbrt", u), integer(2))))
+		}),
+		"root": func(d *differentiator, args []Node) (Node, error) {
+			return d.diff(pow(args[1], div(integer(1), args[0])))
+		},
+		"hypot": func(d *differentiator, args []Node) (Node, error) {
+			var sum Node = integer(0)
+			for _, arg := range args {
+				darg, err := d.diff(arg)
+				if err != nil {
+					return nil, err
+				}
+				sum = add(sum, mul(arg, darg))
+			}
+			return div(sum, call("hypot", args...)), nil
+		},
+		"abs": chain(func(d *differentiator, u Node) Node {
+			return div(u, call("abs", u))
+		}),
+		"factorial": chain(func(d *differentiator, u Node) Node {
+			v := add(u, integer(1))
+			return mul(call("gamma", v), call("digamma", v))
+		}),
+		"gamma": chain(func(d *differentiator, u Node) Node {
+			return mul(call("gamma", u), call("digamma", u))
+		}),
+		"lgamma": chain(func(d *differentiator, u Node) Node {
+			return call("digamma", u)
+		}),
+		"beta": func(d *differentiator, args []Node) (Node, error) {
+			a, b := args[0], args[1]
+			da, db, err := d.diff2(a, b)
+			if err != nil {
+				return nil, err
+			}
+			psiSum := call("digamma", add(a, b))
+			inner := add(
+				mul(sub(call("digamma", a), psiSum), da),
+				mul(sub(call("digamma", b), psiSum), db),
+			)
+			return mul(call("beta", a, b), inner), nil
+		},
+		"erf": chain(func(d *differentiator, u Node) Node {
+			return div(mul(integer(2), call("exp", neg(pow(u, integer(2))))), call("sqrt", ident("pi")))
+		}),
+		"erfc": chain(func(d *differentiator, u Node) Node {
+			return neg(div(mul(integer(2), call("exp", neg(pow(u, integer(2))))), call("sqrt", ident("pi"))))
+		}),
+		"erfinv": chain(func(d *differentiator, u Node) Node {
+			return div(mul(call("sqrt", ident("pi")), call("exp", pow(call("erfinv", u), integer(2)))), integer(2))
+		}),
+		"j0": chain(func(d *differentiator, u Node) Node {
+			return neg(call("j1", u))
+		}),
+		"j1": chain(func(d *differentiator, u Node) Node {
+			return sub(call("j0", u), div(call("j1", u), u))
+		}),
+		"jn": besselRule("jn"),
+		"y0": chain(func(d *differentiator, u Node) Node {
+			return neg(call("y1", u))
+		}),
+		"y1": chain(func(d *differentiator, u Node) Node {
+			return sub(call("y0", u), div(call("y1", u), u))
+		}),
+		"yn": besselRule("yn"),
+		"mod": func(d *differentiator, args []Node) (Node, error) {
+			// mod(a, b) = a - b·floor(a/b), and floor is piecewise constant
+			da, db, err := d.diff2(args[0], args[1])
+			if err != nil {
+				return nil, err
+			}
+			return sub(da, mul(db, call("floor", div(args[0], args[1])))), nil
+		},
+		"floor": piecewiseConstant,
+		"ceil":  piecewiseConstant,
+		"round": piecewiseConstant,
+	}
+}
+
+// noDerivativeReason explains why a function has no derivative rule
+func noDerivativeReason(name string) string {
+	if name == "min" || name == "max" {
+		return "not differentiable where two arguments are equal"
+	}
+	if _, ok := integerFunctions[name]; ok {
+		return "defined only on integers"
+	}
+	return "no closed-form derivative with the available functions"
+}
+
+// piecewiseConstant is the derivative of a step function away from its
+// steps
+func piecewiseConstant(d *differentiator, args []Node) (Node, error) {
+	return integer(0), nil
+}
+
+// besselRule differentiates a Bessel function of integer order n using
+// 2·Z'(n, x) = Z(n-1, x) - Z(n+1, x)
+func besselRule(name string) derivativeRule {
+	return func(d *differentiator, args []Node) (Node, error) {
+		n, x := args[0], args[1]
+		if d.depends(n) {
+			return nil, errorAt(ErrUnsupported, n.Span(), "cannot differentiate %s with respect to its order", name)
+		}
+		dx, err := d.diff(x)
+		if err != nil {
+			return nil, err
+		}
+		lower := call(name, sub(n, integer(1)), x)
+		upper := call(name, add(n, integer(1)), x)
+		return mul(div(sub(lower, upper), integer(2)), dx), nil
+	}
+}
+
+// Derivative differentiates an expression with respect to variable and
+// returns the simplified result as a program. In degree mode the
+// trigonometric functions are differentiated as functions of degrees.
+func (p *ExpressionParser) Derivative(expression, variable string, opts EvalOptions) (*Program, error) {
+	tree, err := Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckName(variable); err != nil {
+		return nil, err
+	}
+	if err := p.checkCalls(tree); err != nil {
+		return nil, err
+	}
+	opts = p.withDefaults(opts)
+
+	d := &differentiator{parser: p, variable: variable, degrees: opts.AngleMode.IsDegree()}
+	result, err := d.diff(tree)
+	if err != nil {
+		return nil, err
+	}
+	// Reparse the printed form so the program's spans refer to its source
+	return p.Compile(FormatExpression(result))
+}
+
+// checkCalls reports the first call to an unknown function or with the
+// wrong number of arguments, which differentiation would otherwise skip
+// when the call does not involve the variable
+func (p *ExpressionParser) checkCalls(tree Node) error {
+	var err error
+	Walk(tree, func(node Node) {
+		n, ok := node.(*CallNode)
+		if !ok || err != nil {
+			return
+		}
+		fn, ok := p.functions[n.Name]
+		if !ok {
+			err = errorAt(ErrUnknownFunction, n.span, "unknown function '%s'", n.Name)
+			return
+		}
+		if arityErr := fn.checkArity(n.Name, len(n.Args)); arityErr != nil {
+			err = locate(arityErr, n.span, "")
+		}
+	})
+	return err
+}
+
+// depends reports whether node refers to the variable
+func (d *differentiator) depends(node Node) bool {
+	found := false
+	Walk(node, func(n Node) {
+		if ident, ok := n.(*IdentNode); ok && ident.Name == d.variable {
+			found = true
+		}
+	})
+	return found
+}
+
+// angleIn is the factor the chain rule adds for an angle argument
+func (d *differentiator) angleIn() Node {
+	if d.degrees {
+		return div(ident("pi"), integer(180))
+	}
+	return integer(1)
+}
+
+// angleOut is the factor that converts an angle result from radians
+func (d *differentiator) angleOut() Node {
+	if d.degrees {
+		return div(integer(180), ident("pi"))
+	}
+	return integer(1)
+}
+
+// diff2 differentiates two nodes
+func (d *differentiator) diff2(a, b Node) (Node, Node, error) {
+	da, err := d.diff(a)
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := d.diff(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return da, db, nil
+}
+
+// diff returns the derivative of node
+func (d *differentiator) diff(node Node) (Node, error) {
+	if !d.depends(node) {
+		return integer(0), nil
+	}
+
+	switch n := node.(type) {
+	case *IdentNode:
+		return integer(1), nil
+
+	case *UnaryNode:
+		if bitwiseOperators[n.Op] {
+			return nil, unsupportedOperator(n.Op, n.span)
+		}
+		du, err := d.diff(n.Operand)
+		if err != nil {
+			return nil, err
+		}
+		if n.Op == "-" {
+			return neg(du), nil
+		}
+		return du, nil
+
+	case *PostfixNode:
+		return derivativeRules["factorial"](d, []Node{n.Operand})
+
+	case *BinaryNode:
+		return d.diffBinary(n)
+
+	case *CallNode:
+		rule, ok := derivativeRules[n.Name]
+		if !ok {
+			return nil, errorAt(ErrUnsupported, n.span, "cannot differentiate %s (%s)", n.Name, noDerivativeReason(n.Name))
+		}
+		return rule(d, n.Args)
+	}
+
+	return nil, unsupportedNode(node, "derivatives")
+}
+
+// diffBinary applies the sum, product, quotient and power rules
+func (d *differentiator) diffBinary(n *BinaryNode) (Node, error) {
+	u, v := n.Left, n.Right
+	du, dv, err := d.diff2(u, v)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Op {
+	case "+":
+		return add(du, dv), nil
+	case "-":
+		return sub(du, dv), nil
+	case "*":
+		return add(mul(du, v), mul(u, dv)), nil
+	case "/":
+		if !d.depends(v) {
+			return div(du, v), nil
+		}
+		return div(sub(mul(du, v), mul(u, dv)), pow(v, integer(2))), nil
+	case "^":
+		switch {
+		case !d.depends(v):
+			// v·u^(v-1)·u'
+			return mul(mul(v, pow(u, sub(v, integer(1)))), du), nil
+		case !d.depends(u):
+			// u^v·ln(u)·v'
+			return mul(mul(pow(u, v), call("ln", u)), dv), nil
+		}
+		// u^v·(v'·ln(u) + v·u'/u)
+		return mul(pow(u, v), add(mul(dv, call("ln", u)), div(mul(v, du), u))), nil
+	}
+
+	return nil, unsupportedOperator(n.Op, n.span)
+}
+
+// The constructors below build expression trees, folding constants and
+// dropping identities as they go so that derivatives come out simplified.
+// Every node is split into a rational coefficient and the rest, so 2*x,
+// -x/3 and 4*x/6 combine and like terms add up.
+
+func ident(name string) Node {
+	return &IdentNode{Name: name}
+}
+
+func integer(n int64) Node {
+	return number(big.NewRat(n, 1))
+}
+
+func call(name string, args ...Node) Node {
+	if arg, ok := args[0].(*IdentNode); ok && name == "ln" && arg.Name == "e" {
+		return integer(1)
+	}
+	return &CallNode{Name: name, Args: args}
+}
+
+func operation(op string, left, right Node) Node {
+	return &BinaryNode{Op: op, Left: left, Right: right}
+}
+
+// number writes a rational as a literal: an integer, a terminating
+// decimal, or a quotient of integers
+func number(r *big.Rat) Node {
+	abs := new(big.Rat).Abs(r)
+	var result Node
+	if text, ok := decimalText(abs); ok {
+		result = &NumberNode{Text: text}
+	} else {
+		result = operation("/", &NumberNode{Text: abs.Num().String()}, &NumberNode{Text: abs.Denom().String()})
+	}
+	if r.Sign() < 0 {
+		return &UnaryNode{Op: "-", Operand: result}
+	}
+	return result
+}
+
+// decimalText writes a non-negative rational as a decimal when its
+// expansion terminates
+func decimalText(r *big.Rat) (string, bool) {
+	if r.IsInt() {
+		return r.Num().String(), true
+	}
+	den := new(big.Int).Set(r.Denom())
+	places := 0
+	for _, p := range []int64{2, 5} {
+		factor, rem := big.NewInt(p), new(big.Int)
+		for {
+			q, m := new(big.Int).QuoRem(den, factor, rem)
+			if m.Sign() != 0 {
+				break
+			}
+			den = q
+			places++
+		}
+	}
+	if den.Cmp(big.NewInt(1)) != 0 {
+		return "", false
+	}
+	return strings.TrimRight(r.FloatString(places), "0"), true
+}
+
+// constantValue returns the value of a node that is a rational constant
+func constantValue(node Node) (*big.Rat, bool) {
+	switch n := node.(type) {
+	case *NumberNode:
+		r, err := parseRationalLiteral(n.Text)
+		return r, err == nil
+	case *UnaryNode:
+		if r, ok := constantValue(n.Operand); ok && n.Op == "-" {
+			return r.Neg(r), true
+		}
+	case *BinaryNode:
+		if n.Op != "/" {
+			break
+		}
+		a, okA := constantValue(n.Left)
+		b, okB := constantValue(n.Right)
+		if okA && okB && b.Sign() != 0 {
+			return a.Quo(a, b), true
+		}
+	}
+	return nil, false
+}
+
+// split separates a node into a rational coefficient and the rest of the
+// product, which is nil for a constant
+func split(node Node) (*big.Rat, Node) {
+	if r, ok := constantValue(node); ok {
+		return r, nil
+	}
+	switch n := node.(type) {
+	case *UnaryNode:
+		if n.Op == "-" {
+			c, rest := split(n.Operand)
+			return c.Neg(c), rest
+		}
+	case *BinaryNode:
+		switch n.Op {
+		case "*":
+			ca, ra := split(n.Left)
+			cb, rb := split(n.Right)
+			return ca.Mul(ca, cb), mulRest(ra, rb)
+		case "/":
+			ca, ra := split(n.Left)
+			cb, rb := split(n.Right)
+			if cb.Sign() != 0 {
+				return ca.Quo(ca, cb), divRest(ra, rb)
+			}
+		}
+	}
+	return big.NewRat(1, 1), node
+}
+
+// build multiplies rest by a rational coefficient, writing c·x as c*x,
+// x/q or p*x/q
+func build(c *big.Rat, rest Node) Node {
+	if rest == nil || c.Sign() == 0 {
+		return number(c)
+	}
+	p, q := new(big.Int).Abs(c.Num()), c.Denom()
+	one := big.NewInt(1)
+
+	var result Node
+	if r, ok := rest.(*BinaryNode); ok && r.Op == "/" && isOne(r.Left) {
+		// c/x rather than c*1/x
+		den := r.Right
+		if q.Cmp(one) != 0 {
+			den = operation("*", &NumberNode{Text: q.String()}, den)
+		}
+		result = operation("/", &NumberNode{Text: p.String()}, den)
+	} else {
+		result = rest
+		if p.Cmp(one) != 0 {
+			result = operation("*", &NumberNode{Text: p.String()}, result)
+		}
+		if q.Cmp(one) != 0 {
+			result = operation("/", result, &NumberNode{Text: q.String()})
+		}
+	}
+	if c.Sign() < 0 {
+		return &UnaryNode{Op: "-", Operand: result}
+	}
+	return result
+}
+
+func isOne(node Node) bool {
+	r, ok := constantValue(node)
+	return ok && r.Cmp(big.NewRat(1, 1)) == 0
+}
+
+// same reports whether two trees are written identically
+func same(a, b Node) bool {
+	return FormatExpression(a) == FormatExpression(b)
+}
+
+// factor is one base of a product raised to a rational exponent, negative
+// for a divisor
+type factor struct {
+	base     Node
+	exponent *big.Rat
+}
+
+// factors flattens the non-constant part of a product into its factors.
+// A product raised to an integer power is distributed, so (x*y)^2 gives
+// x^2 and y^2.
+func factors(node Node) []factor {
+	if r, ok := constantValue(node); ok && r.Cmp(big.NewRat(1, 1)) == 0 {
+		return nil
+	}
+	n, ok := node.(*BinaryNode)
+	if !ok {
+		return []factor{{node, big.NewRat(1, 1)}}
+	}
+	switch n.Op {
+	case "*":
+		return append(factors(n.Left), factors(n.Right)...)
+	case "/":
+		return append(factors(n.Left), scaleFactors(factors(n.Right), big.NewRat(-1, 1))...)
+	case "^":
+		k, ok := constantValue(n.Right)
+		if !ok {
+			break
+		}
+		if inner, ok := n.Left.(*BinaryNode); ok && (inner.Op == "*" || inner.Op == "/") && k.IsInt() {
+			return scaleFactors(factors(inner), k)
+		}
+		return []factor{{n.Left, k}}
+	}
+	return []factor{{node, big.NewRat(1, 1)}}
+}
+
+// scaleFactors multiplies the exponent of every factor by k
+func scaleFactors(fs []factor, k *big.Rat) []factor {
+	for i := range fs {
+		fs[i].exponent = new(big.Rat).Mul(fs[i].exponent, k)
+	}
+	return fs
+}
+
+// product multiplies factors, adding the exponents of equal bases so that
+// common factors cancel. Factors keep the order they first appear in, and
+// the result is nil when nothing but constants remain.
+func product(fs []factor) Node {
+	var merged []factor
+	for _, f := range fs {
+		found := false
+		for i := range merged {
+			if same(merged[i].base, f.base) {
+				merged[i].exponent.Add(merged[i].exponent, f.exponent)
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, factor{f.base, new(big.Rat).Set(f.exponent)})
+		}
+	}
+
+	var num, den Node
+	for _, f := range merged {
+		switch f.exponent.Sign() {
+		case 1:
+			num = appendFactor(num, pow(f.base, number(f.exponent)))
+		case -1:
+			den = appendFactor(den, pow(f.base, number(new(big.Rat).Neg(f.exponent))))
+		}
+	}
+	switch {
+	case den == nil:
+		return num
+	case num == nil:
+		return operation("/", integer(1), den)
+	}
+	return operation("/", num, den)
+}
+
+func appendFactor(product, f Node) Node {
+	if product == nil {
+		return f
+	}
+	return operation("*", product, f)
+}
+
+// canonical writes the non-constant part of a product so that products
+// of the same factors in any order are written the same way
+func canonical(node Node) string {
+	fs := factors(product(factors(node)))
+	keys := make([]string, len(fs))
+	for i, f := range fs {
+		keys[i] = FormatExpression(f.base) + "^" + f.exponent.RatString()
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "*")
+}
+
+// term is one summand of a sum, split into coefficient and rest
+type term struct {
+	coef *big.Rat
+	rest Node
+}
+
+// terms flattens a sum or difference into its summands
+func terms(node Node) []term {
+	if n, ok := node.(*BinaryNode); ok && (n.Op == "+" || n.Op == "-") {
+		result := terms(n.Left)
+		for _, t := range terms(n.Right) {
+			if n.Op == "-" {
+				t.coef.Neg(t.coef)
+			}
+			result = append(result, t)
+		}
+		return result
+	}
+	c, rest := split(node)
+	return []term{{c, rest}}
+}
+
+// add sums two nodes, collecting like terms and writing negative terms
+// as subtractions
+func add(a, b Node) Node {
+	var sum []term
+	for _, t := range append(terms(a), terms(b)...) {
+		merged := false
+		for i := range sum {
+			if (sum[i].rest == nil && t.rest == nil) || (sum[i].rest != nil && t.rest != nil && canonical(sum[i].rest) == canonical(t.rest)) {
+				sum[i].coef.Add(sum[i].coef, t.coef)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			sum = append(sum, term{new(big.Rat).Set(t.coef), t.rest})
+		}
+	}
+
+	var result Node
+	for _, t := range sum {
+		switch {
+		case t.coef.Sign() == 0:
+			continue
+		case result == nil:
+			result = build(t.coef, t.rest)
+		case t.coef.Sign() < 0:
+			result = operation("-", result, build(new(big.Rat).Neg(t.coef), t.rest))
+		default:
+			result = operation("+", result, build(t.coef, t.rest))
+		}
+	}
+	if result == nil {
+		return integer(0)
+	}
+	return result
+}
+
+func sub(a, b Node) Node {
+	return add(a, neg(b))
+}
+
+func neg(a Node) Node {
+	c, rest := split(a)
+	return build(c.Neg(c), rest)
+}
+
+func mul(a, b Node) Node {
+	ca, ra := split(a)
+	cb, rb := split(b)
+	return build(ca.Mul(ca, cb), mulRest(ra, rb))
+}
+
+// mulRest multiplies the non-constant parts of two products, either of
+// which may be nil
+func mulRest(a, b Node) Node {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	return product(append(factors(a), factors(b)...))
+}
+
+func div(a, b Node) Node {
+	ca, ra := split(a)
+	cb, rb := split(b)
+	if cb.Sign() == 0 {
+		// Leave division by zero for evaluation to report
+		return operation("/", a, b)
+	}
+	return build(ca.Quo(ca, cb), divRest(ra, rb))
+}
+
+// divRest divides the non-constant parts of two products, either of which
+// may be nil
+func divRest(a, b Node) Node {
+	if b == nil {
+		return a
+	}
+	var fs []factor
+	if a != nil {
+		fs = factors(a)
+	}
+	return product(append(fs, scaleFactors(factors(b), big.NewRat(-1, 1))...))
+}
+
+func pow(base, exponent Node) Node {
+	k, constExp := constantValue(exponent)
+	b, constBase := constantValue(base)
+	switch {
+	case constExp && k.Sign() == 0:
+		return integer(1)
+	case constExp && k.Cmp(big.NewRat(1, 1)) == 0:
+		return base
+	case constBase && b.Cmp(big.NewRat(1, 1)) == 0:
+		return integer(1)
+	case constBase && constExp && k.IsInt() && k.Num().IsInt64() && k.Num().Int64() <= 64 && k.Num().Int64() >= -64 && b.Sign() != 0:
+		result, err := ratPow(b, k.Num().Int64())
+		if err == nil {
+			return number(result)
+		}
+	case constExp && k.Sign() < 0:
+		// x^-2 is written 1/x^2
+		return div(integer(1), pow(base, number(k.Neg(k))))
+	}
+	if n, ok := base.(*BinaryNode); ok && n.Op == "^" && constExp && k.IsInt() {
+		// (u^a)^k = u^(a·k) for integer k
+		return pow(n.Left, mul(n.Right, exponent))
+	}
+	return operation("^", base, exponent)
+}
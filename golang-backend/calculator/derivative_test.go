@@ -0,0 +1,118 @@
+package calculator
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDerivative(t *testing.T) {
+	cases := []struct {
+		name       string
+		expression string
+		mode       AngleMode
+		want       string
+	}{
+		{"power", "x^3", AngleRadian, "3*x^2"},
+		{"reciprocal", "1/x", AngleRadian, "-1/x^2"},
+		{"variable exponent", "x^x", AngleRadian, "x^x*(ln(x) + 1)"},
+		{"product", "x*sin(x)", AngleRadian, "sin(x) + x*cos(x)"},
+		{"product in degrees", "x*sin(x)", AngleDegree, "sin(x) + x*pi*cos(x)/180"},
+		{"quotient", "sin(x)/x", AngleRadian, "(cos(x)*x - sin(x))/x^2"},
+		{"quotient in degrees", "sin(x)/x", AngleDegree, "(pi*cos(x)*x/180 - sin(x))/x^2"},
+		{"quotient of sums", "x/(x+1)", AngleRadian, "1/(x + 1)^2"},
+		{"chain", "sin(x^2)", AngleRadian, "2*cos(x^2)*x"},
+		{"chain in degrees", "sin(x^2)", AngleDegree, "pi*cos(x^2)*x/90"},
+		{"power of sine", "sin(x)^2", AngleRadian, "2*sin(x)*cos(x)"},
+		{"power of sine in degrees", "sin(x)^2", AngleDegree, "sin(x)*pi*cos(x)/90"},
+		{"inverse trig in degrees", "atan(x)", AngleDegree, "180/(pi*(1 + x^2))"},
+		{"common factor", "x*y/x", AngleRadian, "0"},
+		{"common factor in any order", "x*y*z/(y*x)", AngleRadian, "0"},
+		{"common power", "x^2*y/x", AngleRadian, "y"},
+		{"common sum", "(x+1)*y/(x+1)", AngleRadian, "0"},
+		{"constant", "y^2", AngleRadian, "0"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			prog, err := New().Derivative(tc.expression, "x", EvalOptions{AngleMode: tc.mode})
+			if err != nil {
+				t.Fatalf("d/dx %s: %v", tc.expression, err)
+			}
+			if got := prog.Source(); got != tc.want {
+				t.Errorf("d/dx %s = %s, want %s", tc.expression, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestDerivativeNumeric compares derivatives with central differences
+func TestDerivativeNumeric(t *testing.T) {
+	cases := []struct {
+		expression string
+		mode       AngleMode
+		at         float64
+	}{
+		{"x^2*sqrt(x)", AngleRadian, 2},
+		{"tan(x)*cos(2*x)", AngleRadian, 0.3},
+		{"tan(x)*cos(2*x)", AngleDegree, 30},
+		{"asin(x/2)", AngleDegree, 0.5},
+		{"exp(-x^2/2)/(1+x^2)", AngleRadian, 0.7},
+		{"log(x, 2)*gamma(x)", AngleRadian, 2.5},
+	}
+	for _, tc := range cases {
+		prog, err := New().Derivative(tc.expression, "x", EvalOptions{AngleMode: tc.mode})
+		if err != nil {
+			t.Fatalf("d/dx %s: %v", tc.expression, err)
+		}
+		opts := EvalOptions{AngleMode: tc.mode, Variables: map[string]float64{"x": tc.at}}
+		got, err := prog.Run(opts)
+		if err != nil {
+			t.Fatalf("d/dx %s at %v: %v", tc.expression, tc.at, err)
+		}
+
+		f, err := New().Compile(tc.expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		const h = 1e-6
+		at := func(x float64) float64 {
+			y, err := f.Run(EvalOptions{AngleMode: tc.mode, Variables: map[string]float64{"x": x}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return y
+		}
+		want := (at(tc.at+h) - at(tc.at-h)) / (2 * h)
+		if math.Abs(got-want) > 1e-6*math.Max(1, math.Abs(want)) {
+			t.Errorf("d/dx %s at %v = %v, want %v", tc.expression, tc.at, got, want)
+		}
+	}
+}
+
+func TestDerivativeErrors(t *testing.T) {
+	cases := []struct {
+		expression string
+		kind       error
+		message    string
+	}{
+		{"min(x, 1)", ErrUnsupported, "cannot differentiate min"},
+		{"max(x, 2)", ErrUnsupported, "cannot differentiate max"},
+		{"2*gcd(x, 4)", ErrUnsupported, "cannot differentiate gcd"},
+		{"zeta(x)", ErrUnsupported, "cannot differentiate zeta"},
+		{"jn(x, 2)", ErrUnsupported, "cannot differentiate jn with respect to its order"},
+		{"x & 1", ErrUnsupported, ""},
+		{"foo(x)", ErrUnknownFunction, "foo"},
+		{"y + sin(1, 2)", ErrArgumentCount, ""},
+	}
+	for _, tc := range cases {
+		_, err := New().Derivative(tc.expression, "x", EvalOptions{})
+		if !errors.Is(err, tc.kind) {
+			t.Errorf("d/dx %s: error %v, want %v", tc.expression, err, tc.kind)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.message) {
+			t.Errorf("d/dx %s: error %q does not mention %q", tc.expression, err, tc.message)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package calculator
+
+import (
+	"calculator-backend/linalg"
+	"errors"
+	"fmt"
+)
+
+// Error kinds returned by the calculator package. Every error produced
+// while parsing or evaluating wraps one of these, so callers can test for
+// them with errors.Is.
+var (
+	ErrSyntax          = errors.New("syntax error")
+	ErrDivisionByZero  = errors.New("division by zero")
+	ErrDomain          = errors.New("domain error")
+	ErrOverflow        = errors.New("overflow")
+	ErrUnknownFunction = errors.New("unknown function")
+	ErrUnknownVariable = errors.New("unknown variable")
+	ErrArgumentCount   = errors.New("wrong number of arguments")
+	ErrUnsupported     = errors.New("unsupported operation")
+	ErrInvalidName     = errors.New("invalid name")
+	ErrRecursion       = errors.New("recursion")
+)
+
+// errorCode pairs an error kind with its stable machine-readable code
+type errorCode struct {
+	kind error
+	code string
+}
+
+// errorCodes lists the code of each error kind. Kinds are matched in
+// order, so an error wrapping several kinds always gets the code of the
+// first.
+var errorCodes = []errorCode{
+	{ErrSyntax, "syntax_error"},
+	{ErrDivisionByZero, "division_by_zero"},
+	{ErrDomain, "domain_error"},
+	{ErrOverflow, "overflow"},
+	{ErrUnknownFunction, "unknown_function"},
+	{ErrUnknownVariable, "unknown_variable"},
+	{ErrArgumentCount, "argument_count"},
+	{ErrUnsupported, "unsupported"},
+	{ErrInvalidName, "invalid_name"},
+	{ErrRecursion, "recursion"},
+
+	{linalg.ErrDimension, "dimension_mismatch"},
+	{linalg.ErrSingular, "singular_matrix"},
+	{linalg.ErrIllConditioned, "ill_conditioned"},
+}
+
+// Error is a calculator error of a given kind. When it is located, Span
+// covers the offending part of the original expression, in runes.
+type Error struct {
+	Kind    error
+	Message string
+	Span    Span
+	located bool
+}
+
+// newError creates an error of the given kind that is not yet located
+func newError(kind error, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// newErrorf creates an unlocated error with a formatted message
+func newErrorf(kind error, format string, args ...interface{}) *Error {
+	return newError(kind, fmt.Sprintf(format, args...))
+}
+
+// errorAt creates an error of the given kind located at span
+func errorAt(kind error, span Span, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...), Span: span, located: true}
+}
+
+// NewError creates an error of the given kind that is not located. It is
+// for packages that build on the calculator, so their errors carry the
+// same kinds and codes.
+func NewError(kind error, format string, args ...interface{}) *Error {
+	return newErrorf(kind, format, args...)
+}
+
+// ErrorAt creates an error of the given kind located at span
+func ErrorAt(kind error, span Span, format string, args ...interface{}) *Error {
+	return errorAt(kind, span, format, args...)
+}
+
+func (e *Error) Error() string {
+	if e.located {
+		return fmt.Sprintf("%s at position %d", e.Message, e.Span.Start)
+	}
+	return e.Message
+}
+
+// Unwrap returns the error kind, so errors.Is(err, ErrDomain) works
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// Located reports whether Span refers to the source expression
+func (e *Error) Located() bool {
+	return e.located
+}
+
+// Code returns the stable machine-readable code for the error's kind
+func (e *Error) Code() string {
+	return kindCode(e.Kind)
+}
+
+// ErrorCode returns the stable machine-readable code for any error
+func ErrorCode(err error) string {
+	var calcErr *Error
+	if errors.As(err, &calcErr) {
+		return calcErr.Code()
+	}
+	return kindCode(err)
+}
+
+// kindCode returns the code of the first error kind err is or wraps
+func kindCode(err error) string {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.kind) {
+			return c.code
+		}
+	}
+	return "evaluation_error"
+}
+
+// locate attaches span to err unless it already carries a location.
+// A prefix, if given, is prepended to the message as context.
+func locate(err error, span Span, prefix string) error {
+	var calcErr *Error
+	if errors.As(err, &calcErr) {
+		if calcErr.located {
+			return err
+		}
+		return &Error{Kind: calcErr.Kind, Message: prefix + calcErr.Message, Span: span, located: true}
+	}
+	return &Error{Message: prefix + err.Error(), Span: span, located: true}
+}
+
+// relocate moves err to span whether or not it is already located. It is
+// used for errors raised in other source text, such as the body of a
+// user-defined function, whose offsets mean nothing to the caller.
+func relocate(err error, span Span, prefix string) error {
+	var calcErr *Error
+	if errors.As(err, &calcErr) {
+		return &Error{Kind: calcErr.Kind, Message: prefix + calcErr.Message, Span: span, located: true}
+	}
+	return locate(err, span, prefix)
+}
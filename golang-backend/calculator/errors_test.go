@@ -0,0 +1,94 @@
+package calculator
+
+import (
+	"calculator-backend/linalg"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorSpans(t *testing.T) {
+	cases := []struct {
+		expression string
+		kind       error
+		code       string
+		span       Span
+	}{
+		{"1 / 0", ErrDivisionByZero, "division_by_zero", Span{0, 5}},
+		{"2 + sqrt(-1)", ErrDomain, "domain_error", Span{4, 12}},
+		{"2 + ln(0) * 3", ErrDomain, "domain_error", Span{4, 9}},
+		{"1 + foo(2)", ErrUnknownFunction, "unknown_function", Span{4, 10}},
+		{"3 * y", ErrUnknownVariable, "unknown_variable", Span{4, 5}},
+		{"sin(1, 2)", ErrArgumentCount, "argument_count", Span{0, 9}},
+		{"1 +", ErrSyntax, "syntax_error", Span{3, 3}},
+		{"(1 + 2", ErrSyntax, "syntax_error", Span{6, 6}},
+		{"1 $ 2", ErrSyntax, "syntax_error", Span{2, 3}},
+		{"10^400", ErrOverflow, "overflow", Span{0, 6}},
+		// Spans count runes, not bytes
+		{"π ÷ 0", ErrDivisionByZero, "division_by_zero", Span{0, 5}},
+		{"[1, 2; 3]", ErrSyntax, "syntax_error", Span{7, 8}},
+	}
+	for _, tc := range cases {
+		_, err := New().Evaluate(tc.expression, EvalOptions{})
+		if !errors.Is(err, tc.kind) {
+			t.Errorf("%q: error %v, want %v", tc.expression, err, tc.kind)
+			continue
+		}
+		var calcErr *Error
+		if !errors.As(err, &calcErr) {
+			t.Errorf("%q: error %T is not a *Error", tc.expression, err)
+			continue
+		}
+		if !calcErr.Located() || calcErr.Span != tc.span {
+			t.Errorf("%q: span %v (located %v), want %v", tc.expression, calcErr.Span, calcErr.Located(), tc.span)
+		}
+		if got := ErrorCode(err); got != tc.code {
+			t.Errorf("%q: code %q, want %q", tc.expression, got, tc.code)
+		}
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{NewError(ErrRecursion, "too deep"), "recursion"},
+		{fmt.Errorf("context: %w", ErrInvalidName), "invalid_name"},
+		{fmt.Errorf("context: %w", NewError(ErrOverflow, "too large")), "overflow"},
+		{ErrUnsupported, "unsupported"},
+		{fmt.Errorf("context: %w", linalg.ErrDimension), "dimension_mismatch"},
+		{linalg.ErrSingular, "singular_matrix"},
+		{linalg.ErrIllConditioned, "ill_conditioned"},
+		{errors.New("something else"), "evaluation_error"},
+	}
+	for _, tc := range cases {
+		if got := ErrorCode(tc.err); got != tc.want {
+			t.Errorf("ErrorCode(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestLocate(t *testing.T) {
+	inner := errorAt(ErrDomain, Span{1, 2}, "bad value")
+	if got := locate(inner, Span{0, 9}, "in f: "); got != error(inner) {
+		t.Errorf("locate replaced an existing location: %v", got)
+	}
+
+	moved := relocate(inner, Span{0, 9}, "in f: ")
+	var calcErr *Error
+	if !errors.As(moved, &calcErr) || calcErr.Span != (Span{0, 9}) || calcErr.Message != "in f: bad value" {
+		t.Errorf("relocate = %#v", moved)
+	}
+	if !errors.Is(moved, ErrDomain) {
+		t.Errorf("relocate lost the kind: %v", moved)
+	}
+
+	plain := locate(errors.New("boom"), Span{3, 4}, "")
+	if got := plain.Error(); got != "boom at position 3" {
+		t.Errorf("locate(plain) = %q", got)
+	}
+	if got := NewError(ErrDomain, "x = %d", 1).Error(); got != "x = 1" {
+		t.Errorf("unlocated message = %q", got)
+	}
+}
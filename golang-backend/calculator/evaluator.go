@@ -0,0 +1,236 @@
+package calculator
+
+import (
+	"math"
+)
+
+// constants maps identifier names to their values
+var constants = map[string]float64{
+	"pi": PI,
+	"π":  PI,
+	"e":  E,
+}
+
+// maxCallDepth bounds how deeply user-defined functions may nest
+const maxCallDepth = 64
+
+// maxUserCalls bounds how many user-defined function calls one evaluation
+// may make, so definitions that call each other twice cannot run for
+// exponential time within the depth limit
+const maxUserCalls = 100000
+
+// evaluation carries the state of a single Evaluate call. While a
+// user-defined function body is evaluated, params holds its arguments.
+// calls counts user-defined function calls and is shared by the nested
+// evaluations of one call.
+type evaluation struct {
+	parser *ExpressionParser
+	opts   EvalOptions
+	params map[string]float64
+	depth  int
+	calls  *int
+}
+
+// eval walks an expression tree and computes its value
+func (ev *evaluation) eval(node Node) (float64, error) {
+	switch n := node.(type) {
+	case *NumberNode:
+		if math.IsInf(n.Value, 0) {
+			return 0, errorAt(ErrOverflow, n.span, "number out of range '%s'", n.Text)
+		}
+		return n.Value, nil
+
+	case *IdentNode:
+		if value, ok := ev.params[n.Name]; ok {
+			return value, nil
+		}
+		if value, ok := ev.opts.lookup(n.Name); ok {
+			return value, nil
+		}
+		if value, ok := ev.parser.constants[n.Name]; ok {
+			return value, nil
+		}
+		return 0, errorAt(ErrUnknownVariable, n.span, "unknown identifier '%s'", n.Name)
+
+	case *UnaryNode:
+		operand, err := ev.eval(n.Operand)
+		if err != nil {
+			return 0, err
+		}
+		if bitwiseOperators[n.Op] {
+			return 0, unsupportedOperator(n.Op, n.span)
+		}
+		if n.Op == "-" {
+			return ev.parser.basic.Negate(operand), nil
+		}
+		return operand, nil
+
+	case *PostfixNode:
+		operand, err := ev.eval(n.Operand)
+		if err != nil {
+			return 0, err
+		}
+		result, err := ev.parser.basic.Factorial(operand)
+		if err != nil {
+			return 0, locate(err, n.span, "factorial error: ")
+		}
+		return result, nil
+
+	case *BinaryNode:
+		return ev.evalBinary(n)
+
+	case *CallNode:
+		return ev.evalCall(n)
+	}
+
+	return 0, unsupportedNode(node, "")
+}
+
+// evalBinary evaluates both operands and applies an infix operator
+func (ev *evaluation) evalBinary(n *BinaryNode) (float64, error) {
+	left, err := ev.eval(n.Left)
+	if err != nil {
+		return 0, err
+	}
+	right, err := ev.eval(n.Right)
+	if err != nil {
+		return 0, err
+	}
+	return ev.applyBinary(n, left, right)
+}
+
+// applyBinary applies the infix operator of n to evaluated operands
+func (ev *evaluation) applyBinary(n *BinaryNode, left, right float64) (float64, error) {
+	var (
+		result float64
+		err    error
+	)
+	switch n.Op {
+	case "+":
+		result = ev.parser.basic.Add(left, right)
+	case "-":
+		result = ev.parser.basic.Subtract(left, right)
+	case "*":
+		result = ev.parser.basic.Multiply(left, right)
+	case "/":
+		result, err = ev.parser.basic.Divide(left, right)
+	case "^":
+		result, err = ev.parser.basic.Power(left, right)
+	default:
+		return 0, unsupportedOperator(n.Op, n.span)
+	}
+	if err != nil {
+		return 0, locate(err, n.span, "")
+	}
+	return checkResult(result, n.span)
+}
+
+// checkResult rejects infinite and NaN intermediate results, locating the
+// error at the operation that produced them
+func checkResult(value float64, span Span) (float64, error) {
+	switch {
+	case math.IsNaN(value):
+		return 0, errorAt(ErrDomain, span, "result is undefined")
+	case math.IsInf(value, 0):
+		return 0, errorAt(ErrOverflow, span, "result out of range")
+	}
+	return value, nil
+}
+
+// evalCall evaluates a function call
+func (ev *evaluation) evalCall(n *CallNode) (float64, error) {
+	fn, ok := ev.parser.functions[n.Name]
+	if !ok {
+		if user, ok := ev.opts.userFunction(n.Name); ok {
+			return ev.callUser(user, n)
+		}
+		if _, ok := matrixFunctions[n.Name]; ok {
+			return 0, errorAt(ErrUnsupported, n.span, "function %s takes a matrix and cannot be used where a number is expected", n.Name)
+		}
+		return 0, errorAt(ErrUnknownFunction, n.span, "unknown function '%s'", n.Name)
+	}
+	if err := fn.checkArity(n.Name, len(n.Args)); err != nil {
+		return 0, locate(err, n.span, "")
+	}
+
+	args := make([]float64, len(n.Args))
+	for i, argNode := range n.Args {
+		arg, err := ev.eval(argNode)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = arg
+	}
+	return ev.applyCall(fn, n, args)
+}
+
+// applyCall applies a built-in function to evaluated arguments
+func (ev *evaluation) applyCall(fn function, n *CallNode, args []float64) (float64, error) {
+	result, err := fn.eval(args, ev.opts)
+	if err != nil {
+		return 0, locate(err, n.span, "error in "+n.Name+" function: ")
+	}
+	return checkResult(result, n.span)
+}
+
+// Call applies the named function to args as an expression calling it
+// would, for callers that have values rather than an expression
+func (p *ExpressionParser) Call(name string, args []float64, opts EvalOptions) (float64, error) {
+	fn, ok := p.functions[name]
+	if !ok {
+		return 0, newErrorf(ErrUnknownFunction, "unknown function '%s'", name)
+	}
+	if err := fn.checkArity(name, len(args)); err != nil {
+		return 0, err
+	}
+
+	result, err := fn.eval(args, p.withDefaults(opts))
+	switch {
+	case err != nil:
+		return 0, err
+	case math.IsNaN(result):
+		return 0, newError(ErrDomain, "result is undefined")
+	case math.IsInf(result, 0):
+		return 0, newError(ErrOverflow, "result out of range")
+	}
+	return result, nil
+}
+
+// callUser evaluates a call to a user-defined function. The body is
+// evaluated in a fresh evaluation that sees only the function's parameters,
+// not those of its caller.
+func (ev *evaluation) callUser(fn *UserFunction, n *CallNode) (float64, error) {
+	if len(n.Args) != len(fn.Params) {
+		plural := "s"
+		if len(fn.Params) == 1 {
+			plural = ""
+		}
+		return 0, errorAt(ErrArgumentCount, n.span, "function %s expects %d argument%s, got %d", fn.Name, len(fn.Params), plural, len(n.Args))
+	}
+	if ev.depth >= maxCallDepth {
+		return 0, errorAt(ErrRecursion, n.span, "user-defined functions nested more than %d deep", maxCallDepth)
+	}
+	if ev.calls == nil {
+		ev.calls = new(int)
+	}
+	if *ev.calls >= maxUserCalls {
+		return 0, errorAt(ErrRecursion, n.span, "more than %d calls to user-defined functions", maxUserCalls)
+	}
+	*ev.calls++
+
+	params := make(map[string]float64, len(fn.Params))
+	for i, argNode := range n.Args {
+		arg, err := ev.eval(argNode)
+		if err != nil {
+			return 0, err
+		}
+		params[fn.Params[i]] = arg
+	}
+
+	inner := &evaluation{parser: ev.parser, opts: ev.opts, params: params, depth: ev.depth + 1, calls: ev.calls}
+	result, err := inner.eval(fn.Body)
+	if err != nil {
+		return 0, relocate(err, n.span, "error in "+fn.Name+" function: ")
+	}
+	return result, nil
+}
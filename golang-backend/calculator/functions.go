@@ -0,0 +1,266 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+)
+
+// Arity is the range of argument counts a function accepts
+type Arity struct {
+	Min int
+	Max int // -1 for variadic functions
+}
+
+// function is a registered function callable from expressions
+type function struct {
+	Arity
+	eval Func
+	info FunctionInfo
+}
+
+// checkArity reports an error if n arguments are not accepted
+func (a Arity) checkArity(name string, n int) error {
+	if n >= a.Min && (a.Max < 0 || n <= a.Max) {
+		return nil
+	}
+
+	var expected string
+	switch {
+	case a.Max < 0:
+		expected = fmt.Sprintf("at least %d", a.Min)
+	case a.Min == a.Max:
+		expected = fmt.Sprintf("%d", a.Min)
+	default:
+		expected = fmt.Sprintf("%d to %d", a.Min, a.Max)
+	}
+
+	plural := "s"
+	if a.Max == 1 || (a.Max < 0 && a.Min == 1) {
+		plural = ""
+	}
+	return newErrorf(ErrArgumentCount, "function %s expects %s argument%s, got %d", name, expected, plural, n)
+}
+
+// unary adapts a single-argument function
+func unary(fn func(x float64, opts EvalOptions) (float64, error)) function {
+	return function{
+		Arity: Arity{1, 1},
+		eval: func(args []float64, opts EvalOptions) (float64, error) {
+			return fn(args[0], opts)
+		},
+	}
+}
+
+// binary adapts a two-argument function
+func binary(fn func(x, y float64, opts EvalOptions) (float64, error)) function {
+	return function{
+		Arity: Arity{2, 2},
+		eval: func(args []float64, opts EvalOptions) (float64, error) {
+			return fn(args[0], args[1], opts)
+		},
+	}
+}
+
+// builtinFunctions returns the functions every registry starts with,
+// including the integer functions, documented by builtinInfo and
+// descriptionsID
+func builtinFunctions(basic *BasicOperations, scientific *ScientificOperations) map[string]function {
+	functions := map[string]function{
+		"sin": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Sin(x, opts.AngleMode.IsDegree()), nil
+		}),
+		"cos": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Cos(x, opts.AngleMode.IsDegree()), nil
+		}),
+		"tan": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Tan(x, opts.AngleMode.IsDegree())
+		}),
+		"sec": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Sec(x, opts.AngleMode.IsDegree())
+		}),
+		"csc": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Csc(x, opts.AngleMode.IsDegree())
+		}),
+		"cot": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Cot(x, opts.AngleMode.IsDegree())
+		}),
+		"asin": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Asin(x, opts.AngleMode.IsDegree())
+		}),
+		"acos": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Acos(x, opts.AngleMode.IsDegree())
+		}),
+		"atan": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Atan(x, opts.AngleMode.IsDegree()), nil
+		}),
+		"asec": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Asec(x, opts.AngleMode.IsDegree())
+		}),
+		"acsc": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Acsc(x, opts.AngleMode.IsDegree())
+		}),
+		"acot": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Acot(x, opts.AngleMode.IsDegree()), nil
+		}),
+		"atan2": binary(func(y, x float64, opts EvalOptions) (float64, error) {
+			return scientific.Atan2(y, x, opts.AngleMode.IsDegree())
+		}),
+		"sinh": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Sinh(x)
+		}),
+		"cosh": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Cosh(x)
+		}),
+		"tanh": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Tanh(x), nil
+		}),
+		"asinh": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Asinh(x), nil
+		}),
+		"acosh": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Acosh(x)
+		}),
+		"atanh": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Atanh(x)
+		}),
+		"log": {
+			Arity: Arity{1, 2},
+			eval: func(args []float64, opts EvalOptions) (float64, error) {
+				if len(args) == 2 {
+					return scientific.LogBase(args[0], args[1])
+				}
+				return scientific.Log(args[0])
+			},
+		},
+		"ln": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Ln(x)
+		}),
+		"exp": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Exp(x)
+		}),
+		"exp2": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Exp2(x)
+		}),
+		"exp10": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Exp10(x)
+		}),
+		"pow": binary(func(x, y float64, opts EvalOptions) (float64, error) {
+			return basic.Power(x, y)
+		}),
+		"sqrt": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return basic.SquareRoot(x)
+		}),
+		"cbrt": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return basic.CubeRoot(x), nil
+		}),
+		"root": binary(func(n, x float64, opts EvalOptions) (float64, error) {
+			return basic.NthRoot(n, x)
+		}),
+		"hypot": {
+			Arity: Arity{2, -1},
+			eval: func(args []float64, opts EvalOptions) (float64, error) {
+				return scientific.Hypot(args...), nil
+			},
+		},
+		"abs": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return basic.Absolute(x), nil
+		}),
+		"factorial": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return basic.Factorial(x)
+		}),
+		"gamma": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Gamma(x)
+		}),
+		"lgamma": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.LogGamma(x)
+		}),
+		"beta": binary(func(a, b float64, opts EvalOptions) (float64, error) {
+			return scientific.Beta(a, b)
+		}),
+		"digamma": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Digamma(x)
+		}),
+		"zeta": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Zeta(x)
+		}),
+		"erf": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Erf(x), nil
+		}),
+		"erfc": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Erfc(x), nil
+		}),
+		"erfinv": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Erfinv(x)
+		}),
+		"j0": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.J0(x), nil
+		}),
+		"j1": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.J1(x), nil
+		}),
+		"jn": binary(func(n, x float64, opts EvalOptions) (float64, error) {
+			return scientific.Jn(n, x)
+		}),
+		"y0": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Y0(x)
+		}),
+		"y1": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Y1(x)
+		}),
+		"yn": binary(func(n, x float64, opts EvalOptions) (float64, error) {
+			return scientific.Yn(n, x)
+		}),
+		"mod": binary(func(a, b float64, opts EvalOptions) (float64, error) {
+			return basic.Modulo(a, b)
+		}),
+		"min": {
+			Arity: Arity{1, -1},
+			eval: func(args []float64, opts EvalOptions) (float64, error) {
+				result := args[0]
+				for _, arg := range args[1:] {
+					result = math.Min(result, arg)
+				}
+				return result, nil
+			},
+		},
+		"max": {
+			Arity: Arity{1, -1},
+			eval: func(args []float64, opts EvalOptions) (float64, error) {
+				result := args[0]
+				for _, arg := range args[1:] {
+					result = math.Max(result, arg)
+				}
+				return result, nil
+			},
+		},
+		"floor": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Floor(x), nil
+		}),
+		"ceil": unary(func(x float64, opts EvalOptions) (float64, error) {
+			return scientific.Ceil(x), nil
+		}),
+		"round": {
+			Arity: Arity{1, 2},
+			eval: func(args []float64, opts EvalOptions) (float64, error) {
+				if len(args) == 2 {
+					if args[1] != math.Trunc(args[1]) {
+						return 0, newError(ErrDomain, "round digits must be an integer")
+					}
+					return scientific.RoundTo(args[0], int(args[1])), nil
+				}
+				return scientific.Round(args[0]), nil
+			},
+		},
+	}
+
+	for name, fn := range integerFunctions {
+		if _, ok := functions[name]; !ok {
+			functions[name] = fn.float()
+		}
+	}
+	for name, fn := range functions {
+		fn.info = builtinDoc(name, builtinInfo[name])
+		functions[name] = fn
+	}
+	return functions
+}
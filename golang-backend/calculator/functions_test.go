@@ -0,0 +1,89 @@
+package calculator
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestMultiArgumentFunctions(t *testing.T) {
+	cases := []struct {
+		expression string
+		want       float64
+	}{
+		{"log(8, 2)", 3},
+		{"log(100)", 2},
+		{"atan2(1, 1)", 45},
+		{"atan2(1, -1)", 135},
+		{"hypot(3, 4)", 5},
+		{"hypot(1, 2, 2)", 3},
+		{"min(3, 1, 2)", 1},
+		{"max(3, 1, 2)", 3},
+		{"max(-1)", -1},
+		{"pow(2, 10)", 1024},
+		{"root(3, 27)", 3},
+		{"root(3, -8)", -2},
+		{"mod(7, 3)", 1},
+		{"mod(-7, 3)", 2},
+		{"round(3.14159, 2)", 3.14},
+		{"round(1234, -2)", 1200},
+		{"round(2.5)", 3},
+		{"max(1, min(5, 4), 2) + log(9, 3)", 6},
+		{"pow(2, max(1, 3))", 8},
+	}
+	for _, tc := range cases {
+		got, err := New().Evaluate(tc.expression, EvalOptions{AngleMode: AngleDegree})
+		if err != nil {
+			t.Errorf("%s: %v", tc.expression, err)
+			continue
+		}
+		if math.Abs(got-tc.want) > 1e-12*math.Max(1, math.Abs(tc.want)) {
+			t.Errorf("%s = %v, want %v", tc.expression, got, tc.want)
+		}
+	}
+}
+
+func TestArity(t *testing.T) {
+	cases := []struct {
+		expression string
+		message    string
+	}{
+		{"sin(1, 2)", "function sin expects 1 argument, got 2"},
+		{"atan2(1)", "function atan2 expects 2 arguments, got 1"},
+		{"log(1, 2, 3)", "function log expects 1 to 2 arguments, got 3"},
+		{"min()", "function min expects at least 1 argument, got 0"},
+		{"hypot(1)", "function hypot expects at least 2 arguments, got 1"},
+	}
+	for _, tc := range cases {
+		_, err := New().Evaluate(tc.expression, EvalOptions{})
+		if !errors.Is(err, ErrArgumentCount) {
+			t.Errorf("%s: error %v, want %v", tc.expression, err, ErrArgumentCount)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.message) {
+			t.Errorf("%s: error %q, want %q", tc.expression, err, tc.message)
+		}
+	}
+}
+
+func TestMultiArgumentFunctionErrors(t *testing.T) {
+	cases := []struct {
+		expression string
+		kind       error
+	}{
+		{"log(8, 1)", ErrDomain},
+		{"log(-8, 2)", ErrDomain},
+		{"root(2, -4)", ErrDomain},
+		{"root(0, 4)", ErrDomain},
+		{"mod(1, 0)", ErrDivisionByZero},
+		{"round(1.5, 0.5)", ErrDomain},
+		{"min(1,)", ErrSyntax},
+		{"max(,1)", ErrSyntax},
+	}
+	for _, tc := range cases {
+		if _, err := New().Evaluate(tc.expression, EvalOptions{}); !errors.Is(err, tc.kind) {
+			t.Errorf("%s: error %v, want %v", tc.expression, err, tc.kind)
+		}
+	}
+}
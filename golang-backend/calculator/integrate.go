@@ -0,0 +1,393 @@
+package calculator
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+)
+
+// defaultIntegralTolerance is the error target when the caller sets none
+const defaultIntegralTolerance = 1e-10
+
+// defaultIntegralEvaluations bounds the integrand evaluations when the
+// caller sets no limit
+const defaultIntegralEvaluations = 100000
+
+// maxIntegralEvaluations bounds the limit a caller may set
+const maxIntegralEvaluations = 1000000
+
+// kronrodPoints is the number of integrand evaluations in one application
+// of the Kronrod rule, the least any integral costs
+const kronrodPoints = 15
+
+// maxSingularities bounds how often an interval is split at a point where
+// the integrand could not be evaluated
+const maxSingularities = 16
+
+// float64Epsilon is the spacing of float64 values just above 1
+const float64Epsilon = 0x1p-52
+
+// IntegralOptions sets the interval and accuracy of a definite integral
+type IntegralOptions struct {
+	Lower, Upper float64 // either may be infinite
+	// Tolerance is the absolute error to aim for; an error within
+	// Tolerance relative to the value is also accepted. 0 means 1e-10.
+	Tolerance float64
+	// MaxEvaluations limits integrand evaluations; 0 means 100000, and
+	// at least 15 are needed
+	MaxEvaluations int
+}
+
+// Integral is the result of adaptive quadrature
+type Integral struct {
+	Value         float64
+	ErrorEstimate float64 // estimated absolute error of Value
+	Evaluations   int     // times the integrand was evaluated
+	Intervals     int     // subintervals the range was split into
+	Converged     bool    // false when the evaluation limit stopped refinement early
+}
+
+// Gauss–Kronrod 15-point nodes on [-1, 1], in decreasing order, with the
+// Kronrod weights and the weights of the embedded 7-point Gauss rule,
+// which uses every other node
+var (
+	kronrodNodes = [8]float64{
+		0.991455371120812639206854697526329,
+		0.949107912342758524526189684047851,
+		0.864864423359769072789712788640926,
+		0.741531185599394439863864773280788,
+		0.586087235467691130294144845693013,
+		0.405845151377397166906606412076961,
+		0.207784955007898467600689403773245,
+		0,
+	}
+	kronrodWeights = [8]float64{
+		0.022935322010529224963732008058970,
+		0.063092092629978553290700663189204,
+		0.104790010322250183839876322541518,
+		0.140653259715525918745189590510238,
+		0.169004726639267902826583426598550,
+		0.190350578064785409913256402421014,
+		0.204432940075298892414161999234649,
+		0.209482141084727828012999174891714,
+	}
+	gaussWeights = [4]float64{
+		0.129484966168869693270611432679082,
+		0.279705391489276667901467771423780,
+		0.381830050505118944950369775488975,
+		0.417959183673469387755102040816327,
+	}
+)
+
+// Integrate evaluates the definite integral of an expression over the
+// variable with this parser's function table
+func (p *ExpressionParser) Integrate(expression, variable string, bounds IntegralOptions, opts EvalOptions) (*Integral, error) {
+	prog, err := p.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	return prog.Integrate(variable, bounds, opts)
+}
+
+// Integrate evaluates the definite integral of the program over the
+// variable by adaptive Gauss–Kronrod quadrature. The subinterval with the
+// largest error is split until the total error meets the tolerance.
+// Infinite bounds are mapped onto a finite interval first. An interval is
+// also split at a sample where the integrand is undefined, so isolated
+// integrable singularities such as that of 1/sqrt(abs(x)) at 0 are
+// handled; where that does not help, the error names the point. Other
+// identifiers are bound from opts as in Run.
+func (prog *Program) Integrate(variable string, bounds IntegralOptions, opts EvalOptions) (*Integral, error) {
+	if err := prog.parser.CheckName(variable); err != nil {
+		return nil, err
+	}
+	tol := bounds.Tolerance
+	if tol == 0 {
+		tol = defaultIntegralTolerance
+	}
+	if !(tol > 0) || math.IsInf(tol, 0) {
+		return nil, newError(ErrDomain, "tolerance must be a positive number")
+	}
+	limit := bounds.MaxEvaluations
+	if limit == 0 {
+		limit = defaultIntegralEvaluations
+	}
+	if limit < kronrodPoints || limit > maxIntegralEvaluations {
+		return nil, newErrorf(ErrDomain, "evaluation limit must be between %d and %d", kronrodPoints, maxIntegralEvaluations)
+	}
+	a, b := bounds.Lower, bounds.Upper
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return nil, newError(ErrDomain, "integration bounds must be numbers")
+	}
+	if a == b {
+		return &Integral{Converged: true}, nil
+	}
+	sign := 1.0
+	if a > b {
+		a, b, sign = b, a, -1
+	}
+
+	vars := make(map[string]float64, len(opts.Variables)+1)
+	for name, value := range opts.Variables {
+		vars[name] = value
+	}
+	opts.Variables = vars
+	q := &quadrature{limit: limit}
+	q.f = func(x float64) (float64, error) {
+		vars[variable] = x
+		q.evaluations++
+		// Digits rounds the integral, not every sample of the integrand
+		y, err := prog.Run(EvalOptions{AngleMode: opts.AngleMode, Variables: vars, Scope: opts.Scope})
+		if err != nil && (errors.Is(err, ErrDivisionByZero) || errors.Is(err, ErrDomain) || errors.Is(err, ErrOverflow)) {
+			return 0, &singularity{variable: variable, x: x, err: err}
+		}
+		return y, err
+	}
+	q.f = transformInfinite(q.f, &a, &b)
+
+	result, err := q.integrate(a, b, tol)
+	if err != nil {
+		return nil, err
+	}
+	result.Value = roundSignificant(sign*result.Value, opts.Digits)
+	return result, nil
+}
+
+// transformInfinite replaces an integrand over an interval with an
+// infinite end by one over a finite interval with the same integral,
+// updating the bounds. The new integrands are never sampled at the ends
+// of their intervals, where the substitutions diverge.
+func transformInfinite(f func(float64) (float64, error), a, b *float64) func(float64) (float64, error) {
+	lower, upper := *a, *b
+	switch {
+	case math.IsInf(lower, -1) && math.IsInf(upper, 1):
+		// x = t / (1 - t²) on (-1, 1)
+		*a, *b = -1, 1
+		return func(t float64) (float64, error) {
+			d := 1 - t*t
+			y, err := f(t / d)
+			return y * (1 + t*t) / (d * d), err
+		}
+	case math.IsInf(upper, 1):
+		// x = a + t / (1 - t) on [0, 1)
+		*a, *b = 0, 1
+		return func(t float64) (float64, error) {
+			d := 1 - t
+			y, err := f(lower + t/d)
+			return y / (d * d), err
+		}
+	case math.IsInf(lower, -1):
+		// x = b - (1 - t) / t on (0, 1]
+		*a, *b = 0, 1
+		return func(t float64) (float64, error) {
+			y, err := f(upper - (1-t)/t)
+			return y / (t * t), err
+		}
+	}
+	return f
+}
+
+// quadrature holds the state of one adaptive integration
+type quadrature struct {
+	f           func(float64) (float64, error)
+	evaluations int
+	limit       int
+	splits      int // intervals split at singularities
+}
+
+// singularity records a point where the integrand could not be evaluated.
+// X is in the variable of the integral and t the point sampled, which
+// differ when an infinite interval was transformed.
+type singularity struct {
+	variable string
+	x, t     float64
+	err      error
+}
+
+func (s *singularity) Error() string {
+	return s.err.Error()
+}
+
+// domainError reports that the integral could not avoid the point
+func (s *singularity) domainError() error {
+	message := s.err.Error()
+	var calcErr *Error
+	if errors.As(s.err, &calcErr) {
+		message = calcErr.Message
+	}
+	return newErrorf(ErrDomain, "the integrand is not defined at %s = %g: %s", s.variable, s.x, message)
+}
+
+// segment is a subinterval with its Kronrod estimate and error
+type segment struct {
+	a, b   float64
+	value  float64
+	errEst float64
+}
+
+// segmentHeap orders subintervals by decreasing error
+type segmentHeap []segment
+
+func (h segmentHeap) Len() int            { return len(h) }
+func (h segmentHeap) Less(i, j int) bool  { return h[i].errEst > h[j].errEst }
+func (h segmentHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *segmentHeap) Push(x interface{}) { *h = append(*h, x.(segment)) }
+func (h *segmentHeap) Pop() interface{} {
+	old := *h
+	s := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return s
+}
+
+// integrate bisects the worst subinterval until the summed error is within
+// tolerance, the evaluation limit is reached, or the subintervals are too
+// narrow to split
+func (q *quadrature) integrate(a, b, tol float64) (*Integral, error) {
+	first, err := q.estimate(a, b)
+	if err != nil {
+		return nil, err
+	}
+	segments := &segmentHeap{}
+	var total, totalErr float64
+	for _, s := range first {
+		heap.Push(segments, s)
+		total += s.value
+		totalErr += s.errEst
+	}
+
+	converged := false
+	for {
+		if totalErr <= math.Max(tol, tol*math.Abs(total)) {
+			converged = true
+			break
+		}
+		// Splitting costs two more applications of the rule
+		if q.evaluations+2*kronrodPoints > q.limit {
+			break
+		}
+		worst := heap.Pop(segments).(segment)
+		mid := worst.a + (worst.b-worst.a)/2
+		if mid <= worst.a || mid >= worst.b {
+			// Too narrow to split; the error cannot improve further
+			heap.Push(segments, worst)
+			break
+		}
+		left, err := q.estimate(worst.a, mid)
+		if err != nil {
+			return nil, err
+		}
+		right, err := q.estimate(mid, worst.b)
+		if err != nil {
+			return nil, err
+		}
+		total -= worst.value
+		totalErr -= worst.errEst
+		for _, s := range append(left, right...) {
+			heap.Push(segments, s)
+			total += s.value
+			totalErr += s.errEst
+		}
+	}
+	if math.IsNaN(total) || math.IsInf(total, 0) {
+		return nil, newError(ErrOverflow, "integral is out of range")
+	}
+	return &Integral{
+		Value:         total,
+		ErrorEstimate: totalErr,
+		Evaluations:   q.evaluations,
+		Intervals:     segments.Len(),
+		Converged:     converged,
+	}, nil
+}
+
+// estimate applies the Kronrod rule to [a, b]. When the integrand is
+// undefined at a sample inside the interval, the interval is split there
+// instead; the rule never samples the ends of an interval, so the halves
+// avoid the point.
+func (q *quadrature) estimate(a, b float64) ([]segment, error) {
+	s, err := q.kronrod(a, b)
+	var sing *singularity
+	if !errors.As(err, &sing) {
+		if err != nil {
+			return nil, err
+		}
+		return []segment{s}, nil
+	}
+	if sing.t <= a || sing.t >= b || q.splits >= maxSingularities || q.evaluations+2*kronrodPoints > q.limit {
+		return nil, sing.domainError()
+	}
+	q.splits++
+	left, err := q.estimate(a, sing.t)
+	if err != nil {
+		return nil, err
+	}
+	right, err := q.estimate(sing.t, b)
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+// kronrod applies the 15-point Kronrod rule to [a, b], estimating its
+// error from the embedded 7-point Gauss rule as QUADPACK does
+func (q *quadrature) kronrod(a, b float64) (segment, error) {
+	center := (a + b) / 2
+	half := (b - a) / 2
+
+	var samples [kronrodPoints]float64
+	sample := func(t float64) (float64, error) {
+		y, err := q.f(t)
+		var sing *singularity
+		if errors.As(err, &sing) {
+			sing.t = t
+		}
+		return y, err
+	}
+	for i, node := range kronrodNodes {
+		var err error
+		if samples[2*i], err = sample(center - half*node); err != nil {
+			return segment{}, err
+		}
+		if node == 0 {
+			break
+		}
+		if samples[2*i+1], err = sample(center + half*node); err != nil {
+			return segment{}, err
+		}
+	}
+
+	var kronrod, gauss, absolute float64
+	for i, w := range kronrodWeights {
+		sum := samples[2*i]
+		abs := math.Abs(samples[2*i])
+		if i < 7 {
+			sum += samples[2*i+1]
+			abs += math.Abs(samples[2*i+1])
+		}
+		kronrod += w * sum
+		absolute += w * abs
+		if i%2 == 1 {
+			gauss += gaussWeights[i/2] * sum
+		}
+	}
+
+	// The spread of the integrand about its mean scales the raw difference
+	// between the two rules, which overstates the error of the Kronrod rule
+	mean := kronrod / 2
+	var spread float64
+	for i, w := range kronrodWeights {
+		spread += w * math.Abs(samples[2*i]-mean)
+		if i < 7 {
+			spread += w * math.Abs(samples[2*i+1]-mean)
+		}
+	}
+	errEst := math.Abs((kronrod - gauss) * half)
+	spread *= math.Abs(half)
+	if spread != 0 && errEst != 0 {
+		errEst = spread * math.Min(1, math.Pow(200*errEst/spread, 1.5))
+	}
+	if floor := 50 * float64Epsilon * absolute * math.Abs(half); floor > errEst {
+		errEst = floor
+	}
+	return segment{a: a, b: b, value: kronrod * half, errEst: errEst}, nil
+}
@@ -0,0 +1,93 @@
+package calculator
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestIntegrate(t *testing.T) {
+	inf := math.Inf(1)
+	cases := []struct {
+		expr         string
+		lower, upper float64
+		mode         AngleMode
+		want         float64
+	}{
+		{"x^2", 0, 3, AngleRadian, 9},
+		{"x^2", 3, 0, AngleRadian, -9},
+		{"sin(x)", 0, math.Pi, AngleRadian, 2},
+		{"sin(x)", 0, 180, AngleDegree, 360 / math.Pi},
+		{"exp(-x^2)", -inf, inf, AngleRadian, math.Sqrt(math.Pi)},
+		{"exp(-x)", 0, inf, AngleRadian, 1},
+		{"1/x^2", -inf, -1, AngleRadian, 1},
+		{"1/(1 + x^2)", -inf, inf, AngleRadian, math.Pi},
+		{"sqrt(x)", 0, 1, AngleRadian, 2.0 / 3},
+		{"1/sqrt(abs(x))", -1, 1, AngleRadian, 4},
+		{"ln(abs(x))", -1, 1, AngleRadian, -2},
+		{"k*x", 0, 2, AngleRadian, 6},
+		{"x", 2, 2, AngleRadian, 0},
+	}
+	p := New()
+	for _, tc := range cases {
+		opts := EvalOptions{AngleMode: tc.mode, Variables: map[string]float64{"k": 3}}
+		got, err := p.Integrate(tc.expr, "x", IntegralOptions{Lower: tc.lower, Upper: tc.upper}, opts)
+		if err != nil {
+			t.Errorf("∫ %s from %v to %v: %v", tc.expr, tc.lower, tc.upper, err)
+			continue
+		}
+		if math.Abs(got.Value-tc.want) > 1e-8 || !got.Converged {
+			t.Errorf("∫ %s from %v to %v = %+v, want %v", tc.expr, tc.lower, tc.upper, *got, tc.want)
+		}
+		if got.ErrorEstimate > 1e-8 {
+			t.Errorf("∫ %s: error estimate %v", tc.expr, got.ErrorEstimate)
+		}
+	}
+}
+
+func TestIntegrateBudget(t *testing.T) {
+	p := New()
+	got, err := p.Integrate("sin(1/x)", "x", IntegralOptions{Lower: 0.001, Upper: 1, MaxEvaluations: 45}, EvalOptions{AngleMode: AngleRadian})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Converged || got.Evaluations > 45 {
+		t.Errorf("with 45 evaluations: %+v", *got)
+	}
+
+	got, err = p.Integrate("x", "x", IntegralOptions{Lower: 0, Upper: 1}, EvalOptions{})
+	if err != nil || got.Evaluations != kronrodPoints || got.Intervals != 1 {
+		t.Errorf("a polynomial took %+v, %v", got, err)
+	}
+}
+
+func TestIntegrateErrors(t *testing.T) {
+	cases := []struct {
+		expr     string
+		variable string
+		bounds   IntegralOptions
+		kind     error
+		message  string
+	}{
+		{"1/x", "x", IntegralOptions{Lower: -1, Upper: 1}, ErrDomain, "not defined at x ="},
+		{"ln(x)", "x", IntegralOptions{Lower: -1, Upper: 1}, ErrDomain, "not defined"},
+		{"sqrt(x)", "x", IntegralOptions{Lower: -1, Upper: 0}, ErrDomain, "not defined"},
+		{"x*y", "x", IntegralOptions{Lower: 0, Upper: 1}, ErrUnknownVariable, ""},
+		{"x", "pi", IntegralOptions{Lower: 0, Upper: 1}, ErrInvalidName, ""},
+		{"x", "x", IntegralOptions{Lower: 0, Upper: 1, Tolerance: -1}, ErrDomain, "tolerance"},
+		{"x", "x", IntegralOptions{Lower: 0, Upper: 1, MaxEvaluations: 10}, ErrDomain, "evaluation limit"},
+		{"x", "x", IntegralOptions{Lower: math.NaN(), Upper: 1}, ErrDomain, "bounds"},
+	}
+	p := New()
+	for _, tc := range cases {
+		_, err := p.Integrate(tc.expr, tc.variable, tc.bounds, EvalOptions{})
+		if !errors.Is(err, tc.kind) {
+			t.Errorf("∫ %s d%s: error %v, want %v", tc.expr, tc.variable, err, tc.kind)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.message) {
+			t.Errorf("∫ %s d%s: error %q does not mention %q", tc.expr, tc.variable, err, tc.message)
+		}
+	}
+}
@@ -0,0 +1,420 @@
+package calculator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// IntType is the fixed-width integer type of programmer mode
+type IntType struct {
+	Bits     int // 8, 16, 32 or 64; 0 means 64
+	Unsigned bool
+	Wrap     bool // wrap arithmetic results modulo 2^Bits instead of reporting overflow
+}
+
+// withDefaults fills in the width when the caller left it unset
+func (t IntType) withDefaults() IntType {
+	if t.Bits == 0 {
+		t.Bits = 64
+	}
+	return t
+}
+
+// Valid reports whether the type has a supported width
+func (t IntType) Valid() bool {
+	switch t.withDefaults().Bits {
+	case 8, 16, 32, 64:
+		return true
+	}
+	return false
+}
+
+// String returns the Go-style name of the type, such as "int32" or "uint8"
+func (t IntType) String() string {
+	t = t.withDefaults()
+	if t.Unsigned {
+		return fmt.Sprintf("uint%d", t.Bits)
+	}
+	return fmt.Sprintf("int%d", t.Bits)
+}
+
+// modulus returns 2^Bits
+func (t IntType) modulus() *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(t.Bits))
+}
+
+// bounds returns the smallest and largest values of the type
+func (t IntType) bounds() (lo, hi *big.Int) {
+	if t.Unsigned {
+		return new(big.Int), new(big.Int).Sub(t.modulus(), big.NewInt(1))
+	}
+	half := new(big.Int).Lsh(big.NewInt(1), uint(t.Bits-1))
+	return new(big.Int).Neg(half), half.Sub(half, big.NewInt(1))
+}
+
+// wrap reduces value modulo 2^Bits into the range of the type
+func (t IntType) wrap(value *big.Int) *big.Int {
+	m := t.modulus()
+	result := new(big.Int).Mod(value, m)
+	if !t.Unsigned && result.Bit(t.Bits-1) == 1 {
+		result.Sub(result, m)
+	}
+	return result
+}
+
+// fit applies the overflow policy to an arithmetic result
+func (t IntType) fit(value *big.Int) (*big.Int, error) {
+	lo, hi := t.bounds()
+	if value.Cmp(lo) >= 0 && value.Cmp(hi) <= 0 {
+		return value, nil
+	}
+	if t.Wrap {
+		return t.wrap(value), nil
+	}
+	return nil, newErrorf(ErrOverflow, "%s overflows %s", value, t)
+}
+
+// IntResult is the outcome of a programmer-mode evaluation
+type IntResult struct {
+	Value *big.Int
+	Type  IntType
+}
+
+// Format writes the result in base 10, or as the two's-complement bit
+// pattern of the type in base 2, 8 or 16 with a 0b, 0o or 0x prefix
+func (r *IntResult) Format(base int) string {
+	if base == 10 {
+		return r.Value.String()
+	}
+	pattern := r.Value
+	if pattern.Sign() < 0 {
+		pattern = new(big.Int).Add(pattern, r.Type.modulus())
+	}
+	digits := pattern.Text(base)
+	switch base {
+	case 2:
+		return "0b" + digits
+	case 8:
+		return "0o" + digits
+	case 16:
+		return "0x" + strings.ToUpper(digits)
+	}
+	return digits
+}
+
+// intFunction is a built-in function of programmer mode
+type intFunction struct {
+	Arity
+	eval func(t IntType, args []*big.Int) (*big.Int, error)
+}
+
+// rotate rotates the bit pattern of value left by n places within the
+// width of t
+func rotate(t IntType, value, n *big.Int) *big.Int {
+	m := t.modulus()
+	pattern := new(big.Int).Mod(value, m)
+	shift := uint(new(big.Int).Mod(n, big.NewInt(int64(t.Bits))).Int64())
+	left := new(big.Int).Lsh(pattern, shift)
+	left.Or(left, new(big.Int).Rsh(pattern, uint(t.Bits)-shift))
+	return t.wrap(left)
+}
+
+// intFunctions lists the functions with a fixed-width meaning in
+// programmer mode. The exact integer functions are also available.
+var intFunctions = map[string]intFunction{
+	"abs": {Arity{1, 1}, func(t IntType, args []*big.Int) (*big.Int, error) {
+		return new(big.Int).Abs(args[0]), nil
+	}},
+	"min": {Arity{1, -1}, func(t IntType, args []*big.Int) (*big.Int, error) {
+		result := args[0]
+		for _, arg := range args[1:] {
+			if arg.Cmp(result) < 0 {
+				result = arg
+			}
+		}
+		return result, nil
+	}},
+	"max": {Arity{1, -1}, func(t IntType, args []*big.Int) (*big.Int, error) {
+		result := args[0]
+		for _, arg := range args[1:] {
+			if arg.Cmp(result) > 0 {
+				result = arg
+			}
+		}
+		return result, nil
+	}},
+	"mod": {Arity{2, 2}, func(t IntType, args []*big.Int) (*big.Int, error) {
+		return NewNumberTheoryOperations().FlooredMod(args[0], args[1])
+	}},
+	"rol": {Arity{2, 2}, func(t IntType, args []*big.Int) (*big.Int, error) {
+		return rotate(t, args[0], args[1]), nil
+	}},
+	"ror": {Arity{2, 2}, func(t IntType, args []*big.Int) (*big.Int, error) {
+		return rotate(t, args[0], new(big.Int).Neg(args[1])), nil
+	}},
+}
+
+// intEvaluation carries the state of a single programmer-mode evaluation
+type intEvaluation struct {
+	parser *ExpressionParser
+	opts   EvalOptions
+	typ    IntType
+}
+
+// EvaluateInt parses and evaluates an expression on fixed-width integers
+// of the type opts.Int
+func (p *ExpressionParser) EvaluateInt(expression string, opts EvalOptions) (*IntResult, error) {
+	prog, err := p.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	return prog.RunInt(opts)
+}
+
+// RunInt evaluates the program on fixed-width integers of the type opts.Int
+func (prog *Program) RunInt(opts EvalOptions) (*IntResult, error) {
+	opts = prog.parser.withDefaults(opts)
+	typ := opts.Int.withDefaults()
+	if !typ.Valid() {
+		return nil, newErrorf(ErrDomain, "unsupported integer width %d", typ.Bits)
+	}
+	ev := &intEvaluation{parser: prog.parser, opts: opts, typ: typ}
+	value, err := ev.eval(prog.tree)
+	if err != nil {
+		return nil, err
+	}
+	return &IntResult{Value: value, Type: typ}, nil
+}
+
+// fit applies the overflow policy, locating an overflow at span
+func (ev *intEvaluation) fit(value *big.Int, span Span) (*big.Int, error) {
+	result, err := ev.typ.fit(value)
+	if err != nil {
+		return nil, locate(err, span, "")
+	}
+	return result, nil
+}
+
+// literal converts a number literal to an integer. Prefixed literals are
+// bit patterns, so 0xFF is -1 as an int8.
+func (ev *intEvaluation) literal(n *NumberNode) (*big.Int, error) {
+	value, err := parseRationalLiteral(n.Text)
+	if err != nil {
+		return nil, locate(err, n.span, "")
+	}
+	if !value.IsInt() {
+		return nil, errorAt(ErrDomain, n.span, "'%s' is not an integer", n.Text)
+	}
+	result := value.Num()
+	if len(n.Text) > 2 && n.Text[0] == '0' {
+		if _, prefixed := numberBases[rune(n.Text[1])]; prefixed && result.Cmp(ev.typ.modulus()) < 0 {
+			return ev.typ.wrap(result), nil
+		}
+	}
+	return result, nil
+}
+
+// eval walks an expression tree and computes its value
+func (ev *intEvaluation) eval(node Node) (*big.Int, error) {
+	switch n := node.(type) {
+	case *NumberNode:
+		value, err := ev.literal(n)
+		if err != nil {
+			return nil, err
+		}
+		return ev.fit(value, n.span)
+
+	case *IdentNode:
+		value, ok := ev.opts.lookup(n.Name)
+		if !ok {
+			value, ok = ev.parser.constants[n.Name]
+		}
+		if !ok {
+			return nil, errorAt(ErrUnknownVariable, n.span, "unknown identifier '%s'", n.Name)
+		}
+		if value != math.Trunc(value) || math.IsInf(value, 0) {
+			return nil, errorAt(ErrDomain, n.span, "'%s' is not an integer", n.Name)
+		}
+		result, _ := big.NewFloat(value).Int(nil)
+		return ev.fit(result, n.span)
+
+	case *UnaryNode:
+		// Negate literals before fitting them so that -128 is an int8
+		if literal, ok := n.Operand.(*NumberNode); ok && n.Op == "-" {
+			value, err := ev.literal(literal)
+			if err != nil {
+				return nil, err
+			}
+			return ev.fit(new(big.Int).Neg(value), n.span)
+		}
+		operand, err := ev.eval(n.Operand)
+		if err != nil {
+			return nil, err
+		}
+		switch n.Op {
+		case "-":
+			return ev.fit(new(big.Int).Neg(operand), n.span)
+		case "~":
+			return ev.typ.wrap(new(big.Int).Not(operand)), nil
+		}
+		return operand, nil
+
+	case *PostfixNode:
+		operand, err := ev.eval(n.Operand)
+		if err != nil {
+			return nil, err
+		}
+		if operand.Sign() < 0 {
+			return nil, errorAt(ErrDomain, n.span, "factorial error: factorial not defined for negative numbers")
+		}
+		// n! has at least n/2 factors of two, so beyond twice the width
+		// it is zero modulo 2^Bits and overflows otherwise
+		if operand.Cmp(big.NewInt(int64(2*ev.typ.Bits))) > 0 {
+			if ev.typ.Wrap {
+				return new(big.Int), nil
+			}
+			return nil, errorAt(ErrOverflow, n.span, "factorial error: %s! overflows %s", operand, ev.typ)
+		}
+		return ev.fit(new(big.Int).MulRange(1, operand.Int64()), n.span)
+
+	case *BinaryNode:
+		return ev.evalBinary(n)
+
+	case *CallNode:
+		return ev.evalCall(n)
+	}
+
+	return nil, unsupportedNode(node, "integer mode")
+}
+
+// evalBinary evaluates both operands and applies an infix operator.
+// Arithmetic and left shifts follow the overflow policy; the other
+// bitwise operators always wrap.
+func (ev *intEvaluation) evalBinary(n *BinaryNode) (*big.Int, error) {
+	left, err := ev.eval(n.Left)
+	if err != nil {
+		return nil, err
+	}
+	var right *big.Int
+	switch n.Op {
+	case "^", "<<", ">>":
+		right, err = ev.count(n.Right)
+	default:
+		right, err = ev.eval(n.Right)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Op {
+	case "+":
+		return ev.fit(new(big.Int).Add(left, right), n.span)
+	case "-":
+		return ev.fit(new(big.Int).Sub(left, right), n.span)
+	case "*":
+		return ev.fit(new(big.Int).Mul(left, right), n.span)
+	case "/":
+		if right.Sign() == 0 {
+			return nil, errorAt(ErrDivisionByZero, n.span, "division by zero")
+		}
+		return ev.fit(new(big.Int).Quo(left, right), n.span)
+	case "^":
+		return ev.power(left, right, n.span)
+	case "&":
+		return ev.typ.wrap(new(big.Int).And(left, right)), nil
+	case "|":
+		return ev.typ.wrap(new(big.Int).Or(left, right)), nil
+	case "xor":
+		return ev.typ.wrap(new(big.Int).Xor(left, right)), nil
+	case "<<", ">>":
+		if right.Sign() < 0 {
+			return nil, errorAt(ErrDomain, n.span, "negative shift count %s", right)
+		}
+		// Shifting by the width or more clears every bit
+		count := uint(ev.typ.Bits)
+		if right.Cmp(big.NewInt(int64(count))) < 0 {
+			count = uint(right.Int64())
+		}
+		if n.Op == "<<" {
+			if ev.typ.Wrap {
+				return ev.typ.wrap(new(big.Int).Lsh(left, count)), nil
+			}
+			if left.Sign() != 0 && right.Cmp(big.NewInt(int64(ev.typ.Bits))) >= 0 {
+				return nil, errorAt(ErrOverflow, n.span, "%s << %s overflows %s", left, right, ev.typ)
+			}
+			return ev.fit(new(big.Int).Lsh(left, count), n.span)
+		}
+		// Rsh of a negative big.Int is an arithmetic shift
+		return new(big.Int).Rsh(left, count), nil
+	}
+
+	return nil, unsupportedOperator(n.Op, n.span)
+}
+
+// count evaluates an exponent or shift count. A literal is taken as
+// written rather than fitted to the type, so 2^200 and 1 << 200 are
+// checked against the width instead of using a wrapped count.
+func (ev *intEvaluation) count(node Node) (*big.Int, error) {
+	if literal, ok := node.(*NumberNode); ok {
+		return ev.literal(literal)
+	}
+	return ev.eval(node)
+}
+
+// power raises base to a non-negative integer exponent
+func (ev *intEvaluation) power(base, exponent *big.Int, span Span) (*big.Int, error) {
+	if exponent.Sign() < 0 {
+		return nil, errorAt(ErrDomain, span, "negative exponent %s in integer mode", exponent)
+	}
+	if ev.typ.Wrap {
+		return ev.typ.wrap(new(big.Int).Exp(base, exponent, ev.typ.modulus())), nil
+	}
+	// Any base other than -1, 0 and 1 overflows beyond Bits
+	if base.CmpAbs(big.NewInt(1)) > 0 && exponent.Cmp(big.NewInt(int64(ev.typ.Bits))) > 0 {
+		return nil, errorAt(ErrOverflow, span, "%s^%s overflows %s", base, exponent, ev.typ)
+	}
+	return ev.fit(new(big.Int).Exp(base, exponent, nil), span)
+}
+
+// evalCall evaluates a function call
+func (ev *intEvaluation) evalCall(n *CallNode) (*big.Int, error) {
+	var (
+		arity Arity
+		apply func(args []*big.Int) (*big.Int, error)
+	)
+	if fn, ok := intFunctions[n.Name]; ok {
+		arity = fn.Arity
+		apply = func(args []*big.Int) (*big.Int, error) {
+			return fn.eval(ev.typ, args)
+		}
+	} else if fn, ok := integerFunctions[n.Name]; ok {
+		arity, apply = fn.Arity, fn.eval
+	} else {
+		_, builtin := ev.parser.functions[n.Name]
+		_, user := ev.opts.userFunction(n.Name)
+		if builtin || user {
+			return nil, errorAt(ErrUnsupported, n.span, "function %s is not supported in integer mode", n.Name)
+		}
+		return nil, errorAt(ErrUnknownFunction, n.span, "unknown function '%s'", n.Name)
+	}
+	if err := arity.checkArity(n.Name, len(n.Args)); err != nil {
+		return nil, locate(err, n.span, "")
+	}
+
+	args := make([]*big.Int, len(n.Args))
+	for i, argNode := range n.Args {
+		arg, err := ev.eval(argNode)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = arg
+	}
+
+	result, err := apply(args)
+	if err != nil {
+		return nil, locate(err, n.span, "error in "+n.Name+" function: ")
+	}
+	return ev.fit(result, n.span)
+}
@@ -0,0 +1,179 @@
+package calculator
+
+import (
+	"errors"
+	"testing"
+)
+
+var (
+	int8Wrap    = IntType{Bits: 8, Wrap: true}
+	int8Strict  = IntType{Bits: 8}
+	uint8Wrap   = IntType{Bits: 8, Unsigned: true, Wrap: true}
+	uint8Strict = IntType{Bits: 8, Unsigned: true}
+	int32Wrap   = IntType{Bits: 32, Wrap: true}
+	int64Wrap   = IntType{Wrap: true}
+	uint64Wrap  = IntType{Bits: 64, Unsigned: true, Wrap: true}
+)
+
+func TestEvaluateInt(t *testing.T) {
+	cases := []struct {
+		expr string
+		typ  IntType
+		want string
+	}{
+		{"7 / 2", int32Wrap, "3"},
+		{"-7 / 2", int32Wrap, "-3"},
+		{"2^10", int32Wrap, "1024"},
+		{"5!", int32Wrap, "120"},
+		{"0xF0 | 0x0F", int32Wrap, "255"},
+		{"0b1100 & 0b1010", int32Wrap, "8"},
+		{"0b1100 xor 0b1010", int32Wrap, "6"},
+		{"~0", int32Wrap, "-1"},
+		{"~0", uint8Wrap, "255"},
+		{"1 << 4", int32Wrap, "16"},
+		{"-16 >> 2", int32Wrap, "-4"},
+		{"1 << 40", int32Wrap, "0"},
+		{"0o17 + 1", int32Wrap, "16"},
+		{"rol(0x81, 1)", uint8Wrap, "3"},
+		{"ror(1, 1)", uint8Wrap, "128"},
+		{"rol(1, 9)", uint8Wrap, "2"},
+		{"rol(-128, 1)", int8Wrap, "1"},
+		{"mod(-7, 3)", int32Wrap, "2"},
+		{"gcd(12, 18)", int32Wrap, "6"},
+		{"max(3, -4, 2)", int32Wrap, "3"},
+
+		// Two's-complement wraparound
+		{"127 + 1", int8Wrap, "-128"},
+		{"-128 - 1", int8Wrap, "127"},
+		{"255 + 1", uint8Wrap, "0"},
+		{"0 - 1", uint8Wrap, "255"},
+		{"16 * 16", uint8Wrap, "0"},
+		{"3^5", int8Wrap, "-13"},
+		{"2^64", int64Wrap, "0"},
+		{"2^200", int8Wrap, "0"},
+		{"3^200", int8Wrap, "-95"},
+		{"1 << 7", int8Wrap, "-128"},
+		{"1 << 200", int8Wrap, "0"},
+		{"-(-128)", int8Wrap, "-128"},
+		{"20!", int8Wrap, "0"},
+		{"0xFF", int8Wrap, "-1"},
+		{"0xFFFFFFFFFFFFFFFF", uint64Wrap, "18446744073709551615"},
+		{"0xFFFFFFFFFFFFFFFF", int64Wrap, "-1"},
+
+		// The extremes fit without wrapping
+		{"-128", int8Strict, "-128"},
+		{"127", int8Strict, "127"},
+		{"255", uint8Strict, "255"},
+		{"0x80", int8Strict, "-128"},
+		{"1 << 6", int8Strict, "64"},
+		{"-1 << 7", int8Strict, "-128"},
+		{"1 << 7", uint8Strict, "128"},
+		{"0 << 100", int8Strict, "0"},
+	}
+	p := New()
+	for _, tc := range cases {
+		got, err := p.EvaluateInt(tc.expr, EvalOptions{Int: tc.typ})
+		if err != nil {
+			t.Errorf("%s as %s: %v", tc.expr, tc.typ, err)
+			continue
+		}
+		if got.Value.String() != tc.want {
+			t.Errorf("%s as %s = %s, want %s", tc.expr, tc.typ, got.Value, tc.want)
+		}
+	}
+}
+
+func TestEvaluateIntErrors(t *testing.T) {
+	cases := []struct {
+		expr string
+		typ  IntType
+		kind error
+	}{
+		{"127 + 1", int8Strict, ErrOverflow},
+		{"-128 - 1", int8Strict, ErrOverflow},
+		{"0 - 1", uint8Strict, ErrOverflow},
+		{"-1", uint8Strict, ErrOverflow},
+		{"128", int8Strict, ErrOverflow},
+		{"16 * 16", uint8Strict, ErrOverflow},
+		{"2^8", uint8Strict, ErrOverflow},
+		{"3^100", int8Strict, ErrOverflow},
+		{"2^200", int8Strict, ErrOverflow},
+		{"1 << 7", int8Strict, ErrOverflow},
+		{"1 << 70", int8Strict, ErrOverflow},
+		{"1 << 8", uint8Strict, ErrOverflow},
+		{"6!", int8Strict, ErrOverflow},
+		{"abs(-128)", int8Strict, ErrOverflow},
+		{"-(-128)", int8Strict, ErrOverflow},
+		{"1 / 0", int32Wrap, ErrDivisionByZero},
+		{"mod(1, 0)", int32Wrap, ErrDivisionByZero},
+		{"1.5 + 1", int32Wrap, ErrDomain},
+		{"2^-1", int32Wrap, ErrDomain},
+		{"1 << -1", int32Wrap, ErrDomain},
+		{"(-3)!", int32Wrap, ErrDomain},
+		{"sqrt(4)", int32Wrap, ErrUnsupported},
+		{"pi", int32Wrap, ErrDomain},
+		{"nosuch(1)", int32Wrap, ErrUnknownFunction},
+		{"1", IntType{Bits: 12}, ErrDomain},
+	}
+	p := New()
+	for _, tc := range cases {
+		if _, err := p.EvaluateInt(tc.expr, EvalOptions{Int: tc.typ}); !errors.Is(err, tc.kind) {
+			t.Errorf("%s as %s: error %v, want %v", tc.expr, tc.typ, err, tc.kind)
+		}
+	}
+
+	// Bitwise operators need integer mode; ^ stays a power elsewhere
+	if _, err := p.Evaluate("6 & 3", EvalOptions{}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("6 & 3 in float mode: error %v, want %v", err, ErrUnsupported)
+	}
+	if got, err := p.Evaluate("2^3", EvalOptions{}); err != nil || got != 8 {
+		t.Errorf("2^3 in float mode = %v, %v", got, err)
+	}
+}
+
+func TestIntResultFormat(t *testing.T) {
+	cases := []struct {
+		expr string
+		typ  IntType
+		base int
+		want string
+	}{
+		{"255", int32Wrap, 16, "0xFF"},
+		{"255", int32Wrap, 2, "0b11111111"},
+		{"8", int32Wrap, 8, "0o10"},
+		{"-1", int8Wrap, 16, "0xFF"},
+		{"-1", int8Wrap, 2, "0b11111111"},
+		{"-1", int8Wrap, 10, "-1"},
+		{"-128", int8Wrap, 8, "0o200"},
+		{"-1", int32Wrap, 16, "0xFFFFFFFF"},
+		{"0", int32Wrap, 2, "0b0"},
+	}
+	p := New()
+	for _, tc := range cases {
+		result, err := p.EvaluateInt(tc.expr, EvalOptions{Int: tc.typ})
+		if err != nil {
+			t.Errorf("%s: %v", tc.expr, err)
+			continue
+		}
+		if got := result.Format(tc.base); got != tc.want {
+			t.Errorf("%s as %s in base %d = %s, want %s", tc.expr, tc.typ, tc.base, got, tc.want)
+		}
+	}
+}
+
+func TestIntTypeString(t *testing.T) {
+	cases := []struct {
+		typ  IntType
+		want string
+	}{
+		{IntType{}, "int64"},
+		{IntType{Bits: 8}, "int8"},
+		{IntType{Bits: 16, Unsigned: true}, "uint16"},
+		{IntType{Bits: 64, Unsigned: true}, "uint64"},
+	}
+	for _, tc := range cases {
+		if got := tc.typ.String(); got != tc.want {
+			t.Errorf("%+v: got %s, want %s", tc.typ, got, tc.want)
+		}
+	}
+}
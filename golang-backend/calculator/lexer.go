@@ -0,0 +1,165 @@
+package calculator
+
+import (
+	"unicode"
+)
+
+// tokenKind identifies the lexical class of a token
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOperator
+	tokLParen
+	tokRParen
+	tokComma
+	tokAssign
+	tokLBracket
+	tokRBracket
+	tokSemicolon
+)
+
+// token is a single lexical unit of an expression. Pos and End are rune
+// offsets into the original expression.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+	end  int
+}
+
+// operatorAliases maps alternative operator spellings to their canonical form
+var operatorAliases = map[rune]string{
+	'×': "*",
+	'÷': "/",
+	'−': "-",
+}
+
+// keywordOperators lists operators spelled as words. XOR has no symbol
+// because ^ is exponentiation.
+var keywordOperators = map[string]bool{
+	"xor": true,
+}
+
+// tokenize splits an expression into tokens
+func tokenize(expr string) ([]token, error) {
+	src := []rune(expr)
+	var tokens []token
+
+	for i := 0; i < len(src); {
+		ch := src[i]
+		start := i
+
+		switch {
+		case unicode.IsSpace(ch):
+			i++
+			continue
+
+		case isDigit(ch), ch == '.' && i+1 < len(src) && isDigit(src[i+1]):
+			end, err := scanNumber(src, start)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, token{tokNumber, string(src[start:i]), start, i})
+			continue
+
+		case ch == 'π':
+			// π never joins a longer identifier, so iπ and 2xπ multiply
+			tokens = append(tokens, token{tokIdent, "π", start, start + 1})
+			i++
+			continue
+
+		case isIdentStart(ch):
+			for i < len(src) && isIdentPart(src[i]) && src[i] != 'π' {
+				i++
+			}
+			kind := tokIdent
+			if keywordOperators[string(src[start:i])] {
+				kind = tokOperator
+			}
+			tokens = append(tokens, token{kind, string(src[start:i]), start, i})
+			continue
+
+		case ch == '(':
+			tokens = append(tokens, token{tokLParen, "(", start, start + 1})
+			i++
+			continue
+
+		case ch == ')':
+			tokens = append(tokens, token{tokRParen, ")", start, start + 1})
+			i++
+			continue
+
+		case ch == ',':
+			tokens = append(tokens, token{tokComma, ",", start, start + 1})
+			i++
+			continue
+
+		case ch == '[':
+			tokens = append(tokens, token{tokLBracket, "[", start, start + 1})
+			i++
+			continue
+
+		case ch == ']':
+			tokens = append(tokens, token{tokRBracket, "]", start, start + 1})
+			i++
+			continue
+
+		case ch == ';':
+			tokens = append(tokens, token{tokSemicolon, ";", start, start + 1})
+			i++
+			continue
+
+		case ch == '=':
+			tokens = append(tokens, token{tokAssign, "=", start, start + 1})
+			i++
+			continue
+		}
+
+		if alias, ok := operatorAliases[ch]; ok {
+			tokens = append(tokens, token{tokOperator, alias, start, start + 1})
+			i++
+			continue
+		}
+
+		switch ch {
+		case '<', '>':
+			if i+1 < len(src) && src[i+1] == ch {
+				tokens = append(tokens, token{tokOperator, string(src[start : start+2]), start, start + 2})
+				i += 2
+				continue
+			}
+		case '*':
+			if i+1 < len(src) && src[i+1] == '*' {
+				tokens = append(tokens, token{tokOperator, "^", start, start + 2})
+				i += 2
+				continue
+			}
+			fallthrough
+		case '+', '-', '/', '^', '!', '&', '|', '~':
+			tokens = append(tokens, token{tokOperator, string(ch), start, start + 1})
+			i++
+			continue
+		}
+
+		return nil, errorAt(ErrSyntax, Span{start, start + 1}, "unexpected character '%c'", ch)
+	}
+
+	tokens = append(tokens, token{tokEOF, "", len(src), len(src)})
+	return tokens, nil
+}
+
+func isDigit(ch rune) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isIdentStart(ch rune) bool {
+	return ch == '_' || unicode.IsLetter(ch)
+}
+
+func isIdentPart(ch rune) bool {
+	return isIdentStart(ch) || isDigit(ch)
+}
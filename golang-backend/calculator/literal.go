@@ -0,0 +1,114 @@
+package calculator
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// numberBases maps radix prefix letters to their base
+var numberBases = map[rune]int{
+	'x': 16, 'X': 16,
+	'b': 2, 'B': 2,
+	'o': 8, 'O': 8,
+}
+
+// scanNumber returns the end offset of the numeric literal starting at
+// src[start]. It accepts decimal literals with an optional fraction and
+// exponent (1.5e-3, .5, 6.02E23), 0x/0b/0o prefixed integers, and
+// underscores between digits as separators (1_000_000).
+func scanNumber(src []rune, start int) (int, error) {
+	i := start
+
+	if src[i] == '0' && i+2 < len(src) {
+		if base, ok := numberBases[src[i+1]]; ok && isDigitInBase(src[i+2], base) {
+			return scanDigits(src, i+2, base)
+		}
+	}
+
+	var err error
+	if i, err = scanDigits(src, i, 10); err != nil {
+		return 0, err
+	}
+	if i+1 < len(src) && src[i] == '.' && isDigit(src[i+1]) {
+		if i, err = scanDigits(src, i+1, 10); err != nil {
+			return 0, err
+		}
+	}
+
+	// An exponent is only consumed when digits follow, so that 2e is 2·e
+	if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+		j := i + 1
+		if j < len(src) && (src[j] == '+' || src[j] == '-') {
+			j++
+		}
+		if j < len(src) && isDigit(src[j]) {
+			return scanDigits(src, j, 10)
+		}
+	}
+
+	return i, nil
+}
+
+// scanDigits consumes digits of the given base, allowing single
+// underscores between them
+func scanDigits(src []rune, i, base int) (int, error) {
+	for i < len(src) {
+		switch {
+		case isDigitInBase(src[i], base):
+			i++
+		case src[i] == '_':
+			if i == 0 || !isDigitInBase(src[i-1], base) || i+1 >= len(src) || !isDigitInBase(src[i+1], base) {
+				return 0, errorAt(ErrSyntax, Span{i, i + 1}, "invalid digit separator")
+			}
+			i++
+		default:
+			return i, nil
+		}
+	}
+	return i, nil
+}
+
+func isDigitInBase(ch rune, base int) bool {
+	digit, ok := digitValue(ch)
+	return ok && digit < base
+}
+
+// digitValue returns the value of a digit in bases up to 36, where the
+// letters a to z, in either case, stand for 10 to 35
+func digitValue(ch rune) (int, bool) {
+	switch {
+	case ch >= '0' && ch <= '9':
+		return int(ch - '0'), true
+	case ch >= 'a' && ch <= 'z':
+		return int(ch-'a') + 10, true
+	case ch >= 'A' && ch <= 'Z':
+		return int(ch-'A') + 10, true
+	}
+	return 0, false
+}
+
+// parseNumberLiteral converts a literal accepted by scanNumber to a float64.
+// Literals beyond float64 range yield ±Inf so that arbitrary-precision
+// evaluation, which reparses the literal text, can still accept them.
+func parseNumberLiteral(text string) (float64, error) {
+	text = strings.ReplaceAll(text, "_", "")
+
+	if len(text) > 2 && text[0] == '0' {
+		if base, ok := numberBases[rune(text[1])]; ok {
+			n, ok := new(big.Int).SetString(text[2:], base)
+			if !ok {
+				return 0, newErrorf(ErrSyntax, "invalid number '%s'", text)
+			}
+			value, _ := new(big.Float).SetInt(n).Float64()
+			return value, nil
+		}
+	}
+
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return 0, newErrorf(ErrSyntax, "invalid number '%s'", text)
+	}
+	return value, nil
+}
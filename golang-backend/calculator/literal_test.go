@@ -0,0 +1,66 @@
+package calculator
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNumberLiterals(t *testing.T) {
+	cases := []struct {
+		expression string
+		want       float64
+	}{
+		{"1.5e3", 1500},
+		{"1.5e-3", 0.0015},
+		{"6.02E23", 6.02e23},
+		{"2e+2", 200},
+		{".5", 0.5},
+		{".5e1", 5},
+		{"1_000_000", 1e6},
+		{"0.000_1", 1e-4},
+		{"0xff", 255},
+		{"0XFF", 255},
+		{"0xdead_beef", 0xdeadbeef},
+		{"0b1010", 10},
+		{"0o17", 15},
+		{"2e", 2 * math.E},
+		{"e^2", math.E * math.E},
+		{"exp(1) - e", 0},
+		{"ceil(1.2e0)", 2},
+		{"sec(0)", 1},
+	}
+	for _, tc := range cases {
+		got, err := New().Evaluate(tc.expression, EvalOptions{})
+		if err != nil {
+			t.Errorf("%s: %v", tc.expression, err)
+			continue
+		}
+		if math.Abs(got-tc.want) > 1e-12*math.Max(1, math.Abs(tc.want)) {
+			t.Errorf("%s = %v, want %v", tc.expression, got, tc.want)
+		}
+	}
+}
+
+func TestNumberLiteralErrors(t *testing.T) {
+	cases := []struct {
+		expression string
+		span       Span
+	}{
+		{"1__000", Span{1, 2}},
+		{"1_", Span{1, 2}},
+		{"1_000_", Span{5, 6}},
+		{"1.5.2", Span{3, 5}},
+	}
+	for _, tc := range cases {
+		_, err := New().Evaluate(tc.expression, EvalOptions{})
+		var calcErr *Error
+		if !errors.As(err, &calcErr) || !errors.Is(err, ErrSyntax) {
+			t.Errorf("%s: error %v, want a syntax error", tc.expression, err)
+			continue
+		}
+		if !calcErr.Located() || calcErr.Span != tc.span {
+			t.Errorf("%s: span %v, want %v", tc.expression, calcErr.Span, tc.span)
+		}
+	}
+}
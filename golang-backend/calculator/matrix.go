@@ -0,0 +1,389 @@
+package calculator
+
+import (
+	"calculator-backend/linalg"
+	"errors"
+	"math"
+)
+
+// MatrixResult is the outcome of evaluating an expression that involves
+// matrices. Matrix is nil when the expression reduced to a number, as
+// det([1, 2; 3, 4]) does.
+type MatrixResult struct {
+	Matrix *linalg.Matrix
+	Scalar float64
+}
+
+// IsMatrix reports whether the result is a matrix
+func (r *MatrixResult) IsMatrix() bool {
+	return r.Matrix != nil
+}
+
+// matrixFunction is a built-in function on matrices
+type matrixFunction struct {
+	Arity
+	eval func(args []*MatrixResult) (*MatrixResult, error)
+}
+
+// matrixFunctions are the functions only available in matrix
+// expressions. A number passed where a matrix is expected is a 1×1 matrix.
+var matrixFunctions = map[string]matrixFunction{
+	"det": {Arity{1, 1}, func(args []*MatrixResult) (*MatrixResult, error) {
+		det, err := args[0].asMatrix().Det()
+		return &MatrixResult{Scalar: det}, err
+	}},
+	"inv": {Arity{1, 1}, func(args []*MatrixResult) (*MatrixResult, error) {
+		inv, err := args[0].asMatrix().Inverse()
+		return &MatrixResult{Matrix: inv}, err
+	}},
+	"transpose": {Arity{1, 1}, func(args []*MatrixResult) (*MatrixResult, error) {
+		return &MatrixResult{Matrix: args[0].asMatrix().Transpose()}, nil
+	}},
+	"rank": {Arity{1, 1}, func(args []*MatrixResult) (*MatrixResult, error) {
+		return &MatrixResult{Scalar: float64(args[0].asMatrix().Rank())}, nil
+	}},
+	"trace": {Arity{1, 1}, func(args []*MatrixResult) (*MatrixResult, error) {
+		trace, err := args[0].asMatrix().Trace()
+		return &MatrixResult{Scalar: trace}, err
+	}},
+	"cond": {Arity{1, 1}, func(args []*MatrixResult) (*MatrixResult, error) {
+		cond, err := args[0].asMatrix().Condition()
+		if err == nil && math.IsInf(cond, 1) {
+			err = &linalg.ConditionError{Condition: cond}
+		}
+		return &MatrixResult{Scalar: cond}, err
+	}},
+	"solve": {Arity{2, 2}, func(args []*MatrixResult) (*MatrixResult, error) {
+		x, err := linalg.Solve(args[0].asMatrix(), args[1].asMatrix())
+		return &MatrixResult{Matrix: x}, err
+	}},
+	"identity": {Arity{1, 1}, func(args []*MatrixResult) (*MatrixResult, error) {
+		n := args[0]
+		if n.IsMatrix() || n.Scalar != math.Trunc(n.Scalar) || n.Scalar < 1 || n.Scalar > linalg.MaxSize {
+			return nil, newErrorf(ErrDomain, "size must be an integer between 1 and %d", linalg.MaxSize)
+		}
+		return &MatrixResult{Matrix: linalg.Identity(int(n.Scalar))}, nil
+	}},
+}
+
+// asMatrix returns the matrix of r, treating a number as a 1×1 matrix
+func (r *MatrixResult) asMatrix() *linalg.Matrix {
+	if r.IsMatrix() {
+		return r.Matrix
+	}
+	m, _ := linalg.FromRows([][]float64{{r.Scalar}})
+	return m
+}
+
+// IsMatrix reports whether the program contains a matrix literal or a
+// matrix function, and so must be evaluated with RunMatrix
+func (prog *Program) IsMatrix() bool {
+	return hasMatrix(prog.tree)
+}
+
+// hasMatrix reports whether a matrix literal or matrix function appears
+// in the tree
+func hasMatrix(node Node) bool {
+	return matrixSubtrees(node, map[Node]bool{})
+}
+
+// matrixSubtrees records in found every node whose subtree contains a
+// matrix literal or matrix function, and reports whether node does. One
+// pass lets evaluation tell plain numeric subtrees apart without walking
+// each of them again.
+func matrixSubtrees(node Node, found map[Node]bool) bool {
+	contains := false
+	switch n := node.(type) {
+	case *MatrixNode:
+		contains = true
+		for _, row := range n.Rows {
+			for _, entry := range row {
+				matrixSubtrees(entry, found)
+			}
+		}
+	case *CallNode:
+		_, contains = matrixFunctions[n.Name]
+		for _, arg := range n.Args {
+			if matrixSubtrees(arg, found) {
+				contains = true
+			}
+		}
+	case *UnaryNode:
+		contains = matrixSubtrees(n.Operand, found)
+	case *PostfixNode:
+		contains = matrixSubtrees(n.Operand, found)
+	case *BinaryNode:
+		left := matrixSubtrees(n.Left, found)
+		right := matrixSubtrees(n.Right, found)
+		contains = left || right
+	}
+	if contains {
+		found[node] = true
+	}
+	return contains
+}
+
+// EvaluateMatrix parses and evaluates an expression whose values may be
+// matrices, such as [1, 2; 3, 4]^2 or det([1, 2; 3, 4])
+func (p *ExpressionParser) EvaluateMatrix(expression string, opts EvalOptions) (*MatrixResult, error) {
+	prog, err := p.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	return prog.RunMatrix(opts)
+}
+
+// RunMatrix evaluates the program on matrices and numbers. Parts of the
+// expression without matrices are evaluated as by Run.
+func (prog *Program) RunMatrix(opts EvalOptions) (*MatrixResult, error) {
+	opts = prog.parser.withDefaults(opts)
+	ev := &matrixEvaluation{scalar: &evaluation{parser: prog.parser, opts: opts}, matrix: map[Node]bool{}}
+	matrixSubtrees(prog.tree, ev.matrix)
+	result, err := ev.eval(prog.tree)
+	if err != nil {
+		return nil, err
+	}
+	if !result.IsMatrix() {
+		return &MatrixResult{Scalar: roundSignificant(result.Scalar, opts.Digits)}, nil
+	}
+	rows := result.Matrix.ToRows()
+	for _, row := range rows {
+		for j, v := range row {
+			row[j] = roundSignificant(v, opts.Digits)
+		}
+	}
+	m, _ := linalg.FromRows(rows)
+	return &MatrixResult{Matrix: m}, nil
+}
+
+// matrixError locates an error from a matrix operation at span. Errors
+// from linalg become the kind of the calculator error, so their codes and
+// condition numbers stay reachable with errors.Is and errors.As.
+func matrixError(err error, span Span, prefix string) error {
+	var calcErr *Error
+	if errors.As(err, &calcErr) {
+		return locate(err, span, prefix)
+	}
+	return &Error{Kind: err, Message: prefix + err.Error(), Span: span, located: true}
+}
+
+// matrixEvaluation carries the state of a single matrix evaluation.
+// Numbers are computed by the float evaluation it wraps; matrix holds the
+// nodes whose subtrees involve matrices.
+type matrixEvaluation struct {
+	scalar *evaluation
+	matrix map[Node]bool
+}
+
+// scalarResult wraps a number as a result
+func scalarResult(value float64) *MatrixResult {
+	return &MatrixResult{Scalar: value}
+}
+
+// eval walks an expression tree and computes its value
+func (ev *matrixEvaluation) eval(node Node) (*MatrixResult, error) {
+	if !ev.matrix[node] {
+		value, err := ev.scalar.eval(node)
+		if err != nil {
+			return nil, err
+		}
+		return scalarResult(value), nil
+	}
+
+	switch n := node.(type) {
+	case *MatrixNode:
+		rows := make([][]float64, len(n.Rows))
+		for i, row := range n.Rows {
+			rows[i] = make([]float64, len(row))
+			for j, entry := range row {
+				value, err := ev.eval(entry)
+				if err != nil {
+					return nil, err
+				}
+				if value.IsMatrix() {
+					return nil, errorAt(ErrUnsupported, entry.Span(), "matrix entries must be numbers")
+				}
+				rows[i][j] = value.Scalar
+			}
+		}
+		m, err := linalg.FromRows(rows)
+		if err != nil {
+			return nil, matrixError(err, n.span, "")
+		}
+		return &MatrixResult{Matrix: m}, nil
+
+	case *UnaryNode:
+		operand, err := ev.eval(n.Operand)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case n.Op == "+":
+			return operand, nil
+		case n.Op != "-":
+			return nil, unsupportedOperator(n.Op, n.span)
+		case operand.IsMatrix():
+			return &MatrixResult{Matrix: operand.Matrix.Scale(-1)}, nil
+		}
+		return scalarResult(ev.scalar.parser.basic.Negate(operand.Scalar)), nil
+
+	case *PostfixNode:
+		operand, err := ev.eval(n.Operand)
+		if err != nil {
+			return nil, err
+		}
+		if operand.IsMatrix() {
+			return nil, errorAt(ErrDomain, n.span, "factorial of a matrix is not defined")
+		}
+		result, err := ev.scalar.parser.basic.Factorial(operand.Scalar)
+		if err != nil {
+			return nil, locate(err, n.span, "factorial error: ")
+		}
+		return scalarResult(result), nil
+
+	case *BinaryNode:
+		return ev.evalBinary(n)
+
+	case *CallNode:
+		return ev.evalCall(n)
+	}
+
+	return nil, unsupportedNode(node, "")
+}
+
+// evalBinary evaluates both operands and applies an infix operator.
+// Numbers scale matrices; a matrix may be divided by a number and raised
+// to an integer power.
+func (ev *matrixEvaluation) evalBinary(n *BinaryNode) (*MatrixResult, error) {
+	left, err := ev.eval(n.Left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := ev.eval(n.Right)
+	if err != nil {
+		return nil, err
+	}
+	if !left.IsMatrix() && !right.IsMatrix() {
+		value, err := ev.scalar.applyBinary(n, left.Scalar, right.Scalar)
+		if err != nil {
+			return nil, err
+		}
+		return scalarResult(value), nil
+	}
+
+	var result *linalg.Matrix
+	switch n.Op {
+	case "+", "-":
+		if !left.IsMatrix() || !right.IsMatrix() {
+			return nil, errorAt(linalg.ErrDimension, n.span, "cannot combine a matrix and a number with '%s'", n.Op)
+		}
+		if n.Op == "+" {
+			result, err = linalg.Add(left.Matrix, right.Matrix)
+		} else {
+			result, err = linalg.Sub(left.Matrix, right.Matrix)
+		}
+	case "*":
+		switch {
+		case !left.IsMatrix():
+			result = right.Matrix.Scale(left.Scalar)
+		case !right.IsMatrix():
+			result = left.Matrix.Scale(right.Scalar)
+		default:
+			result, err = linalg.Mul(left.Matrix, right.Matrix)
+		}
+	case "/":
+		if right.IsMatrix() {
+			return nil, errorAt(ErrUnsupported, n.span, "cannot divide by a matrix; multiply by its inverse with inv")
+		}
+		if right.Scalar == 0 {
+			return nil, errorAt(ErrDivisionByZero, n.span, "division by zero")
+		}
+		result = left.Matrix.Scale(1 / right.Scalar)
+	case "^":
+		if right.IsMatrix() {
+			return nil, errorAt(ErrUnsupported, n.span, "exponent must be a number, not a matrix")
+		}
+		if right.Scalar != math.Trunc(right.Scalar) || math.Abs(right.Scalar) > math.MaxInt32 {
+			return nil, errorAt(ErrDomain, n.span, "a matrix can only be raised to an integer power")
+		}
+		result, err = left.Matrix.Pow(int(right.Scalar))
+	default:
+		return nil, unsupportedOperator(n.Op, n.span)
+	}
+	if err != nil {
+		return nil, matrixError(err, n.span, "")
+	}
+	return checkMatrix(result, n.span)
+}
+
+// evalCall evaluates a call of a matrix function, or of a built-in
+// function whose numeric arguments involve matrices, as in sqrt(det(A))
+func (ev *matrixEvaluation) evalCall(n *CallNode) (*MatrixResult, error) {
+	mfn, isMatrix := matrixFunctions[n.Name]
+	fn, builtin := ev.scalar.parser.functions[n.Name]
+	switch {
+	case isMatrix:
+		if err := mfn.checkArity(n.Name, len(n.Args)); err != nil {
+			return nil, locate(err, n.span, "")
+		}
+	case builtin:
+		if err := fn.checkArity(n.Name, len(n.Args)); err != nil {
+			return nil, locate(err, n.span, "")
+		}
+	default:
+		if _, ok := ev.scalar.opts.userFunction(n.Name); ok {
+			return nil, errorAt(ErrUnsupported, n.span, "user-defined function %s cannot take a matrix", n.Name)
+		}
+		return nil, errorAt(ErrUnknownFunction, n.span, "unknown function '%s'", n.Name)
+	}
+
+	args := make([]*MatrixResult, len(n.Args))
+	for i, argNode := range n.Args {
+		arg, err := ev.eval(argNode)
+		if err != nil {
+			return nil, err
+		}
+		if !isMatrix && arg.IsMatrix() {
+			return nil, errorAt(ErrUnsupported, argNode.Span(), "function %s takes numbers, not a matrix", n.Name)
+		}
+		args[i] = arg
+	}
+
+	if !isMatrix {
+		values := make([]float64, len(args))
+		for i, arg := range args {
+			values[i] = arg.Scalar
+		}
+		value, err := ev.scalar.applyCall(fn, n, values)
+		if err != nil {
+			return nil, err
+		}
+		return scalarResult(value), nil
+	}
+
+	result, err := mfn.eval(args)
+	if err != nil {
+		return nil, matrixError(err, n.span, "error in "+n.Name+" function: ")
+	}
+	if !result.IsMatrix() {
+		value, err := checkResult(result.Scalar, n.span)
+		if err != nil {
+			return nil, err
+		}
+		return scalarResult(value), nil
+	}
+	return checkMatrix(result.Matrix, n.span)
+}
+
+// checkMatrix rejects matrices with infinite or NaN entries, locating the
+// error at the operation that produced them
+func checkMatrix(m *linalg.Matrix, span Span) (*MatrixResult, error) {
+	for i := 0; i < m.Rows(); i++ {
+		for j := 0; j < m.Cols(); j++ {
+			if _, err := checkResult(m.At(i, j), span); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &MatrixResult{Matrix: m}, nil
+}
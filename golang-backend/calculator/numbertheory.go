@@ -0,0 +1,451 @@
+package calculator
+
+import (
+	"math"
+	"math/big"
+	"sort"
+)
+
+// Limits keeping combinatorial results to a size that computes quickly
+const (
+	maxCombinatorial = 100000
+	maxFibonacci     = 1000000
+	maxFactorBits    = 128
+	pollardLimit     = 1 << 18
+	rhoBatch         = 64
+)
+
+// NumberTheoryOperations provides exact integer and combinatorial operations
+type NumberTheoryOperations struct{}
+
+// NewNumberTheoryOperations creates a new NumberTheoryOperations instance
+func NewNumberTheoryOperations() *NumberTheoryOperations {
+	return &NumberTheoryOperations{}
+}
+
+// PrimeFactor is a prime and its exponent in a factorization
+type PrimeFactor struct {
+	Prime    *big.Int
+	Exponent int
+}
+
+// GCD calculates the greatest common divisor of the values, which is
+// never negative
+func (n *NumberTheoryOperations) GCD(values ...*big.Int) *big.Int {
+	result := new(big.Int)
+	for _, v := range values {
+		result.GCD(nil, nil, result, new(big.Int).Abs(v))
+	}
+	return result
+}
+
+// LCM calculates the least common multiple of the values, which is never
+// negative; it is zero if any value is zero
+func (n *NumberTheoryOperations) LCM(values ...*big.Int) *big.Int {
+	result := big.NewInt(1)
+	for _, v := range values {
+		if v.Sign() == 0 {
+			return new(big.Int)
+		}
+		g := n.GCD(result, v)
+		result.Mul(result, new(big.Int).Quo(new(big.Int).Abs(v), g))
+	}
+	return result
+}
+
+// FlooredMod calculates the remainder of a divided by b with the sign of
+// b, matching Modulo
+func (n *NumberTheoryOperations) FlooredMod(a, b *big.Int) (*big.Int, error) {
+	result, err := n.TruncatedMod(a, b)
+	if err != nil {
+		return nil, err
+	}
+	if result.Sign() != 0 && result.Sign() != b.Sign() {
+		result.Add(result, b)
+	}
+	return result, nil
+}
+
+// TruncatedMod calculates the remainder of a divided by b with the sign of
+// a, as Go's % operator does
+func (n *NumberTheoryOperations) TruncatedMod(a, b *big.Int) (*big.Int, error) {
+	if b.Sign() == 0 {
+		return nil, newError(ErrDivisionByZero, "modulo by zero")
+	}
+	return new(big.Int).Rem(a, b), nil
+}
+
+// IsPrime reports whether value is prime. It is exact below 2^64 and
+// wrong with negligible probability above.
+func (n *NumberTheoryOperations) IsPrime(value *big.Int) bool {
+	return value.Sign() > 0 && value.ProbablyPrime(20)
+}
+
+// NextPrime returns the smallest prime greater than value
+func (n *NumberTheoryOperations) NextPrime(value *big.Int) *big.Int {
+	two := big.NewInt(2)
+	if value.Cmp(two) < 0 {
+		return two
+	}
+	candidate := new(big.Int).Add(value, big.NewInt(1))
+	if candidate.Bit(0) == 0 {
+		candidate.Add(candidate, big.NewInt(1))
+	}
+	for !candidate.ProbablyPrime(20) {
+		candidate.Add(candidate, two)
+	}
+	return candidate
+}
+
+// Factor returns the prime factorization of value in increasing order of
+// primes. Small factors are found by trial division and larger ones with
+// Pollard's rho method, which gives up on numbers whose two smallest prime
+// factors both exceed about 2^36. Values are limited to maxFactorBits bits
+// so that giving up stays quick.
+func (n *NumberTheoryOperations) Factor(value *big.Int) ([]PrimeFactor, error) {
+	if value.Sign() <= 0 {
+		return nil, newError(ErrDomain, "factor domain error: value must be positive")
+	}
+	if value.BitLen() > maxFactorBits {
+		return nil, newErrorf(ErrDomain, "factor domain error: value must be less than 2^%d", maxFactorBits)
+	}
+
+	var primes []*big.Int
+	rest := new(big.Int).Set(value)
+	quotient, remainder := new(big.Int), new(big.Int)
+	for p := int64(2); p < 1000; p++ {
+		prime := big.NewInt(p)
+		if !prime.ProbablyPrime(0) {
+			continue
+		}
+		for {
+			quotient.QuoRem(rest, prime, remainder)
+			if remainder.Sign() != 0 {
+				break
+			}
+			primes = append(primes, prime)
+			rest.Set(quotient)
+		}
+	}
+
+	pending := []*big.Int{rest}
+	for len(pending) > 0 {
+		m := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		switch {
+		case m.Cmp(big.NewInt(1)) == 0:
+			continue
+		case m.ProbablyPrime(20):
+			primes = append(primes, m)
+			continue
+		}
+		divisor := pollardRho(m)
+		if divisor == nil {
+			return nil, newErrorf(ErrUnsupported, "cannot factor %s: no factor found within the search limit", m)
+		}
+		pending = append(pending, divisor, new(big.Int).Quo(m, divisor))
+	}
+
+	sort.Slice(primes, func(i, j int) bool { return primes[i].Cmp(primes[j]) < 0 })
+	var factors []PrimeFactor
+	for _, p := range primes {
+		if len(factors) > 0 && factors[len(factors)-1].Prime.Cmp(p) == 0 {
+			factors[len(factors)-1].Exponent++
+			continue
+		}
+		factors = append(factors, PrimeFactor{Prime: p, Exponent: 1})
+	}
+	return factors, nil
+}
+
+// pollardRho looks for a non-trivial divisor of the composite m with
+// Floyd's cycle detection over x² + c. It moves on to the next c when a
+// cycle closes without a divisor and returns nil once pollardLimit steps
+// have been taken in all. Differences are multiplied together so that only
+// one GCD is taken per rhoBatch steps; a batch whose product shares every
+// factor with m is replayed step by step.
+func pollardRho(m *big.Int) *big.Int {
+	one := big.NewInt(1)
+	step := func(v, c *big.Int) {
+		v.Mul(v, v)
+		v.Add(v, c)
+		v.Mod(v, m)
+	}
+
+	steps := 0
+	for c := int64(1); steps < pollardLimit; c++ {
+		cc := big.NewInt(c)
+		x, y, d := big.NewInt(2), big.NewInt(2), big.NewInt(1)
+		diff, product := new(big.Int), new(big.Int)
+		for ; steps < pollardLimit && d.Cmp(one) == 0; steps += rhoBatch {
+			savedX, savedY := new(big.Int).Set(x), new(big.Int).Set(y)
+			product.SetInt64(1)
+			for j := 0; j < rhoBatch; j++ {
+				step(x, cc)
+				step(y, cc)
+				step(y, cc)
+				product.Mul(product, diff.Sub(x, y))
+				product.Mod(product, m)
+			}
+			d.GCD(nil, nil, product, m)
+			if d.Cmp(m) != 0 {
+				continue
+			}
+			x, y = savedX, savedY
+			for j := 0; j < rhoBatch; j++ {
+				step(x, cc)
+				step(y, cc)
+				step(y, cc)
+				diff.Sub(x, y)
+				if d.GCD(nil, nil, diff.Abs(diff), m); d.Cmp(one) != 0 {
+					break
+				}
+			}
+		}
+		if d.Cmp(one) != 0 && d.Cmp(m) != 0 {
+			return d
+		}
+	}
+	return nil
+}
+
+// ModPow calculates base^exponent modulo a positive modulus. A negative
+// exponent raises the modular inverse of base.
+func (n *NumberTheoryOperations) ModPow(base, exponent, modulus *big.Int) (*big.Int, error) {
+	if modulus.Sign() <= 0 {
+		return nil, newError(ErrDomain, "modpow domain error: modulus must be positive")
+	}
+	b := new(big.Int).Mod(base, modulus)
+	e := new(big.Int).Set(exponent)
+	if e.Sign() < 0 {
+		inverse, err := n.ModInverse(b, modulus)
+		if err != nil {
+			return nil, err
+		}
+		b, e = inverse, e.Neg(e)
+	}
+	return new(big.Int).Exp(b, e, modulus), nil
+}
+
+// ModInverse calculates the x in [0, modulus) with value·x ≡ 1 (mod modulus)
+func (n *NumberTheoryOperations) ModInverse(value, modulus *big.Int) (*big.Int, error) {
+	if modulus.Sign() <= 0 {
+		return nil, newError(ErrDomain, "modinv domain error: modulus must be positive")
+	}
+	if modulus.Cmp(big.NewInt(1)) == 0 {
+		return new(big.Int), nil
+	}
+	result := new(big.Int).ModInverse(new(big.Int).Mod(value, modulus), modulus)
+	if result == nil {
+		return nil, newErrorf(ErrDomain, "%s has no inverse modulo %s", value, modulus)
+	}
+	return result, nil
+}
+
+// combinatorialArgs checks the arguments of nCr and nPr
+func combinatorialArgs(name string, total, chosen *big.Int) (int64, int64, error) {
+	if total.Sign() < 0 || chosen.Sign() < 0 {
+		return 0, 0, newErrorf(ErrDomain, "%s domain error: arguments must be non-negative", name)
+	}
+	if total.Cmp(big.NewInt(maxCombinatorial)) > 0 {
+		return 0, 0, newErrorf(ErrOverflow, "%s arguments must not exceed %d", name, maxCombinatorial)
+	}
+	if chosen.Cmp(total) > 0 {
+		return total.Int64(), total.Int64() + 1, nil
+	}
+	return total.Int64(), chosen.Int64(), nil
+}
+
+// Binomial calculates the number of ways to choose k of n items, nCr
+func (n *NumberTheoryOperations) Binomial(total, chosen *big.Int) (*big.Int, error) {
+	t, k, err := combinatorialArgs("nCr", total, chosen)
+	if err != nil {
+		return nil, err
+	}
+	if k > t {
+		return new(big.Int), nil
+	}
+	return new(big.Int).Binomial(t, k), nil
+}
+
+// Permutations calculates the number of ordered arrangements of k of n
+// items, nPr
+func (n *NumberTheoryOperations) Permutations(total, chosen *big.Int) (*big.Int, error) {
+	t, k, err := combinatorialArgs("nPr", total, chosen)
+	if err != nil {
+		return nil, err
+	}
+	if k > t {
+		return new(big.Int), nil
+	}
+	return new(big.Int).MulRange(t-k+1, t), nil
+}
+
+// Fibonacci calculates the nth Fibonacci number, with F(0) = 0 and F(1) = 1
+func (n *NumberTheoryOperations) Fibonacci(index *big.Int) (*big.Int, error) {
+	if index.Sign() < 0 {
+		return nil, newError(ErrDomain, "fibonacci domain error: index must be non-negative")
+	}
+	if index.Cmp(big.NewInt(maxFibonacci)) > 0 {
+		return nil, newErrorf(ErrOverflow, "fibonacci index must not exceed %d", maxFibonacci)
+	}
+
+	// Fast doubling: F(2k) = F(k)(2F(k+1) - F(k)), F(2k+1) = F(k)² + F(k+1)²
+	a, b := new(big.Int), big.NewInt(1)
+	for i := index.BitLen() - 1; i >= 0; i-- {
+		c := new(big.Int).Lsh(b, 1)
+		c.Sub(c, a)
+		c.Mul(c, a)
+		d := new(big.Int).Mul(a, a)
+		d.Add(d, new(big.Int).Mul(b, b))
+		a, b = c, d
+		if index.Bit(i) == 1 {
+			a, b = b, a.Add(a, b)
+		}
+	}
+	return a, nil
+}
+
+// integerFunction is a function of exact integers
+type integerFunction struct {
+	Arity
+	eval func(args []*big.Int) (*big.Int, error)
+}
+
+// Call applies a named integer operation, as named in expressions, to
+// exact arguments
+func (n *NumberTheoryOperations) Call(name string, args []*big.Int) (*big.Int, error) {
+	fn, ok := integerFunctions[name]
+	if !ok {
+		return nil, newErrorf(ErrUnknownFunction, "unknown function '%s'", name)
+	}
+	if err := fn.checkArity(name, len(args)); err != nil {
+		return nil, err
+	}
+	return fn.eval(args)
+}
+
+// integerFunctions is the table of integer operations. Each is also
+// callable from expressions in every number mode except complex, unless a
+// real-valued function of the same name, like mod, takes precedence.
+var integerFunctions = numberTheoryFunctions(NewNumberTheoryOperations())
+
+// numberTheoryFunctions returns the integer operations of nt by name
+func numberTheoryFunctions(nt *NumberTheoryOperations) map[string]integerFunction {
+	boolean := func(b bool) *big.Int {
+		if b {
+			return big.NewInt(1)
+		}
+		return new(big.Int)
+	}
+	return map[string]integerFunction{
+		"gcd": {Arity{1, -1}, func(args []*big.Int) (*big.Int, error) {
+			return nt.GCD(args...), nil
+		}},
+		"lcm": {Arity{1, -1}, func(args []*big.Int) (*big.Int, error) {
+			return nt.LCM(args...), nil
+		}},
+		"mod": {Arity{2, 2}, func(args []*big.Int) (*big.Int, error) {
+			return nt.FlooredMod(args[0], args[1])
+		}},
+		"rem": {Arity{2, 2}, func(args []*big.Int) (*big.Int, error) {
+			return nt.TruncatedMod(args[0], args[1])
+		}},
+		"isPrime": {Arity{1, 1}, func(args []*big.Int) (*big.Int, error) {
+			return boolean(nt.IsPrime(args[0])), nil
+		}},
+		"nextPrime": {Arity{1, 1}, func(args []*big.Int) (*big.Int, error) {
+			return nt.NextPrime(args[0]), nil
+		}},
+		"modpow": {Arity{3, 3}, func(args []*big.Int) (*big.Int, error) {
+			return nt.ModPow(args[0], args[1], args[2])
+		}},
+		"modinv": {Arity{2, 2}, func(args []*big.Int) (*big.Int, error) {
+			return nt.ModInverse(args[0], args[1])
+		}},
+		"nCr": {Arity{2, 2}, func(args []*big.Int) (*big.Int, error) {
+			return nt.Binomial(args[0], args[1])
+		}},
+		"nPr": {Arity{2, 2}, func(args []*big.Int) (*big.Int, error) {
+			return nt.Permutations(args[0], args[1])
+		}},
+		"fib": {Arity{1, 1}, func(args []*big.Int) (*big.Int, error) {
+			return nt.Fibonacci(args[0])
+		}},
+	}
+}
+
+// nonInteger is the error for a fractional argument to an integer function
+var nonInteger = newError(ErrDomain, "arguments must be integers")
+
+// float adapts an integer function to float64 arguments and results
+func (fn integerFunction) float() function {
+	return function{
+		Arity: fn.Arity,
+		eval: func(args []float64, opts EvalOptions) (float64, error) {
+			ints := make([]*big.Int, len(args))
+			for i, arg := range args {
+				if arg != math.Trunc(arg) || math.IsInf(arg, 0) {
+					return 0, nonInteger
+				}
+				ints[i], _ = big.NewFloat(arg).Int(nil)
+			}
+			result, err := fn.eval(ints)
+			if err != nil {
+				return 0, err
+			}
+			value, _ := new(big.Float).SetInt(result).Float64()
+			if math.IsInf(value, 0) {
+				return 0, newError(ErrOverflow, "result too large for floating point; use rational mode for exact integers")
+			}
+			return value, nil
+		},
+	}
+}
+
+// rational adapts an integer function to exact rational arguments
+func (fn integerFunction) rational() rationalFunction {
+	return func(args []*big.Rat) (*big.Rat, error) {
+		ints := make([]*big.Int, len(args))
+		for i, arg := range args {
+			if !arg.IsInt() {
+				return nil, nonInteger
+			}
+			ints[i] = arg.Num()
+		}
+		result, err := fn.eval(ints)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Rat).SetInt(result), nil
+	}
+}
+
+// arbitrary adapts an integer function to arbitrary-precision arguments
+func (fn integerFunction) arbitrary() bigFunction {
+	return func(ev *bigEvaluation, args []*big.Float) (*big.Float, error) {
+		ints := make([]*big.Int, len(args))
+		for i, arg := range args {
+			if !arg.IsInt() || arg.IsInf() {
+				return nil, nonInteger
+			}
+			ints[i], _ = arg.Int(nil)
+		}
+		result, err := fn.eval(ints)
+		if err != nil {
+			return nil, err
+		}
+		return ev.newFloat().SetInt(result), nil
+	}
+}
+
+func init() {
+	for name, fn := range integerFunctions {
+		if _, ok := rationalFunctions[name]; !ok {
+			rationalFunctions[name] = fn.rational()
+		}
+		if _, ok := bigFunctions[name]; !ok {
+			bigFunctions[name] = fn.arbitrary()
+		}
+	}
+}
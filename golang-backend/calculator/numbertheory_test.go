@@ -0,0 +1,195 @@
+package calculator
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// bigInts parses decimal integers
+func bigInts(t *testing.T, values ...string) []*big.Int {
+	t.Helper()
+	ints := make([]*big.Int, len(values))
+	for i, v := range values {
+		n, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			t.Fatalf("bad integer %q", v)
+		}
+		ints[i] = n
+	}
+	return ints
+}
+
+func TestNumberTheoryCall(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"gcd", []string{"12", "18"}, "6"},
+		{"gcd", []string{"-12", "18", "8"}, "2"},
+		{"gcd", []string{"0", "0"}, "0"},
+		{"lcm", []string{"4", "6"}, "12"},
+		{"lcm", []string{"-4", "6", "10"}, "60"},
+		{"lcm", []string{"0", "5"}, "0"},
+		{"mod", []string{"-7", "3"}, "2"},
+		{"mod", []string{"7", "-3"}, "-2"},
+		{"rem", []string{"-7", "3"}, "-1"},
+		{"rem", []string{"7", "-3"}, "1"},
+		{"isPrime", []string{"97"}, "1"},
+		{"isPrime", []string{"1"}, "0"},
+		{"isPrime", []string{"-7"}, "0"},
+		{"isPrime", []string{"18446744073709551557"}, "1"},
+		{"nextPrime", []string{"-5"}, "2"},
+		{"nextPrime", []string{"2"}, "3"},
+		{"nextPrime", []string{"13"}, "17"},
+		{"nextPrime", []string{"9007199254740992"}, "9007199254740997"},
+		{"modpow", []string{"2", "100", "1000000007"}, "976371285"},
+		{"modpow", []string{"-2", "3", "5"}, "2"},
+		{"modpow", []string{"3", "-1", "7"}, "5"},
+		{"modinv", []string{"3", "7"}, "5"},
+		{"modinv", []string{"-3", "7"}, "2"},
+		{"modinv", []string{"5", "1"}, "0"},
+		{"nCr", []string{"5", "2"}, "10"},
+		{"nCr", []string{"2", "5"}, "0"},
+		{"nCr", []string{"100", "50"}, "100891344545564193334812497256"},
+		{"nPr", []string{"5", "2"}, "20"},
+		{"nPr", []string{"5", "0"}, "1"},
+		{"fib", []string{"0"}, "0"},
+		{"fib", []string{"1"}, "1"},
+		{"fib", []string{"10"}, "55"},
+		{"fib", []string{"100"}, "354224848179261915075"},
+	}
+	nt := NewNumberTheoryOperations()
+	for _, tc := range cases {
+		got, err := nt.Call(tc.name, bigInts(t, tc.args...))
+		if err != nil {
+			t.Errorf("%s%v: %v", tc.name, tc.args, err)
+			continue
+		}
+		if got.String() != tc.want {
+			t.Errorf("%s%v = %s, want %s", tc.name, tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestNumberTheoryErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		kind error
+	}{
+		{"mod", []string{"5", "0"}, ErrDivisionByZero},
+		{"rem", []string{"5", "0"}, ErrDivisionByZero},
+		{"modpow", []string{"2", "3", "0"}, ErrDomain},
+		{"modpow", []string{"2", "-1", "4"}, ErrDomain},
+		{"modinv", []string{"2", "4"}, ErrDomain},
+		{"modinv", []string{"2", "-5"}, ErrDomain},
+		{"nCr", []string{"-1", "2"}, ErrDomain},
+		{"nPr", []string{"5", "-2"}, ErrDomain},
+		{"nCr", []string{"100001", "2"}, ErrOverflow},
+		{"fib", []string{"-1"}, ErrDomain},
+		{"fib", []string{"1000001"}, ErrOverflow},
+		{"gcd", nil, ErrArgumentCount},
+		{"modpow", []string{"2", "3"}, ErrArgumentCount},
+		{"totient", []string{"9"}, ErrUnknownFunction},
+	}
+	nt := NewNumberTheoryOperations()
+	for _, tc := range cases {
+		if _, err := nt.Call(tc.name, bigInts(t, tc.args...)); !errors.Is(err, tc.kind) {
+			t.Errorf("%s%v: error %v, want %v", tc.name, tc.args, err, tc.kind)
+		}
+	}
+}
+
+func TestFactor(t *testing.T) {
+	cases := []struct {
+		value string
+		want  string
+	}{
+		{"1", ""},
+		{"2", "2"},
+		{"360", "2^3 3^2 5"},
+		{"9007199254740993", "3 107 28059810762433"},
+		{"18446744073709551617", "274177 67280421310721"},
+		// Two primes above the trial division limit
+		{"1000036000099", "1000003 1000033"},
+		// The largest size accepted; 2^127 - 1 is prime
+		{"170141183460469231731687303715884105727", "170141183460469231731687303715884105727"},
+		{"340282366920938463463374607431768211455", "3 5 17 257 641 65537 274177 6700417 67280421310721"},
+	}
+	nt := NewNumberTheoryOperations()
+	for _, tc := range cases {
+		factors, err := nt.Factor(bigInts(t, tc.value)[0])
+		if err != nil {
+			t.Errorf("factor(%s): %v", tc.value, err)
+			continue
+		}
+		got := ""
+		for i, f := range factors {
+			if i > 0 {
+				got += " "
+			}
+			got += f.Prime.String()
+			if f.Exponent > 1 {
+				got += "^" + big.NewInt(int64(f.Exponent)).String()
+			}
+		}
+		if got != tc.want {
+			t.Errorf("factor(%s) = %s, want %s", tc.value, got, tc.want)
+		}
+	}
+
+	for _, value := range []string{"0", "-12", "340282366920938463463374607431768211456"} {
+		if _, err := nt.Factor(bigInts(t, value)[0]); !errors.Is(err, ErrDomain) {
+			t.Errorf("factor(%s): error %v, want %v", value, err, ErrDomain)
+		}
+	}
+}
+
+func TestNumberTheoryExpressions(t *testing.T) {
+	cases := []struct {
+		expr     string
+		float    float64
+		rational string
+	}{
+		{"gcd(12, 18) + lcm(4, 6)", 18, "18"},
+		{"nCr(10, 3) / nPr(3, 3)", 20, "20"},
+		{"fib(80)", 23416728348467685, "23416728348467685"},
+		{"modpow(3, 200, 1000)", 1, "1"},
+		{"rem(-7, 3)", -1, "-1"},
+		{"mod(-7, 3)", 2, "2"},
+		{"isPrime(nextPrime(100))", 1, "1"},
+	}
+	p := New()
+	for _, tc := range cases {
+		got, err := p.Evaluate(tc.expr, EvalOptions{})
+		if err != nil || got != tc.float {
+			t.Errorf("%s = %v, %v; want %v", tc.expr, got, err, tc.float)
+		}
+		exact, err := p.EvaluateRational(tc.expr, EvalOptions{})
+		if err != nil || !exact.Exact() || exact.Value.RatString() != tc.rational {
+			t.Errorf("%s in rational mode = %v, %v; want %s", tc.expr, exact, err, tc.rational)
+		}
+	}
+
+	// Results beyond 2^53 stay exact in rational mode
+	exact, err := p.EvaluateRational("fib(100) + 1", EvalOptions{})
+	if err != nil || exact.Value.RatString() != "354224848179261915076" {
+		t.Errorf("fib(100) + 1 = %v, %v", exact, err)
+	}
+
+	errorCases := []struct {
+		expr string
+		kind error
+	}{
+		{"gcd(1.5, 3)", ErrDomain},
+		{"fib(2000)", ErrOverflow},
+		{"modinv(2, 4)", ErrDomain},
+	}
+	for _, tc := range errorCases {
+		if _, err := p.Evaluate(tc.expr, EvalOptions{}); !errors.Is(err, tc.kind) {
+			t.Errorf("%s: error %v, want %v", tc.expr, err, tc.kind)
+		}
+	}
+}
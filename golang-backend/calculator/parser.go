@@ -1,340 +1,465 @@
 package calculator
 
 import (
-	"errors"
-	"fmt"
 	"math"
-	"regexp"
 	"strconv"
-	"strings"
 )
 
-// ExpressionParser handles parsing and evaluating mathematical expressions
+// AngleMode selects the unit trigonometric functions work in
+type AngleMode int
+
+const (
+	// AngleDefault uses the engine's default, set with WithAngleMode
+	AngleDefault AngleMode = iota
+	// AngleDegree interprets angles in degrees
+	AngleDegree
+	// AngleRadian interprets angles in radians
+	AngleRadian
+)
+
+// IsDegree reports whether the mode is degrees. An unresolved
+// AngleDefault counts as degrees.
+func (m AngleMode) IsDegree() bool {
+	return m != AngleRadian
+}
+
+// EvalOptions holds the settings for a single evaluation
+type EvalOptions struct {
+	AngleMode AngleMode
+	Digits    int                // significant digits to round the result to; 0 keeps full precision
+	Variables map[string]float64 // values bound to free identifiers
+	Precision uint               // mantissa bits for arbitrary-precision evaluation
+	Scope     Scope              // resolves identifiers not bound in Variables
+	Int       IntType            // integer type for programmer-mode evaluation
+}
+
+// Scope supplies identifiers and user-defined functions from state that
+// outlives a single evaluation, such as a calculator session
+type Scope interface {
+	Lookup(name string) (float64, bool)
+	Function(name string) (*UserFunction, bool)
+}
+
+// lookup resolves an identifier from Variables, then Scope
+func (o EvalOptions) lookup(name string) (float64, bool) {
+	if value, ok := o.Variables[name]; ok {
+		return value, true
+	}
+	if o.Scope != nil {
+		return o.Scope.Lookup(name)
+	}
+	return 0, false
+}
+
+// userFunction resolves a user-defined function from Scope
+func (o EvalOptions) userFunction(name string) (*UserFunction, bool) {
+	if o.Scope == nil {
+		return nil, false
+	}
+	return o.Scope.Function(name)
+}
+
+// ExpressionParser handles parsing and evaluating mathematical expressions.
+// It holds no per-evaluation state and is safe for concurrent use.
 type ExpressionParser struct {
 	basic      *BasicOperations
 	scientific *ScientificOperations
-	isDegree   bool
+	functions  map[string]function
+	constants  map[string]float64
+	angleMode  AngleMode // used when EvalOptions leaves AngleMode unset
 }
 
-// NewExpressionParser creates a new ExpressionParser
-func NewExpressionParser() *ExpressionParser {
-	return &ExpressionParser{
+// Option configures an engine created by New
+type Option func(*ExpressionParser)
+
+// WithFunctions makes the engine evaluate the functions and constants of
+// reg instead of only the built-in ones. The engine copies reg, so later
+// registrations do not affect it.
+func WithFunctions(reg *Registry) Option {
+	return func(p *ExpressionParser) {
+		p.useRegistry(reg.clone())
+	}
+}
+
+// WithAngleMode sets the angle mode used when EvalOptions does not choose one
+func WithAngleMode(mode AngleMode) Option {
+	return func(p *ExpressionParser) {
+		if mode != AngleDefault {
+			p.angleMode = mode
+		}
+	}
+}
+
+// New creates an engine configured by options. Without options it
+// evaluates the built-in functions in degree mode.
+func New(options ...Option) *ExpressionParser {
+	p := &ExpressionParser{
 		basic:      NewBasicOperations(),
 		scientific: NewScientificOperations(),
-		isDegree:   true, // Default to degree mode
+		angleMode:  AngleDegree,
+	}
+	for _, option := range options {
+		option(p)
+	}
+	if p.functions == nil {
+		p.useRegistry(NewRegistry())
+	}
+	return p
+}
+
+// NewExpressionParser creates an engine with the built-in functions
+func NewExpressionParser() *ExpressionParser {
+	return New()
+}
+
+// useRegistry takes the functions and constants of reg
+func (p *ExpressionParser) useRegistry(reg *Registry) {
+	p.functions = reg.functions
+	p.constants = make(map[string]float64, len(reg.constants))
+	for name, c := range reg.constants {
+		p.constants[name] = c.value
+	}
+}
+
+// Constants returns the named values expressions can use, including any
+// registered with the engine's registry
+func (p *ExpressionParser) Constants() map[string]float64 {
+	constants := make(map[string]float64, len(p.constants))
+	for name, value := range p.constants {
+		constants[name] = value
 	}
+	return constants
 }
 
-// SetMode sets the angle mode for trigonometric functions
-func (p *ExpressionParser) SetMode(isDegree bool) {
-	p.isDegree = isDegree
+// withDefaults fills in the options the caller left to the engine
+func (p *ExpressionParser) withDefaults(opts EvalOptions) EvalOptions {
+	if opts.AngleMode == AngleDefault {
+		opts.AngleMode = p.angleMode
+	}
+	return opts
 }
 
 // Evaluate parses and evaluates a mathematical expression
-func (p *ExpressionParser) Evaluate(expression string) (float64, error) {
-	// Clean the expression
-	expression = p.cleanExpression(expression)
-	
-	// Replace constants
-	expression = p.replaceConstants(expression)
-	
-	// Handle scientific functions
-	var err error
-	expression, err = p.evaluateScientificFunctions(expression)
+func (p *ExpressionParser) Evaluate(expression string, opts EvalOptions) (float64, error) {
+	prog, err := p.Compile(expression)
 	if err != nil {
 		return 0, err
 	}
-	
-	// Evaluate the remaining arithmetic expression
-	return p.evaluateArithmetic(expression)
-}
-
-// cleanExpression removes whitespace and standardizes operators
-func (p *ExpressionParser) cleanExpression(expr string) string {
-	// Remove all whitespace
-	expr = regexp.MustCompile(`\s+`).ReplaceAllString(expr, "")
-	
-	// Replace common operator variations
-	expr = strings.ReplaceAll(expr, "×", "*")
-	expr = strings.ReplaceAll(expr, "÷", "/")
-	expr = strings.ReplaceAll(expr, "−", "-")
-	
-	// Handle implicit multiplication (e.g., 2π → 2*π)
-	expr = regexp.MustCompile(`(\d)([a-zA-Z])`).ReplaceAllString(expr, "$1*$2")
-	expr = regexp.MustCompile(`([a-zA-Z])(\d)`).ReplaceAllString(expr, "$1*$2")
-	expr = regexp.MustCompile(`(\))(\()`).ReplaceAllString(expr, "$1*$2")
-	expr = regexp.MustCompile(`(\d)(\()`).ReplaceAllString(expr, "$1*$2")
-	expr = regexp.MustCompile(`(\))(\d)`).ReplaceAllString(expr, "$1*$2")
-	
-	return expr
-}
-
-// replaceConstants replaces mathematical constants with their values
-func (p *ExpressionParser) replaceConstants(expr string) string {
-	expr = strings.ReplaceAll(expr, "π", fmt.Sprintf("%g", math.Pi))
-	expr = strings.ReplaceAll(expr, "pi", fmt.Sprintf("%g", math.Pi))
-	expr = strings.ReplaceAll(expr, "e", fmt.Sprintf("%g", math.E))
-	return expr
-}
-
-// evaluateScientificFunctions evaluates scientific functions in the expression
-func (p *ExpressionParser) evaluateScientificFunctions(expr string) (string, error) {
-	// Define function patterns with their evaluation logic
-	functions := map[string]func(float64) (float64, error){
-		"sin": func(x float64) (float64, error) {
-			return p.scientific.Sin(x, p.isDegree), nil
-		},
-		"cos": func(x float64) (float64, error) {
-			return p.scientific.Cos(x, p.isDegree), nil
-		},
-		"tan": func(x float64) (float64, error) {
-			return p.scientific.Tan(x, p.isDegree)
-		},
-		"asin": func(x float64) (float64, error) {
-			return p.scientific.Asin(x, p.isDegree)
-		},
-		"acos": func(x float64) (float64, error) {
-			return p.scientific.Acos(x, p.isDegree)
-		},
-		"atan": func(x float64) (float64, error) {
-			return p.scientific.Atan(x, p.isDegree), nil
-		},
-		"log": func(x float64) (float64, error) {
-			return p.scientific.Log(x)
-		},
-		"ln": func(x float64) (float64, error) {
-			return p.scientific.Ln(x)
-		},
-		"exp": func(x float64) (float64, error) {
-			return p.scientific.Exp(x)
-		},
-		"sqrt": func(x float64) (float64, error) {
-			return p.basic.SquareRoot(x)
-		},
-		"abs": func(x float64) (float64, error) {
-			return p.basic.Absolute(x), nil
-		},
-		"floor": func(x float64) (float64, error) {
-			return p.scientific.Floor(x), nil
-		},
-		"ceil": func(x float64) (float64, error) {
-			return p.scientific.Ceil(x), nil
-		},
-		"round": func(x float64) (float64, error) {
-			return p.scientific.Round(x), nil
-		},
-	}
-	
-	// Process each function
-	for funcName, funcEval := range functions {
-		pattern := fmt.Sprintf(`%s\(([^()]+)\)`, funcName)
-		re := regexp.MustCompile(pattern)
-		
-		for re.MatchString(expr) {
-			matches := re.FindStringSubmatch(expr)
-			if len(matches) < 2 {
-				continue
-			}
-			
-			// Recursively evaluate the argument
-			argValue, err := p.evaluateArithmetic(matches[1])
-			if err != nil {
-				return "", fmt.Errorf("error in %s function argument: %v", funcName, err)
-			}
-			
-			// Apply the function
-			result, err := funcEval(argValue)
-			if err != nil {
-				return "", fmt.Errorf("error in %s function: %v", funcName, err)
-			}
-			
-			// Replace the function call with its result
-			expr = strings.Replace(expr, matches[0], fmt.Sprintf("%g", result), 1)
+	return prog.Run(opts)
+}
+
+// roundSignificant rounds value to the given number of significant digits
+func roundSignificant(value float64, digits int) float64 {
+	if digits <= 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+		return value
+	}
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'g', digits, 64), 64)
+	return rounded
+}
+
+// Parse builds an expression tree from its textual form
+func Parse(expression string) (Node, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, newError(ErrSyntax, "empty expression")
+	}
+
+	sp := &syntaxParser{tokens: tokens}
+	tree, err := sp.parseBinary(precOr)
+	if err != nil {
+		return nil, err
+	}
+	if tok := sp.peek(); tok.kind != tokEOF {
+		return nil, sp.unexpected(tok)
+	}
+	return tree, nil
+}
+
+// Operator precedence levels, from loosest to tightest binding. Bitwise
+// operators bind as in C, looser than arithmetic.
+const (
+	precOr = iota + 1
+	precXor
+	precAnd
+	precShift
+	precAdditive
+	precMultiplicative
+	precUnary
+	precPower
+)
+
+// binaryPrecedence maps infix operators to their precedence level
+var binaryPrecedence = map[string]int{
+	"|":   precOr,
+	"xor": precXor,
+	"&":   precAnd,
+	"<<":  precShift,
+	">>":  precShift,
+	"+":   precAdditive,
+	"-":   precAdditive,
+	"*":   precMultiplicative,
+	"/":   precMultiplicative,
+	"^":   precPower,
+}
+
+// prefixOperators and postfixOperators list the unary operators
+var (
+	prefixOperators  = map[string]bool{"+": true, "-": true, "~": true}
+	postfixOperators = map[string]bool{"!": true}
+)
+
+// bitwiseOperators are only defined on fixed-width integers
+var bitwiseOperators = map[string]bool{
+	"|": true, "xor": true, "&": true, "<<": true, ">>": true, "~": true,
+}
+
+// unsupportedOperator reports an operator the number mode cannot evaluate
+func unsupportedOperator(op string, span Span) error {
+	if bitwiseOperators[op] {
+		return errorAt(ErrUnsupported, span, "operator '%s' requires integer mode", op)
+	}
+	return errorAt(ErrUnsupported, span, "unsupported operator '%s'", op)
+}
+
+// unsupportedNode reports a node the evaluator cannot handle. A matrix
+// reaching an evaluator of numbers names the mode; an empty mode means
+// float evaluation, where matrices are only allowed in whole expressions.
+func unsupportedNode(node Node, mode string) error {
+	if _, ok := node.(*MatrixNode); ok {
+		if mode == "" {
+			return errorAt(ErrUnsupported, node.Span(), "a matrix cannot be used where a number is expected")
 		}
+		return errorAt(ErrUnsupported, node.Span(), "matrices are not supported in %s", mode)
 	}
-	
-	// Handle factorial separately (postfix operator)
-	factorialPattern := `(\d+(?:\.\d+)?)!`
-	factorialRe := regexp.MustCompile(factorialPattern)
-	for factorialRe.MatchString(expr) {
-		matches := factorialRe.FindStringSubmatch(expr)
-		if len(matches) < 2 {
-			continue
+	return errorAt(ErrUnsupported, node.Span(), "unsupported expression node %T", node)
+}
+
+// rightAssociative lists infix operators that group right to left
+var rightAssociative = map[string]bool{
+	"^": true,
+}
+
+// syntaxParser is a precedence-climbing parser over a token stream
+type syntaxParser struct {
+	tokens []token
+	pos    int
+}
+
+func (sp *syntaxParser) peek() token {
+	return sp.tokens[sp.pos]
+}
+
+func (sp *syntaxParser) next() token {
+	tok := sp.tokens[sp.pos]
+	if tok.kind != tokEOF {
+		sp.pos++
+	}
+	return tok
+}
+
+func (sp *syntaxParser) unexpected(tok token) error {
+	if tok.kind == tokEOF {
+		return errorAt(ErrSyntax, Span{tok.pos, tok.end}, "unexpected end of expression")
+	}
+	return errorAt(ErrSyntax, Span{tok.pos, tok.end}, "unexpected token '%s'", tok.text)
+}
+
+// parseBinary parses a chain of infix operators binding at least as
+// tightly as minPrec
+func (sp *syntaxParser) parseBinary(minPrec int) (Node, error) {
+	left, err := sp.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, prec, implicit := sp.binaryOperator()
+		if prec == 0 || prec < minPrec {
+			return left, nil
 		}
-		
-		value, err := strconv.ParseFloat(matches[1], 64)
-		if err != nil {
-			return "", fmt.Errorf("invalid number for factorial: %s", matches[1])
+		if !implicit {
+			sp.next()
 		}
-		
-		result, err := p.basic.Factorial(value)
-		if err != nil {
-			return "", fmt.Errorf("factorial error: %v", err)
+
+		nextMin := prec + 1
+		if rightAssociative[op] {
+			nextMin = prec
 		}
-		
-		expr = strings.Replace(expr, matches[0], fmt.Sprintf("%g", result), 1)
-	}
-	
-	return expr, nil
-}
-
-// evaluateArithmetic evaluates basic arithmetic expressions using recursive descent
-func (p *ExpressionParser) evaluateArithmetic(expr string) (float64, error) {
-	if expr == "" {
-		return 0, errors.New("empty expression")
-	}
-	
-	// Handle parentheses first
-	for strings.Contains(expr, "(") {
-		// Find the innermost parentheses
-		start := -1
-		for i, char := range expr {
-			if char == '(' {
-				start = i
-			} else if char == ')' && start != -1 {
-				// Evaluate the expression inside parentheses
-				inner := expr[start+1 : i]
-				result, err := p.evaluateArithmetic(inner)
-				if err != nil {
-					return 0, err
-				}
-				
-				// Replace the parenthetical expression with its result
-				expr = expr[:start] + fmt.Sprintf("%g", result) + expr[i+1:]
-				break
-			}
+		right, err := sp.parseBinary(nextMin)
+		if err != nil {
+			return nil, err
 		}
-		
-		// If we couldn't find matching parentheses, return error
-		if start != -1 && !strings.Contains(expr[start:], ")") {
-			return 0, errors.New("mismatched parentheses")
+
+		left = &BinaryNode{
+			Op:    op,
+			Left:  left,
+			Right: right,
+			span:  Span{left.Span().Start, right.Span().End},
 		}
 	}
-	
-	// Now evaluate the expression without parentheses
-	return p.evaluateSimpleExpression(expr)
 }
 
-// evaluateSimpleExpression evaluates expressions without parentheses using operator precedence
-func (p *ExpressionParser) evaluateSimpleExpression(expr string) (float64, error) {
-	// Handle power operations first (highest precedence)
-	expr, err := p.evaluatePowerOperations(expr)
-	if err != nil {
-		return 0, err
-	}
-	
-	// Handle multiplication and division (left to right)
-	expr, err = p.evaluateMultiplicationDivision(expr)
-	if err != nil {
-		return 0, err
+// binaryOperator reports the infix operator at the current position. A
+// value directly following another value (2π, 3(4), (1)(2), 2[1, 2]) is
+// treated as implicit multiplication.
+func (sp *syntaxParser) binaryOperator() (op string, prec int, implicit bool) {
+	tok := sp.peek()
+	switch tok.kind {
+	case tokOperator:
+		return tok.text, binaryPrecedence[tok.text], false
+	case tokIdent, tokLParen, tokLBracket:
+		return "*", precMultiplicative, true
+	case tokNumber:
+		if sp.pos > 0 && sp.tokens[sp.pos-1].kind != tokNumber {
+			return "*", precMultiplicative, true
+		}
 	}
-	
-	// Handle addition and subtraction (left to right)
-	return p.evaluateAdditionSubtraction(expr)
+	return "", 0, false
 }
 
-// evaluatePowerOperations handles ^ and ** operators
-func (p *ExpressionParser) evaluatePowerOperations(expr string) (string, error) {
-	powerPattern := `(-?\d+(?:\.\d+)?)(\^|\*\*)(-?\d+(?:\.\d+)?)`
-	powerRe := regexp.MustCompile(powerPattern)
-	
-	for powerRe.MatchString(expr) {
-		matches := powerRe.FindStringSubmatch(expr)
-		if len(matches) < 4 {
-			continue
-		}
-		
-		base, err := strconv.ParseFloat(matches[1], 64)
+// parseUnary parses prefix signs. Unary minus binds looser than ^, so
+// -2^2 is -(2^2).
+func (sp *syntaxParser) parseUnary() (Node, error) {
+	tok := sp.peek()
+	if tok.kind == tokOperator && prefixOperators[tok.text] {
+		sp.next()
+		operand, err := sp.parseBinary(precUnary)
 		if err != nil {
-			return "", fmt.Errorf("invalid base for power operation: %s", matches[1])
+			return nil, err
 		}
-		
-		exponent, err := strconv.ParseFloat(matches[3], 64)
-		if err != nil {
-			return "", fmt.Errorf("invalid exponent for power operation: %s", matches[3])
+		return &UnaryNode{
+			Op:      tok.text,
+			Operand: operand,
+			span:    Span{tok.pos, operand.Span().End},
+		}, nil
+	}
+	return sp.parsePostfix()
+}
+
+// parsePostfix parses a primary followed by any number of postfix operators
+func (sp *syntaxParser) parsePostfix() (Node, error) {
+	node, err := sp.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := sp.peek()
+		if tok.kind != tokOperator || !postfixOperators[tok.text] {
+			return node, nil
 		}
-		
-		result, err := p.basic.Power(base, exponent)
-		if err != nil {
-			return "", err
+		sp.next()
+		node = &PostfixNode{
+			Op:      tok.text,
+			Operand: node,
+			span:    Span{node.Span().Start, tok.end},
 		}
-		
-		expr = strings.Replace(expr, matches[0], fmt.Sprintf("%g", result), 1)
 	}
-	
-	return expr, nil
 }
 
-// evaluateMultiplicationDivision handles * and / operators
-func (p *ExpressionParser) evaluateMultiplicationDivision(expr string) (string, error) {
-	mdPattern := `(-?\d+(?:\.\d+)?)([*/])(-?\d+(?:\.\d+)?)`
-	mdRe := regexp.MustCompile(mdPattern)
-	
-	for mdRe.MatchString(expr) {
-		matches := mdRe.FindStringSubmatch(expr)
-		if len(matches) < 4 {
-			continue
-		}
-		
-		left, err := strconv.ParseFloat(matches[1], 64)
+// parsePrimary parses numbers, identifiers, function calls, matrix
+// literals and parenthesised sub-expressions
+func (sp *syntaxParser) parsePrimary() (Node, error) {
+	tok := sp.next()
+
+	switch tok.kind {
+	case tokNumber:
+		value, err := parseNumberLiteral(tok.text)
 		if err != nil {
-			return "", fmt.Errorf("invalid left operand: %s", matches[1])
+			return nil, locate(err, Span{tok.pos, tok.end}, "")
+		}
+		return &NumberNode{Value: value, Text: tok.text, span: Span{tok.pos, tok.end}}, nil
+
+	case tokIdent:
+		if sp.peek().kind == tokLParen {
+			return sp.parseCall(tok)
 		}
-		
-		right, err := strconv.ParseFloat(matches[3], 64)
+		return &IdentNode{Name: tok.text, span: Span{tok.pos, tok.end}}, nil
+
+	case tokLParen:
+		inner, err := sp.parseBinary(precOr)
 		if err != nil {
-			return "", fmt.Errorf("invalid right operand: %s", matches[3])
+			return nil, err
 		}
-		
-		var result float64
-		if matches[2] == "*" {
-			result = p.basic.Multiply(left, right)
-		} else { // matches[2] == "/"
-			result, err = p.basic.Divide(left, right)
-			if err != nil {
-				return "", err
-			}
+		if closing := sp.next(); closing.kind != tokRParen {
+			return nil, errorAt(ErrSyntax, Span{closing.pos, closing.end}, "mismatched parentheses: expected ')'")
 		}
-		
-		expr = strings.Replace(expr, matches[0], fmt.Sprintf("%g", result), 1)
+		return inner, nil
+
+	case tokLBracket:
+		return sp.parseMatrix(tok)
 	}
-	
-	return expr, nil
+
+	return nil, sp.unexpected(tok)
 }
 
-// evaluateAdditionSubtraction handles + and - operators
-func (p *ExpressionParser) evaluateAdditionSubtraction(expr string) (float64, error) {
-	// Split by + and - operators while preserving the operators
-	parts := regexp.MustCompile(`([+-])`).Split(expr, -1)
-	operators := regexp.MustCompile(`([+-])`).FindAllString(expr, -1)
-	
-	if len(parts) == 0 {
-		return 0, errors.New("invalid expression")
+// parseMatrix parses a matrix literal: rows separated by ';' of entries
+// separated by ',', as in [1, 2; 3, 4]
+func (sp *syntaxParser) parseMatrix(open token) (Node, error) {
+	if tok := sp.peek(); tok.kind == tokRBracket {
+		return nil, errorAt(ErrSyntax, Span{open.pos, tok.end}, "empty matrix")
 	}
-	
-	// Parse the first number
-	result, err := strconv.ParseFloat(parts[0], 64)
-	if err != nil {
-		return 0, fmt.Errorf("invalid number: %s", parts[0])
-	}
-	
-	// Apply subsequent operations
-	for i, op := range operators {
-		if i+1 >= len(parts) {
-			break
-		}
-		
-		operand, err := strconv.ParseFloat(parts[i+1], 64)
+
+	var rows [][]Node
+	row := []Node{}
+	for {
+		entry, err := sp.parseBinary(precOr)
 		if err != nil {
-			return 0, fmt.Errorf("invalid operand: %s", parts[i+1])
+			return nil, err
 		}
-		
-		if op == "+" {
-			result = p.basic.Add(result, operand)
-		} else { // op == "-"
-			result = p.basic.Subtract(result, operand)
+		row = append(row, entry)
+
+		switch sep := sp.next(); sep.kind {
+		case tokComma:
+			continue
+		case tokSemicolon, tokRBracket:
+			if len(rows) > 0 && len(row) != len(rows[0]) {
+				span := Span{row[0].Span().Start, row[len(row)-1].Span().End}
+				return nil, errorAt(ErrSyntax, span, "matrix rows must have the same length: row %d has %d, row 1 has %d", len(rows)+1, len(row), len(rows[0]))
+			}
+			rows = append(rows, row)
+			row = []Node{}
+			if sep.kind == tokRBracket {
+				return &MatrixNode{Rows: rows, span: Span{open.pos, sep.end}}, nil
+			}
+		default:
+			return nil, errorAt(ErrSyntax, Span{sep.pos, sep.end}, "mismatched brackets: expected ']'")
 		}
 	}
-	
-	return result, nil
-}
\ No newline at end of file
+}
+
+// parseCall parses the parenthesised, comma-separated arguments of a
+// function call
+func (sp *syntaxParser) parseCall(name token) (Node, error) {
+	sp.next() // consume '('
+
+	var args []Node
+	if sp.peek().kind != tokRParen {
+		for {
+			arg, err := sp.parseBinary(precOr)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+
+			if sp.peek().kind != tokComma {
+				break
+			}
+			sp.next()
+		}
+	}
+
+	closing := sp.next()
+	if closing.kind != tokRParen {
+		return nil, errorAt(ErrSyntax, Span{closing.pos, closing.end}, "mismatched parentheses: expected ')'")
+	}
+
+	return &CallNode{
+		Name: name.text,
+		Args: args,
+		span: Span{name.pos, closing.end},
+	}, nil
+}
@@ -0,0 +1,157 @@
+package calculator
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	cases := []struct {
+		expression string
+		mode       AngleMode
+		want       float64
+	}{
+		{"1 + 2 * 3", AngleDefault, 7},
+		{"(1 + 2) * 3", AngleDefault, 9},
+		{"10 - 4 - 3", AngleDefault, 3},
+		{"2 ^ 3 ^ 2", AngleDefault, 512},
+		{"2 ** 10", AngleDefault, 1024},
+		{"-2 ^ 2", AngleDefault, -4},
+		{"(-2) ^ 2", AngleDefault, 4},
+		{"2 ^ -1", AngleDefault, 0.5},
+		{"--3", AngleDefault, 3},
+		{"3 - -3", AngleDefault, 6},
+		{"5!", AngleDefault, 120},
+		{"-3!", AngleDefault, -6},
+		{"2(3 + 4)", AngleDefault, 14},
+		{"(1 + 1)(2 + 2)", AngleDefault, 8},
+		{"2pi", AngleDefault, 2 * math.Pi},
+		{"6 ÷ 3 × 2 − 1", AngleDefault, 3},
+		{"0.1 + 0.2", AngleDefault, 0.1 + 0.2},
+		{"1/3 * 3", AngleDefault, 1},
+		{"sin(cos(0))", AngleRadian, math.Sin(1)},
+		{"sin((1 + 2))", AngleRadian, math.Sin(3)},
+		{"sin(90)", AngleDegree, 1},
+		{"sqrt(16) + abs(-2)", AngleDefault, 6},
+		{"log(1000) + ln(e)", AngleDefault, 4},
+		{"sqrt(sqrt(sqrt(256)))", AngleDefault, 2},
+		{"e", AngleDefault, math.E},
+		{"π", AngleDefault, math.Pi},
+	}
+	for _, tc := range cases {
+		got, err := New().Evaluate(tc.expression, EvalOptions{AngleMode: tc.mode})
+		if err != nil {
+			t.Errorf("%s: %v", tc.expression, err)
+			continue
+		}
+		if math.Abs(got-tc.want) > 1e-12*math.Max(1, math.Abs(tc.want)) {
+			t.Errorf("%s = %v, want %v", tc.expression, got, tc.want)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	cases := []struct {
+		expression string
+		kind       error
+	}{
+		{"", ErrSyntax},
+		{"   ", ErrSyntax},
+		{"1 +", ErrSyntax},
+		{"(1 + 2", ErrSyntax},
+		{"1 + 2)", ErrSyntax},
+		{"2 * * 3", ErrSyntax},
+		{"sin()", ErrArgumentCount},
+		{"1 $ 2", ErrSyntax},
+		{"foo(1)", ErrUnknownFunction},
+		{"x + 1", ErrUnknownVariable},
+		{"1 / 0", ErrDivisionByZero},
+		{"sqrt(-1)", ErrDomain},
+		{"10 ^ 400", ErrOverflow},
+	}
+	for _, tc := range cases {
+		if _, err := New().Evaluate(tc.expression, EvalOptions{}); !errors.Is(err, tc.kind) {
+			t.Errorf("%q: error %v, want %v", tc.expression, err, tc.kind)
+		}
+	}
+}
+
+// TestFormatExpression checks that printing a tree adds only the
+// parentheses its precedence needs and reparses to the same tree
+func TestFormatExpression(t *testing.T) {
+	cases := []struct {
+		expression string
+		want       string
+	}{
+		{"1+2*3", "1 + 2*3"},
+		{"(1+2)*3", "(1 + 2)*3"},
+		{"1-(2-3)", "1 - (2 - 3)"},
+		{"(2^3)^2", "(2^3)^2"},
+		{"2^3^2", "2^3^2"},
+		{"-(2^2)", "-2^2"},
+		{"(-2)^2", "(-2)^2"},
+		{"sin(x)^2", "sin(x)^2"},
+		{"(n+1)!", "(n + 1)!"},
+		{"2pi", "2*pi"},
+	}
+	for _, tc := range cases {
+		tree, err := Parse(tc.expression)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.expression, err)
+		}
+		got := FormatExpression(tree)
+		if got != tc.want {
+			t.Errorf("format %s = %s, want %s", tc.expression, got, tc.want)
+		}
+		reparsed, err := Parse(got)
+		if err != nil {
+			t.Fatalf("reparse %s: %v", got, err)
+		}
+		if again := FormatExpression(reparsed); again != got {
+			t.Errorf("reparse %s = %s", got, again)
+		}
+	}
+}
+
+// TestEvaluateOptions shares one parser between goroutines evaluating with
+// different options; run with -race to check it keeps no per-call state
+func TestEvaluateOptions(t *testing.T) {
+	p := New()
+	cases := []struct {
+		opts EvalOptions
+		want float64
+	}{
+		{EvalOptions{AngleMode: AngleDegree}, 1},
+		{EvalOptions{AngleMode: AngleRadian}, math.Sin(90)},
+		{EvalOptions{AngleMode: AngleRadian, Digits: 3}, 0.894},
+		{EvalOptions{}, 1},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		tc := cases[i%len(cases)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := p.Evaluate("sin(90)", tc.opts)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if math.Abs(got-tc.want) > 1e-12 {
+				t.Errorf("sin(90) with %+v = %v, want %v", tc.opts, got, tc.want)
+			}
+		}()
+	}
+	wg.Wait()
+
+	radians := New(WithAngleMode(AngleRadian))
+	if got, _ := radians.Evaluate("cos(pi)", EvalOptions{}); got != -1 {
+		t.Errorf("cos(pi) with radians by default = %v, want -1", got)
+	}
+	if got, _ := radians.Evaluate("cos(180)", EvalOptions{AngleMode: AngleDegree}); got != -1 {
+		t.Errorf("cos(180) in degrees = %v, want -1", got)
+	}
+}
@@ -0,0 +1,109 @@
+package calculator
+
+import "strings"
+
+// FormatExpression writes an expression tree back as text that parses to
+// an equivalent tree, with only the parentheses precedence requires.
+// Multiplication is always written explicitly.
+func FormatExpression(node Node) string {
+	var b strings.Builder
+	writeNode(&b, node)
+	return b.String()
+}
+
+// nodePrecedence returns how tightly a node binds when written out;
+// numbers, identifiers and calls never need parentheses
+func nodePrecedence(node Node) int {
+	switch n := node.(type) {
+	case *BinaryNode:
+		return binaryPrecedence[n.Op]
+	case *UnaryNode:
+		return precUnary
+	}
+	return precPower + 1
+}
+
+func writeNode(b *strings.Builder, node Node) {
+	switch n := node.(type) {
+	case *NumberNode:
+		b.WriteString(n.Text)
+
+	case *IdentNode:
+		b.WriteString(n.Name)
+
+	case *UnaryNode:
+		b.WriteString(n.Op)
+		// Negation commutes with * and /, so -2*x needs no parentheses
+		// even though it parses as (-2)*x
+		writeOperand(b, n.Operand, nodePrecedence(n.Operand) < precMultiplicative)
+
+	case *PostfixNode:
+		writeOperand(b, n.Operand, nodePrecedence(n.Operand) <= precPower)
+		b.WriteString(n.Op)
+
+	case *BinaryNode:
+		prec := binaryPrecedence[n.Op]
+		left, right := nodePrecedence(n.Left), nodePrecedence(n.Right)
+		if rightAssociative[n.Op] {
+			writeOperand(b, n.Left, left <= prec)
+		} else {
+			writeOperand(b, n.Left, left < prec)
+		}
+
+		if n.Op == "xor" {
+			b.WriteString(" xor ")
+		} else if prec <= precAdditive {
+			b.WriteString(" " + n.Op + " ")
+		} else {
+			b.WriteString(n.Op)
+		}
+
+		// A signed right operand reads better in parentheses: x*(-y)
+		_, signed := n.Right.(*UnaryNode)
+		switch {
+		case signed, right < prec:
+			writeOperand(b, n.Right, true)
+		case right == prec && !rightAssociative[n.Op]:
+			// a - (b - c) and a / (b * c) differ from their left-grouped forms
+			writeOperand(b, n.Right, n.Op != "+" && n.Op != "*")
+		default:
+			writeOperand(b, n.Right, false)
+		}
+
+	case *CallNode:
+		b.WriteString(n.Name)
+		b.WriteByte('(')
+		for i, arg := range n.Args {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeNode(b, arg)
+		}
+		b.WriteByte(')')
+
+	case *MatrixNode:
+		b.WriteByte('[')
+		for i, row := range n.Rows {
+			if i > 0 {
+				b.WriteString("; ")
+			}
+			for j, entry := range row {
+				if j > 0 {
+					b.WriteString(", ")
+				}
+				writeNode(b, entry)
+			}
+		}
+		b.WriteByte(']')
+	}
+}
+
+func writeOperand(b *strings.Builder, node Node, parens bool) {
+	if parens {
+		b.WriteByte('(')
+	}
+	writeNode(b, node)
+	if parens {
+		b.WriteByte(')')
+	}
+}
@@ -0,0 +1,74 @@
+package calculator
+
+import "sort"
+
+// defaultParser backs the package-level Compile
+var defaultParser = NewExpressionParser()
+
+// Program is a parsed expression that can be evaluated repeatedly with
+// different variable bindings. A Program is immutable and safe for
+// concurrent use.
+type Program struct {
+	source string
+	tree   Node
+	parser *ExpressionParser
+}
+
+// Compile parses an expression once for repeated evaluation
+func Compile(expression string) (*Program, error) {
+	return defaultParser.Compile(expression)
+}
+
+// Compile parses an expression once for repeated evaluation with this
+// parser's function table
+func (p *ExpressionParser) Compile(expression string) (*Program, error) {
+	tree, err := Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	return &Program{source: expression, tree: tree, parser: p}, nil
+}
+
+// Source returns the expression the program was compiled from
+func (prog *Program) Source() string {
+	return prog.source
+}
+
+// Tree returns the program's expression tree
+func (prog *Program) Tree() Node {
+	return prog.tree
+}
+
+// Eval evaluates the program with the given variable bindings in the
+// engine's default angle mode
+func (prog *Program) Eval(vars map[string]float64) (float64, error) {
+	return prog.Run(EvalOptions{Variables: vars})
+}
+
+// Run evaluates the program with the given options
+func (prog *Program) Run(opts EvalOptions) (float64, error) {
+	opts = prog.parser.withDefaults(opts)
+	ev := &evaluation{parser: prog.parser, opts: opts}
+	result, err := ev.eval(prog.tree)
+	if err != nil {
+		return 0, err
+	}
+	return roundSignificant(result, opts.Digits), nil
+}
+
+// Variables returns the sorted names of identifiers in the program that
+// are not built-in constants and must be bound when evaluating
+func (prog *Program) Variables() []string {
+	seen := make(map[string]bool)
+	var names []string
+	Walk(prog.tree, func(n Node) {
+		if ident, ok := n.(*IdentNode); ok {
+			if _, isConst := prog.parser.constants[ident.Name]; !isConst && !seen[ident.Name] {
+				seen[ident.Name] = true
+				names = append(names, ident.Name)
+			}
+		}
+	})
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,88 @@
+package calculator
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestProgram(t *testing.T) {
+	prog, err := Compile("a*x^2 + b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := prog.Variables(); len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "x" {
+		t.Errorf("Variables() = %v, want [a b x]", got)
+	}
+
+	cases := []struct {
+		vars map[string]float64
+		want float64
+	}{
+		{map[string]float64{"a": 1, "b": 0, "x": 3}, 9},
+		{map[string]float64{"a": 2, "b": 1, "x": -1}, 3},
+		{map[string]float64{"a": 0.5, "b": -2, "x": 2, "unused": 7}, 0},
+	}
+	for _, tc := range cases {
+		got, err := prog.Eval(tc.vars)
+		if err != nil {
+			t.Errorf("%v: %v", tc.vars, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%v: got %v, want %v", tc.vars, got, tc.want)
+		}
+	}
+
+	if _, err := prog.Eval(map[string]float64{"a": 1, "x": 1}); !errors.Is(err, ErrUnknownVariable) {
+		t.Errorf("unbound b: error %v, want %v", err, ErrUnknownVariable)
+	}
+}
+
+func TestProgramConstants(t *testing.T) {
+	prog, err := Compile("pi*r^2 + e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := prog.Variables(); len(got) != 1 || got[0] != "r" {
+		t.Errorf("Variables() = %v, want [r]", got)
+	}
+	got, err := prog.Eval(map[string]float64{"r": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(got-(4*math.Pi+math.E)) > 1e-12 {
+		t.Errorf("got %v, want 4π + e", got)
+	}
+	// Bindings are looked up before the built-in constants
+	if got, _ := prog.Eval(map[string]float64{"r": 1, "pi": 3, "e": 0}); got != 3 {
+		t.Errorf("with pi bound to 3: got %v, want 3", got)
+	}
+}
+
+// TestProgramConcurrent evaluates one program from many goroutines with
+// different bindings
+func TestProgramConcurrent(t *testing.T) {
+	prog, err := Compile("sqrt(x) + x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		x := float64(i * i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := prog.Eval(map[string]float64{"x": x})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if want := math.Sqrt(x) + x; got != want {
+				t.Errorf("x = %v: got %v, want %v", x, got, want)
+			}
+		}()
+	}
+	wg.Wait()
+}
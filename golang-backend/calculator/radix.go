@@ -0,0 +1,235 @@
+package calculator
+
+import (
+	"math/big"
+	"strings"
+)
+
+// defaultRadixDigits is how many fraction digits FormatRadix writes when
+// the caller does not choose a limit
+const defaultRadixDigits = 64
+
+// maxRadixDigits bounds the fraction digits FormatRadix will write
+const maxRadixDigits = 10000
+
+// maxRadixWidth bounds the bit width of two's-complement numbers
+const maxRadixWidth = 4096
+
+// RadixOptions controls how FormatRadix writes a number
+type RadixOptions struct {
+	Base      int // 2 to 36
+	MaxDigits int // fraction digits to write before giving up; 0 means 64
+	// Width, when positive, writes integers as Width-bit two's-complement
+	// patterns instead of with a minus sign
+	Width int
+}
+
+// RadixResult is a number written in some base. A fraction that repeats
+// is split into the digits before the cycle and the cycle itself.
+type RadixResult struct {
+	Text      string // the whole number, with a repeating cycle in parentheses: "0.0(0011)"
+	Negative  bool   // whether Text carries a minus sign
+	Integer   string // integer digits, without sign
+	Fraction  string // fraction digits before any repeating cycle
+	Repeating string // the digits that repeat forever, if any
+	Exact     bool   // false when the fraction was cut off at MaxDigits
+}
+
+// checkBase rejects bases outside 2 to 36
+func checkBase(base int) error {
+	if base < 2 || base > 36 {
+		return newErrorf(ErrDomain, "base must be between 2 and 36, got %d", base)
+	}
+	return nil
+}
+
+// checkWidth rejects two's-complement widths that are not usable
+func checkWidth(width int) error {
+	if width < 0 || width > maxRadixWidth {
+		return newErrorf(ErrDomain, "width must be between 1 and %d bits, got %d", maxRadixWidth, width)
+	}
+	return nil
+}
+
+// ParseRadix reads a number written in base, such as "-1A.8" in base 16.
+// A repeating fraction may be written with its cycle in parentheses, as in
+// "0.(3)". When width is positive the digits are a width-bit
+// two's-complement pattern and carry no sign.
+func ParseRadix(text string, base, width int) (*big.Rat, error) {
+	if err := checkBase(base); err != nil {
+		return nil, err
+	}
+	if err := checkWidth(width); err != nil {
+		return nil, err
+	}
+
+	text = strings.TrimSpace(text)
+	negative := false
+	if strings.HasPrefix(text, "-") || strings.HasPrefix(text, "+") {
+		negative = text[0] == '-'
+		text = text[1:]
+		if width > 0 {
+			return nil, newError(ErrSyntax, "a two's-complement pattern has no sign")
+		}
+	}
+
+	whole, fraction, hasPoint := strings.Cut(text, ".")
+	var repeating string
+	if open := strings.IndexByte(fraction, '('); open >= 0 {
+		if !strings.HasSuffix(fraction, ")") || open == len(fraction)-2 {
+			return nil, newErrorf(ErrSyntax, "invalid repeating fraction in '%s'", text)
+		}
+		fraction, repeating = fraction[:open], fraction[open+1:len(fraction)-1]
+	}
+	if whole == "" && fraction == "" && repeating == "" {
+		return nil, newErrorf(ErrSyntax, "invalid number '%s'", text)
+	}
+	if hasPoint && width > 0 {
+		return nil, newError(ErrDomain, "a two's-complement pattern must be an integer")
+	}
+
+	digits := func(s string) (*big.Int, error) {
+		n := new(big.Int)
+		b := big.NewInt(int64(base))
+		for _, ch := range s {
+			d, ok := digitValue(ch)
+			if !ok || d >= base {
+				return nil, newErrorf(ErrSyntax, "invalid digit '%c' for base %d", ch, base)
+			}
+			n.Mul(n, b).Add(n, big.NewInt(int64(d)))
+		}
+		return n, nil
+	}
+	scale := func(n int) *big.Int {
+		return new(big.Int).Exp(big.NewInt(int64(base)), big.NewInt(int64(n)), nil)
+	}
+
+	w, err := digits(whole)
+	if err != nil {
+		return nil, err
+	}
+	value := new(big.Rat).SetInt(w)
+
+	f, err := digits(fraction)
+	if err != nil {
+		return nil, err
+	}
+	value.Add(value, new(big.Rat).SetFrac(f, scale(len(fraction))))
+
+	if repeating != "" {
+		// 0.f(r) adds r / (base^len(f) · (base^len(r) - 1))
+		r, err := digits(repeating)
+		if err != nil {
+			return nil, err
+		}
+		den := scale(len(repeating))
+		den.Sub(den, big.NewInt(1)).Mul(den, scale(len(fraction)))
+		value.Add(value, new(big.Rat).SetFrac(r, den))
+	}
+
+	if width > 0 {
+		if w.BitLen() > width {
+			return nil, newErrorf(ErrOverflow, "%s does not fit in %d bits", text, width)
+		}
+		if w.Bit(width-1) == 1 {
+			w.Sub(w, new(big.Int).Lsh(big.NewInt(1), uint(width)))
+		}
+		return new(big.Rat).SetInt(w), nil
+	}
+	if negative {
+		value.Neg(value)
+	}
+	return value, nil
+}
+
+// FormatRadix writes value in the base of opts, detecting fractions that
+// repeat within the digit limit
+func FormatRadix(value *big.Rat, opts RadixOptions) (*RadixResult, error) {
+	if err := checkBase(opts.Base); err != nil {
+		return nil, err
+	}
+	if err := checkWidth(opts.Width); err != nil {
+		return nil, err
+	}
+	limit := opts.MaxDigits
+	if limit <= 0 {
+		limit = defaultRadixDigits
+	}
+	if limit > maxRadixDigits {
+		return nil, newErrorf(ErrDomain, "at most %d fraction digits can be written", maxRadixDigits)
+	}
+
+	if opts.Width > 0 {
+		return formatTwosComplement(value, opts.Base, opts.Width)
+	}
+
+	result := &RadixResult{Negative: value.Sign() < 0, Exact: true}
+	base := big.NewInt(int64(opts.Base))
+	den := value.Denom()
+	whole, rem := new(big.Int).QuoRem(new(big.Int).Abs(value.Num()), den, new(big.Int))
+	result.Integer = strings.ToUpper(whole.Text(opts.Base))
+
+	// Long division; a remainder seen before starts the repeating cycle
+	var fraction strings.Builder
+	seen := make(map[string]int)
+	digit := new(big.Int)
+	for rem.Sign() != 0 {
+		key := rem.String()
+		if start, ok := seen[key]; ok {
+			digits := fraction.String()
+			result.Fraction, result.Repeating = digits[:start], digits[start:]
+			break
+		}
+		if fraction.Len() == limit {
+			result.Fraction, result.Exact = fraction.String(), false
+			break
+		}
+		seen[key] = fraction.Len()
+		rem.Mul(rem, base)
+		digit.QuoRem(rem, den, rem)
+		fraction.WriteString(strings.ToUpper(digit.Text(opts.Base)))
+	}
+	if rem.Sign() == 0 {
+		result.Fraction = fraction.String()
+	}
+
+	var text strings.Builder
+	if result.Negative {
+		text.WriteByte('-')
+	}
+	text.WriteString(result.Integer)
+	if result.Fraction != "" || result.Repeating != "" {
+		text.WriteByte('.')
+		text.WriteString(result.Fraction)
+		if result.Repeating != "" {
+			text.WriteString("(" + result.Repeating + ")")
+		}
+	}
+	result.Text = text.String()
+	return result, nil
+}
+
+// formatTwosComplement writes an integer as a width-bit pattern. Bases
+// that are powers of two are padded to the full width.
+func formatTwosComplement(value *big.Rat, base, width int) (*RadixResult, error) {
+	if !value.IsInt() {
+		return nil, newError(ErrDomain, "two's complement is only defined for integers")
+	}
+	n := new(big.Int).Set(value.Num())
+	half := new(big.Int).Lsh(big.NewInt(1), uint(width-1))
+	if n.Cmp(half) >= 0 || n.Cmp(new(big.Int).Neg(half)) < 0 {
+		return nil, newErrorf(ErrOverflow, "%s does not fit in %d-bit two's complement", n, width)
+	}
+	if n.Sign() < 0 {
+		n.Add(n, half.Lsh(half, 1))
+	}
+
+	digits := strings.ToUpper(n.Text(base))
+	if base&(base-1) == 0 {
+		bitsPerDigit := big.NewInt(int64(base)).BitLen() - 1
+		if pad := (width+bitsPerDigit-1)/bitsPerDigit - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+	}
+	return &RadixResult{Text: digits, Integer: digits, Exact: true}, nil
+}
@@ -0,0 +1,156 @@
+package calculator
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestParseRadix(t *testing.T) {
+	cases := []struct {
+		text  string
+		base  int
+		width int
+		want  string
+	}{
+		{"FF", 16, 0, "255"},
+		{"ff", 16, 0, "255"},
+		{"-1A.8", 16, 0, "-53/2"},
+		{"+101", 2, 0, "5"},
+		{"0.1", 2, 0, "1/2"},
+		{".1", 3, 0, "1/3"},
+		{"0.(3)", 10, 0, "1/3"},
+		{"0.1(6)", 10, 0, "1/6"},
+		{"0.0(0011)", 2, 0, "1/10"},
+		{"ZZ", 36, 0, "1295"},
+		{"12345678901234567890", 10, 0, "12345678901234567890"},
+		{"11111111", 2, 8, "-1"},
+		{"01111111", 2, 8, "127"},
+		{"80", 16, 8, "-128"},
+		{"7F", 16, 8, "127"},
+		{"377", 8, 8, "-1"},
+	}
+	for _, tc := range cases {
+		got, err := ParseRadix(tc.text, tc.base, tc.width)
+		if err != nil {
+			t.Errorf("%s in base %d: %v", tc.text, tc.base, err)
+			continue
+		}
+		if got.RatString() != tc.want {
+			t.Errorf("%s in base %d = %s, want %s", tc.text, tc.base, got.RatString(), tc.want)
+		}
+	}
+}
+
+func TestParseRadixErrors(t *testing.T) {
+	cases := []struct {
+		text  string
+		base  int
+		width int
+		kind  error
+	}{
+		{"12", 1, 0, ErrDomain},
+		{"12", 37, 0, ErrDomain},
+		{"2", 2, 0, ErrSyntax},
+		{"G", 16, 0, ErrSyntax},
+		{"", 10, 0, ErrSyntax},
+		{"-", 10, 0, ErrSyntax},
+		{".", 10, 0, ErrSyntax},
+		{"0.(3", 10, 0, ErrSyntax},
+		{"0.()", 10, 0, ErrSyntax},
+		{"1.2.3", 10, 0, ErrSyntax},
+		{"-1", 2, 8, ErrSyntax},
+		{"1.1", 2, 8, ErrDomain},
+		{"100000000", 2, 8, ErrOverflow},
+		{"1", 2, 5000, ErrDomain},
+	}
+	for _, tc := range cases {
+		if _, err := ParseRadix(tc.text, tc.base, tc.width); !errors.Is(err, tc.kind) {
+			t.Errorf("%q in base %d, width %d: error %v, want %v", tc.text, tc.base, tc.width, err, tc.kind)
+		}
+	}
+}
+
+func TestFormatRadix(t *testing.T) {
+	cases := []struct {
+		value     string
+		opts      RadixOptions
+		text      string
+		fraction  string
+		repeating string
+		exact     bool
+	}{
+		{"255", RadixOptions{Base: 16}, "FF", "", "", true},
+		{"0", RadixOptions{Base: 2}, "0", "", "", true},
+		{"-53/2", RadixOptions{Base: 16}, "-1A.8", "8", "", true},
+		{"1/3", RadixOptions{Base: 10}, "0.(3)", "", "3", true},
+		{"1/3", RadixOptions{Base: 3}, "0.1", "1", "", true},
+		{"1/6", RadixOptions{Base: 10}, "0.1(6)", "1", "6", true},
+		{"1/10", RadixOptions{Base: 2}, "0.0(0011)", "0", "0011", true},
+		{"-1/7", RadixOptions{Base: 10}, "-0.(142857)", "", "142857", true},
+		{"22/7", RadixOptions{Base: 10}, "3.(142857)", "", "142857", true},
+		{"1/7", RadixOptions{Base: 10, MaxDigits: 4}, "0.1428", "1428", "", false},
+		// A cycle that ends exactly at the limit is still detected
+		{"1/7", RadixOptions{Base: 10, MaxDigits: 6}, "0.(142857)", "", "142857", true},
+		{"1/1024", RadixOptions{Base: 2, MaxDigits: 10}, "0.0000000001", "0000000001", "", true},
+		{"1295", RadixOptions{Base: 36}, "ZZ", "", "", true},
+
+		// Two's complement pads powers of two to the full width
+		{"-1", RadixOptions{Base: 2, Width: 8}, "11111111", "", "", true},
+		{"5", RadixOptions{Base: 2, Width: 8}, "00000101", "", "", true},
+		{"-128", RadixOptions{Base: 16, Width: 8}, "80", "", "", true},
+		{"-1", RadixOptions{Base: 8, Width: 8}, "377", "", "", true},
+		{"-1", RadixOptions{Base: 10, Width: 8}, "255", "", "", true},
+	}
+	for _, tc := range cases {
+		value, _ := new(big.Rat).SetString(tc.value)
+		got, err := FormatRadix(value, tc.opts)
+		if err != nil {
+			t.Errorf("%s in base %d: %v", tc.value, tc.opts.Base, err)
+			continue
+		}
+		if got.Text != tc.text || got.Fraction != tc.fraction || got.Repeating != tc.repeating || got.Exact != tc.exact {
+			t.Errorf("%s in base %d = %+v, want %s", tc.value, tc.opts.Base, *got, tc.text)
+		}
+	}
+}
+
+func TestFormatRadixErrors(t *testing.T) {
+	cases := []struct {
+		value string
+		opts  RadixOptions
+		kind  error
+	}{
+		{"1", RadixOptions{Base: 0}, ErrDomain},
+		{"1", RadixOptions{Base: 37}, ErrDomain},
+		{"1/3", RadixOptions{Base: 10, MaxDigits: 10001}, ErrDomain},
+		{"1/2", RadixOptions{Base: 2, Width: 8}, ErrDomain},
+		{"128", RadixOptions{Base: 2, Width: 8}, ErrOverflow},
+		{"-129", RadixOptions{Base: 2, Width: 8}, ErrOverflow},
+		{"1", RadixOptions{Base: 2, Width: -1}, ErrDomain},
+	}
+	for _, tc := range cases {
+		value, _ := new(big.Rat).SetString(tc.value)
+		if _, err := FormatRadix(value, tc.opts); !errors.Is(err, tc.kind) {
+			t.Errorf("%s with %+v: error %v, want %v", tc.value, tc.opts, err, tc.kind)
+		}
+	}
+}
+
+func TestRadixRoundTrip(t *testing.T) {
+	values := []string{"0", "1", "-1", "255/256", "1/3", "-22/7", "1/10", "123456789/1000"}
+	for _, v := range values {
+		value, _ := new(big.Rat).SetString(v)
+		for _, base := range []int{2, 3, 8, 10, 16, 36} {
+			formatted, err := FormatRadix(value, RadixOptions{Base: base, MaxDigits: 1000})
+			if err != nil || !formatted.Exact {
+				t.Errorf("%s in base %d: %v, %v", v, base, formatted, err)
+				continue
+			}
+			back, err := ParseRadix(formatted.Text, base, 0)
+			if err != nil || back.Cmp(value) != 0 {
+				t.Errorf("%s in base %d wrote %s, read back %v, %v", v, base, formatted.Text, back, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,466 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxRationalExponent bounds integer exponents in rational mode
+const maxRationalExponent = 10000
+
+// maxRationalBits bounds the size of numerators and denominators produced
+// by exponentiation in rational mode
+const maxRationalBits = 1 << 20
+
+// RationalResult is the outcome of an exact rational evaluation
+type RationalResult struct {
+	Value    *big.Rat // exact value; nil when evaluation fell back to float64
+	Float    float64  // nearest float64 to the result
+	Fallback string   // why exact evaluation was abandoned, if it was
+}
+
+// Exact reports whether the result was computed exactly
+func (r *RationalResult) Exact() bool {
+	return r.Value != nil
+}
+
+// inexactError signals that an expression has no exact rational value
+type inexactError struct {
+	reason string
+}
+
+func (e *inexactError) Error() string {
+	return e.reason
+}
+
+// rationalFunction is a built-in function evaluated exactly on rationals
+type rationalFunction func(args []*big.Rat) (*big.Rat, error)
+
+// rationalFunctions lists the built-ins that can return exact rational
+// results. Calls to any other function fall back to float64 evaluation.
+var rationalFunctions = map[string]rationalFunction{
+	"abs": func(args []*big.Rat) (*big.Rat, error) {
+		return new(big.Rat).Abs(args[0]), nil
+	},
+	"floor": func(args []*big.Rat) (*big.Rat, error) {
+		return new(big.Rat).SetInt(ratFloor(args[0])), nil
+	},
+	"ceil": func(args []*big.Rat) (*big.Rat, error) {
+		return new(big.Rat).SetInt(ratCeil(args[0])), nil
+	},
+	"round": func(args []*big.Rat) (*big.Rat, error) {
+		if len(args) == 1 {
+			return new(big.Rat).SetInt(ratRound(args[0])), nil
+		}
+		if !args[1].IsInt() || !args[1].Num().IsInt64() {
+			return nil, newError(ErrDomain, "round digits must be an integer")
+		}
+		digits := args[1].Num().Int64()
+		if digits > maxRationalExponent || digits < -maxRationalExponent {
+			return nil, newError(ErrOverflow, "round digits out of range")
+		}
+		scale, err := ratPow(big.NewRat(10, 1), digits)
+		if err != nil {
+			return nil, err
+		}
+		scaled := new(big.Rat).Mul(args[0], scale)
+		scaled.SetInt(ratRound(scaled))
+		return scaled.Quo(scaled, scale), nil
+	},
+	"min": func(args []*big.Rat) (*big.Rat, error) {
+		result := args[0]
+		for _, arg := range args[1:] {
+			if arg.Cmp(result) < 0 {
+				result = arg
+			}
+		}
+		return result, nil
+	},
+	"max": func(args []*big.Rat) (*big.Rat, error) {
+		result := args[0]
+		for _, arg := range args[1:] {
+			if arg.Cmp(result) > 0 {
+				result = arg
+			}
+		}
+		return result, nil
+	},
+	"mod": func(args []*big.Rat) (*big.Rat, error) {
+		a, b := args[0], args[1]
+		if b.Sign() == 0 {
+			return nil, newError(ErrDivisionByZero, "modulo by zero")
+		}
+		q := new(big.Rat).SetInt(ratFloor(new(big.Rat).Quo(a, b)))
+		q.Mul(q, b)
+		return q.Sub(a, q), nil
+	},
+	"pow": func(args []*big.Rat) (*big.Rat, error) {
+		return ratPowRat(args[0], args[1])
+	},
+	"sqrt": func(args []*big.Rat) (*big.Rat, error) {
+		if args[0].Sign() < 0 {
+			return nil, newError(ErrDomain, "cannot calculate square root of negative number")
+		}
+		return ratRoot(args[0], 2)
+	},
+	"root": func(args []*big.Rat) (*big.Rat, error) {
+		n := args[0]
+		if n.Sign() == 0 {
+			return nil, newError(ErrDomain, "root degree must not be zero")
+		}
+		if !n.IsInt() || !n.Num().IsInt64() || n.Num().Int64() > maxRationalExponent || n.Num().Int64() < -maxRationalExponent {
+			return nil, &inexactError{"root with a non-integer degree is not rational"}
+		}
+		return ratPowRat(args[1], new(big.Rat).Inv(n))
+	},
+}
+
+// rationalEvaluation carries the state of a single rational evaluation
+type rationalEvaluation struct {
+	parser *ExpressionParser
+	opts   EvalOptions
+}
+
+// EvaluateRational parses and evaluates an expression with exact rational
+// arithmetic, falling back to float64 when an irrational value is needed
+func (p *ExpressionParser) EvaluateRational(expression string, opts EvalOptions) (*RationalResult, error) {
+	prog, err := p.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	return prog.RunRational(opts)
+}
+
+// RunRational evaluates the program with exact rational arithmetic,
+// falling back to float64 when an irrational value is needed
+func (prog *Program) RunRational(opts EvalOptions) (*RationalResult, error) {
+	opts = prog.parser.withDefaults(opts)
+	ev := &rationalEvaluation{parser: prog.parser, opts: opts}
+	value, err := ev.eval(prog.tree)
+	if err == nil {
+		f, _ := value.Float64()
+		return &RationalResult{Value: value, Float: f}, nil
+	}
+
+	var inexact *inexactError
+	if !errors.As(err, &inexact) {
+		return nil, err
+	}
+
+	f, err := prog.Run(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &RationalResult{Float: f, Fallback: inexact.reason}, nil
+}
+
+// MixedNumber formats r as a whole part followed by a proper fraction,
+// such as "-3 1/2"
+func MixedNumber(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+
+	abs := new(big.Rat).Abs(r)
+	whole, rem := new(big.Int).QuoRem(abs.Num(), abs.Denom(), new(big.Int))
+
+	var b strings.Builder
+	if r.Sign() < 0 {
+		b.WriteByte('-')
+	}
+	if whole.Sign() != 0 {
+		b.WriteString(whole.String())
+		b.WriteByte(' ')
+	}
+	b.WriteString(rem.String())
+	b.WriteByte('/')
+	b.WriteString(abs.Denom().String())
+	return b.String()
+}
+
+// parseRationalLiteral converts a literal accepted by scanNumber to an
+// exact rational
+func parseRationalLiteral(text string) (*big.Rat, error) {
+	text = strings.ReplaceAll(text, "_", "")
+
+	if len(text) > 2 && text[0] == '0' {
+		if base, ok := numberBases[rune(text[1])]; ok {
+			n, ok := new(big.Int).SetString(text[2:], base)
+			if !ok {
+				return nil, newErrorf(ErrSyntax, "invalid number '%s'", text)
+			}
+			return new(big.Rat).SetInt(n), nil
+		}
+	}
+
+	r, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return nil, newErrorf(ErrSyntax, "invalid number '%s'", text)
+	}
+	return r, nil
+}
+
+// ratFloor returns the greatest integer less than or equal to r
+func ratFloor(r *big.Rat) *big.Int {
+	// Euclidean division by the positive denominator rounds toward −∞
+	return new(big.Int).Div(r.Num(), r.Denom())
+}
+
+// ratCeil returns the least integer greater than or equal to r
+func ratCeil(r *big.Rat) *big.Int {
+	result := ratFloor(new(big.Rat).Neg(r))
+	return result.Neg(result)
+}
+
+// ratRound rounds r to the nearest integer, halves away from zero
+func ratRound(r *big.Rat) *big.Int {
+	shifted := new(big.Rat).Abs(r)
+	shifted.Add(shifted, big.NewRat(1, 2))
+	result := ratFloor(shifted)
+	if r.Sign() < 0 {
+		result.Neg(result)
+	}
+	return result
+}
+
+// ratPow raises r to an integer power
+func ratPow(r *big.Rat, n int64) (*big.Rat, error) {
+	if n > maxRationalExponent || n < -maxRationalExponent {
+		return nil, newError(ErrOverflow, "power result too large")
+	}
+	if n < 0 && r.Sign() == 0 {
+		return nil, newError(ErrDivisionByZero, "division by zero")
+	}
+
+	abs := n
+	if abs < 0 {
+		abs = -abs
+	}
+	if int64(max(r.Num().BitLen(), r.Denom().BitLen()))*abs > maxRationalBits {
+		return nil, newError(ErrOverflow, "power result too large")
+	}
+
+	e := big.NewInt(abs)
+	num := new(big.Int).Exp(r.Num(), e, nil)
+	den := new(big.Int).Exp(r.Denom(), e, nil)
+	if n < 0 {
+		num, den = den, num
+	}
+	return new(big.Rat).SetFrac(num, den), nil
+}
+
+// ratPowRat raises r to a rational power p/q, which is exact only when r
+// is a perfect qth power
+func ratPowRat(r, exponent *big.Rat) (*big.Rat, error) {
+	if !exponent.Num().IsInt64() || !exponent.Denom().IsInt64() {
+		return nil, newError(ErrOverflow, "power result too large")
+	}
+	p, q := exponent.Num().Int64(), exponent.Denom().Int64()
+
+	if q == 1 {
+		return ratPow(r, p)
+	}
+	if q > maxRationalExponent {
+		return nil, &inexactError{"power with this exponent is not rational"}
+	}
+	if r.Sign() < 0 && q%2 == 0 {
+		return nil, newError(ErrDomain, "invalid power operation")
+	}
+
+	root, err := ratRoot(r, q)
+	if err != nil {
+		return nil, err
+	}
+	return ratPow(root, p)
+}
+
+// ratRoot returns the exact kth root of r, or an inexact error when r is
+// not a perfect kth power
+func ratRoot(r *big.Rat, k int64) (*big.Rat, error) {
+	negative := r.Sign() < 0
+	if negative && k%2 == 0 {
+		return nil, newError(ErrDomain, "cannot calculate even root of negative number")
+	}
+
+	num, okNum := intRoot(new(big.Int).Abs(r.Num()), k)
+	den, okDen := intRoot(r.Denom(), k)
+	if !okNum || !okDen {
+		return nil, &inexactError{fmt.Sprintf("%s is not a perfect power, so its root is irrational", r.RatString())}
+	}
+	if negative {
+		num.Neg(num)
+	}
+	return new(big.Rat).SetFrac(num, den), nil
+}
+
+// intRoot returns the integer kth root of a non-negative x and whether it
+// is exact
+func intRoot(x *big.Int, k int64) (*big.Int, bool) {
+	if k == 2 {
+		root := new(big.Int).Sqrt(x)
+		return root, new(big.Int).Mul(root, root).Cmp(x) == 0
+	}
+
+	// Binary search between 0 and 2^(bitlen/k + 1)
+	lo := big.NewInt(0)
+	hi := new(big.Int).Lsh(big.NewInt(1), uint(int64(x.BitLen())/k+1))
+	e := big.NewInt(k)
+	for lo.Cmp(hi) < 0 {
+		mid := new(big.Int).Add(lo, hi)
+		mid.Add(mid, big.NewInt(1))
+		mid.Rsh(mid, 1)
+		if new(big.Int).Exp(mid, e, nil).Cmp(x) <= 0 {
+			lo = mid
+		} else {
+			hi = mid.Sub(mid, big.NewInt(1))
+		}
+	}
+	return lo, new(big.Int).Exp(lo, e, nil).Cmp(x) == 0
+}
+
+// eval walks an expression tree and computes its exact value
+func (ev *rationalEvaluation) eval(node Node) (*big.Rat, error) {
+	switch n := node.(type) {
+	case *NumberNode:
+		value, err := parseRationalLiteral(n.Text)
+		if err != nil {
+			return nil, locate(err, n.span, "")
+		}
+		return value, nil
+
+	case *IdentNode:
+		value, ok := ev.opts.lookup(n.Name)
+		if !ok {
+			if _, irrational := constants[n.Name]; irrational {
+				return nil, &inexactError{fmt.Sprintf("constant %s is irrational", n.Name)}
+			}
+			value, ok = ev.parser.constants[n.Name]
+		}
+		if ok {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				return nil, errorAt(ErrDomain, n.span, "variable '%s' is not a finite number", n.Name)
+			}
+			// Use the shortest decimal form so that 0.1 binds as 1/10
+			r, _ := new(big.Rat).SetString(strconv.FormatFloat(value, 'g', -1, 64))
+			return r, nil
+		}
+		return nil, errorAt(ErrUnknownVariable, n.span, "unknown identifier '%s'", n.Name)
+
+	case *UnaryNode:
+		operand, err := ev.eval(n.Operand)
+		if err != nil {
+			return nil, err
+		}
+		if bitwiseOperators[n.Op] {
+			return nil, unsupportedOperator(n.Op, n.span)
+		}
+		if n.Op == "-" {
+			return new(big.Rat).Neg(operand), nil
+		}
+		return operand, nil
+
+	case *PostfixNode:
+		operand, err := ev.eval(n.Operand)
+		if err != nil {
+			return nil, err
+		}
+		if !operand.IsInt() {
+			return nil, errorAt(ErrDomain, n.span, "factorial error: factorial only defined for integers")
+		}
+		f, err := bigFactorial(new(big.Float).SetInt(operand.Num()), 64)
+		if err != nil {
+			return nil, locate(err, n.span, "factorial error: ")
+		}
+		i, _ := f.Int(nil)
+		return new(big.Rat).SetInt(i), nil
+
+	case *BinaryNode:
+		return ev.evalBinary(n)
+
+	case *CallNode:
+		return ev.evalCall(n)
+	}
+
+	return nil, unsupportedNode(node, "rational mode")
+}
+
+// evalBinary evaluates both operands and applies an infix operator
+func (ev *rationalEvaluation) evalBinary(n *BinaryNode) (*big.Rat, error) {
+	left, err := ev.eval(n.Left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := ev.eval(n.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Op {
+	case "+":
+		return new(big.Rat).Add(left, right), nil
+	case "-":
+		return new(big.Rat).Sub(left, right), nil
+	case "*":
+		return new(big.Rat).Mul(left, right), nil
+	case "/":
+		if right.Sign() == 0 {
+			return nil, errorAt(ErrDivisionByZero, n.span, "division by zero")
+		}
+		return new(big.Rat).Quo(left, right), nil
+	case "^":
+		result, err := ratPowRat(left, right)
+		if err != nil && !isInexact(err) {
+			return nil, locate(err, n.span, "")
+		}
+		return result, err
+	}
+
+	return nil, unsupportedOperator(n.Op, n.span)
+}
+
+// evalCall evaluates a function call exactly, or reports that the call
+// has no exact rational value
+func (ev *rationalEvaluation) evalCall(n *CallNode) (*big.Rat, error) {
+	spec, ok := ev.parser.functions[n.Name]
+	if !ok {
+		if _, user := ev.opts.userFunction(n.Name); user {
+			return nil, &inexactError{fmt.Sprintf("user-defined function %s is evaluated in floating point", n.Name)}
+		}
+		return nil, errorAt(ErrUnknownFunction, n.span, "unknown function '%s'", n.Name)
+	}
+	if err := spec.checkArity(n.Name, len(n.Args)); err != nil {
+		return nil, locate(err, n.span, "")
+	}
+	fn, ok := rationalFunctions[n.Name]
+	if !ok {
+		return nil, &inexactError{fmt.Sprintf("function %s does not have exact rational results", n.Name)}
+	}
+
+	args := make([]*big.Rat, len(n.Args))
+	for i, argNode := range n.Args {
+		arg, err := ev.eval(argNode)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = arg
+	}
+
+	result, err := fn(args)
+	if err != nil {
+		if isInexact(err) {
+			return nil, err
+		}
+		return nil, locate(err, n.span, "error in "+n.Name+" function: ")
+	}
+	return result, nil
+}
+
+// isInexact reports whether err signals a fallback to float64
+func isInexact(err error) bool {
+	var inexact *inexactError
+	return errors.As(err, &inexact)
+}
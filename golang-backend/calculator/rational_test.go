@@ -0,0 +1,110 @@
+package calculator
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestEvaluateRational(t *testing.T) {
+	cases := []struct {
+		expression string
+		want       string
+	}{
+		{"1/3 + 1/6", "1/2"},
+		{"0.1 + 0.2", "3/10"},
+		{"(2/3)^3", "8/27"},
+		{"(2/3)^-2", "9/4"},
+		{"2^100 + 1 - 2^100", "1"},
+		{"1.5e-3", "3/2000"},
+		{"0x10 / 6", "8/3"},
+		{"-7/2", "-7/2"},
+		{"sqrt(9/4)", "3/2"},
+		{"root(3, -27/8)", "-3/2"},
+		{"8^(2/3)", "4"},
+		{"abs(-1/3) + floor(7/2) + ceil(-1/2) + round(5/2)", "19/3"},
+		{"round(2/3, 2)", "67/100"},
+		{"min(1/2, 1/3) + max(1/4, 1/5)", "7/12"},
+		{"mod(-7/2, 2)", "1/2"},
+		{"3! / 4", "3/2"},
+	}
+	for _, tc := range cases {
+		result, err := New().EvaluateRational(tc.expression, EvalOptions{})
+		if err != nil {
+			t.Errorf("%s: %v", tc.expression, err)
+			continue
+		}
+		if !result.Exact() {
+			t.Errorf("%s: fell back to float64: %s", tc.expression, result.Fallback)
+			continue
+		}
+		if got := result.Value.RatString(); got != tc.want {
+			t.Errorf("%s = %s, want %s", tc.expression, got, tc.want)
+		}
+	}
+}
+
+func TestEvaluateRationalFallback(t *testing.T) {
+	cases := []struct {
+		expression string
+		want       float64
+	}{
+		{"sin(30) + 1/3", 0.5 + 1.0/3},
+		{"sqrt(2)", math.Sqrt2},
+		{"2^(1/2)", math.Sqrt2},
+		{"pi/2", math.Pi / 2},
+		{"ln(e)", 1},
+	}
+	for _, tc := range cases {
+		result, err := New().EvaluateRational(tc.expression, EvalOptions{AngleMode: AngleDegree})
+		if err != nil {
+			t.Errorf("%s: %v", tc.expression, err)
+			continue
+		}
+		if result.Exact() || result.Fallback == "" {
+			t.Errorf("%s: exact result %v, want a float64 fallback", tc.expression, result.Value)
+		}
+		if math.Abs(result.Float-tc.want) > 1e-12 {
+			t.Errorf("%s = %v, want %v", tc.expression, result.Float, tc.want)
+		}
+	}
+}
+
+func TestEvaluateRationalErrors(t *testing.T) {
+	cases := []struct {
+		expression string
+		kind       error
+	}{
+		{"1/0", ErrDivisionByZero},
+		{"0^-1", ErrDivisionByZero},
+		{"mod(1, 0)", ErrDivisionByZero},
+		{"sqrt(-4)", ErrDomain},
+		{"2^100000", ErrOverflow},
+		{"(-8)^(1/2)", ErrDomain},
+	}
+	for _, tc := range cases {
+		if _, err := New().EvaluateRational(tc.expression, EvalOptions{}); !errors.Is(err, tc.kind) {
+			t.Errorf("%s: error %v, want %v", tc.expression, err, tc.kind)
+		}
+	}
+}
+
+func TestMixedNumber(t *testing.T) {
+	cases := []struct {
+		value *big.Rat
+		want  string
+	}{
+		{big.NewRat(7, 2), "3 1/2"},
+		{big.NewRat(-7, 2), "-3 1/2"},
+		{big.NewRat(1, 3), "1/3"},
+		{big.NewRat(-1, 3), "-1/3"},
+		{big.NewRat(6, 3), "2"},
+		{big.NewRat(0, 1), "0"},
+	}
+	for _, tc := range cases {
+		if got := MixedNumber(tc.value); got != tc.want {
+			t.Errorf("MixedNumber(%s) = %s, want %s", tc.value.RatString(), got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package calculator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Func implements a registered function. Args have already been checked
+// against the function's arity; opts carries the evaluation's angle mode.
+type Func func(args []float64, opts EvalOptions) (float64, error)
+
+// AngleUse describes how a function interacts with the angle mode
+type AngleUse int
+
+const (
+	// AngleNone marks functions that do not involve angles
+	AngleNone AngleUse = iota
+	// AngleArgument marks functions whose argument is an angle, like sin
+	AngleArgument
+	// AngleResult marks functions that return an angle, like asin
+	AngleResult
+)
+
+// FunctionInfo documents a registered function
+type FunctionInfo struct {
+	Category     string            // such as "trigonometric" or "rounding"
+	Description  string            // in English
+	Descriptions map[string]string // translations keyed by language code, such as "id"
+	Domain       string            // the arguments the function accepts, such as "x > 0"
+	Angle        AngleUse
+	Examples     []string
+}
+
+// DescriptionIn returns the description in the given language, falling
+// back to English when there is no translation
+func (i FunctionInfo) DescriptionIn(lang string) string {
+	if text, ok := i.Descriptions[lang]; ok {
+		return text
+	}
+	return i.Description
+}
+
+// String returns the angle use's API name: "none", "argument" or "result"
+func (a AngleUse) String() string {
+	switch a {
+	case AngleArgument:
+		return "argument"
+	case AngleResult:
+		return "result"
+	}
+	return "none"
+}
+
+// Registry is a set of functions and constants that engines created with
+// WithFunctions can evaluate. Register everything before creating engines:
+// a Registry is not safe for concurrent modification, and each engine
+// keeps its own copy.
+type Registry struct {
+	functions map[string]function
+	constants map[string]constant
+}
+
+// constant is a registered named value
+type constant struct {
+	value       float64
+	description string
+}
+
+// NewRegistry creates a registry holding the built-in functions and
+// constants, ready for custom ones to be added
+func NewRegistry() *Registry {
+	r := &Registry{
+		functions: builtinFunctions(NewBasicOperations(), NewScientificOperations()),
+		constants: make(map[string]constant, len(constants)),
+	}
+	for name, value := range constants {
+		r.constants[name] = constant{value: value, description: constantDescriptions[name]}
+	}
+	return r
+}
+
+// RegisterFunction adds a function callable from expressions. The name
+// must be an identifier not already used by a function or constant.
+func (r *Registry) RegisterFunction(name string, arity Arity, fn Func, info FunctionInfo) error {
+	if err := r.checkNew(name); err != nil {
+		return err
+	}
+	if arity.Min < 0 || (arity.Max >= 0 && arity.Max < arity.Min) {
+		return fmt.Errorf("invalid arity %d to %d for function %s", arity.Min, arity.Max, name)
+	}
+	if fn == nil {
+		return fmt.Errorf("function %s has no implementation", name)
+	}
+	r.functions[name] = function{Arity: arity, eval: fn, info: info}
+	return nil
+}
+
+// RegisterConstant adds a named value usable in expressions
+func (r *Registry) RegisterConstant(name string, value float64, description string) error {
+	if err := r.checkNew(name); err != nil {
+		return err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return fmt.Errorf("constant %s must be a finite number", name)
+	}
+	r.constants[name] = constant{value: value, description: description}
+	return nil
+}
+
+// checkNew verifies that name is an identifier the registry does not use
+func (r *Registry) checkNew(name string) error {
+	runes := []rune(name)
+	if len(runes) == 0 || !isIdentStart(runes[0]) {
+		return fmt.Errorf("invalid name '%s'", name)
+	}
+	for _, ch := range runes[1:] {
+		if !isIdentPart(ch) || ch == 'π' {
+			return fmt.Errorf("invalid name '%s'", name)
+		}
+	}
+	if name == "i" {
+		return errors.New("name 'i' is reserved for the imaginary unit")
+	}
+	if keywordOperators[name] {
+		return fmt.Errorf("name '%s' is reserved for an operator", name)
+	}
+	if _, ok := matrixFunctions[name]; ok {
+		return fmt.Errorf("name '%s' is reserved for a matrix function", name)
+	}
+	if _, ok := r.functions[name]; ok {
+		return fmt.Errorf("function %s is already registered", name)
+	}
+	if _, ok := r.constants[name]; ok {
+		return fmt.Errorf("constant %s is already registered", name)
+	}
+	return nil
+}
+
+// FunctionNames returns the sorted names of the registered functions
+func (r *Registry) FunctionNames() []string {
+	names := make([]string, 0, len(r.functions))
+	for name := range r.functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// clone returns a copy that later registrations do not affect
+func (r *Registry) clone() *Registry {
+	c := &Registry{
+		functions: make(map[string]function, len(r.functions)),
+		constants: make(map[string]constant, len(r.constants)),
+	}
+	for name, fn := range r.functions {
+		c.functions[name] = fn
+	}
+	for name, value := range r.constants {
+		c.constants[name] = value
+	}
+	return c
+}
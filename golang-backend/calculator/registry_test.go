@@ -0,0 +1,149 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+)
+
+// double is a custom function for the registry tests
+func double(args []float64, opts EvalOptions) (float64, error) {
+	return 2 * args[0], nil
+}
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry()
+	if err := reg.RegisterFunction("double", Arity{1, 1}, double, FunctionInfo{Description: "Twice x"}); err != nil {
+		t.Fatal(err)
+	}
+	total := func(args []float64, opts EvalOptions) (float64, error) {
+		sum := 0.0
+		for _, a := range args {
+			sum += a
+		}
+		return sum, nil
+	}
+	if err := reg.RegisterFunction("total", Arity{0, -1}, total, FunctionInfo{}); err != nil {
+		t.Fatal(err)
+	}
+	inDegrees := func(args []float64, opts EvalOptions) (float64, error) {
+		if opts.AngleMode.IsDegree() {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	if err := reg.RegisterFunction("indeg", Arity{0, 0}, inDegrees, FunctionInfo{Angle: AngleArgument}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterConstant("g0", 9.80665, "Standard gravity"); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		opts []Option
+		expr string
+		want float64
+	}{
+		{nil, "double(21)", 42},
+		{nil, "total()", 0},
+		{nil, "total(1, 2, 3, 4)", 10},
+		{nil, "double(g0)", 19.6133},
+		{nil, "sin(90) + double(1)", 3},
+		{nil, "indeg()", 1},
+		{[]Option{WithAngleMode(AngleRadian)}, "indeg()", 0},
+		{[]Option{WithAngleMode(AngleRadian)}, "sin(pi/2)", 1},
+	}
+	for _, tc := range cases {
+		engine := New(append([]Option{WithFunctions(reg)}, tc.opts...)...)
+		got, err := engine.Evaluate(tc.expr, EvalOptions{})
+		if err != nil {
+			t.Errorf("%s: %v", tc.expr, err)
+			continue
+		}
+		if math.Abs(got-tc.want) > 1e-12 {
+			t.Errorf("%s = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+
+	// An explicit angle mode overrides the engine's default
+	engine := New(WithFunctions(reg), WithAngleMode(AngleRadian))
+	if got, err := engine.Evaluate("indeg()", EvalOptions{AngleMode: AngleDegree}); err != nil || got != 1 {
+		t.Errorf("indeg() in degree mode = %v, %v", got, err)
+	}
+
+	if constants := engine.Constants(); constants["g0"] != 9.80665 || constants["e"] != math.E {
+		t.Errorf("constants = %v", constants)
+	}
+}
+
+func TestRegistryIsolation(t *testing.T) {
+	reg := NewRegistry()
+	engine := New(WithFunctions(reg))
+	if err := reg.RegisterFunction("double", Arity{1, 1}, double, FunctionInfo{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Engines copy the registry when they are created
+	if _, err := engine.Evaluate("double(1)", EvalOptions{}); err == nil {
+		t.Error("a function registered after the engine was created is callable")
+	}
+	if _, err := New().Evaluate("double(1)", EvalOptions{}); err == nil {
+		t.Error("a custom function leaked into the default engine")
+	}
+	if got, err := New(WithFunctions(reg)).Evaluate("double(1)", EvalOptions{}); err != nil || got != 2 {
+		t.Errorf("double(1) = %v, %v", got, err)
+	}
+	if err := engine.CheckName("double"); err != nil {
+		t.Errorf("CheckName(double) on an engine without it: %v", err)
+	}
+	if err := New(WithFunctions(reg)).CheckName("double"); err == nil {
+		t.Error("CheckName(double) accepted a registered function")
+	}
+}
+
+func TestRegistryErrors(t *testing.T) {
+	reg := NewRegistry()
+	if err := reg.RegisterConstant("k", 1, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	functions := []struct {
+		name  string
+		arity Arity
+		fn    Func
+	}{
+		{"", Arity{1, 1}, double},
+		{"2x", Arity{1, 1}, double},
+		{"my-fn", Arity{1, 1}, double},
+		{"i", Arity{1, 1}, double},
+		{"xor", Arity{1, 1}, double},
+		{"sin", Arity{1, 1}, double},
+		{"k", Arity{1, 1}, double},
+		{"det", Arity{1, 1}, double},
+		{"identity", Arity{1, 1}, double},
+		{"f", Arity{-1, 1}, double},
+		{"f", Arity{2, 1}, double},
+		{"f", Arity{1, 1}, nil},
+	}
+	for _, tc := range functions {
+		if err := reg.RegisterFunction(tc.name, tc.arity, tc.fn, FunctionInfo{}); err == nil {
+			t.Errorf("RegisterFunction(%q, %v) succeeded", tc.name, tc.arity)
+		}
+	}
+
+	constants := []struct {
+		name  string
+		value float64
+	}{
+		{"pi", 3},
+		{"k", 2},
+		{"sqrt", 2},
+		{"trace", 2},
+		{"nan", math.NaN()},
+		{"inf", math.Inf(1)},
+	}
+	for _, tc := range constants {
+		if err := reg.RegisterConstant(tc.name, tc.value, ""); err == nil {
+			t.Errorf("RegisterConstant(%q, %v) succeeded", tc.name, tc.value)
+		}
+	}
+}
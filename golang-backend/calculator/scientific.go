@@ -1,7 +1,6 @@
 package calculator
 
 import (
-	"errors"
 	"math"
 )
 
@@ -41,10 +40,55 @@ func (s *ScientificOperations) RadiansToDegrees(radians float64) float64 {
 
 // Trigonometric Functions
 
+// exactDegrees holds sine and cosine at the angles in [0°, 45°] where
+// they have closed forms, as the nearest float64 values
+var exactDegrees = map[float64][2]float64{
+	0:  {0, 1},
+	30: {0.5, math.Sqrt(3) / 2},
+	45: {math.Sqrt2 / 2, math.Sqrt2 / 2},
+}
+
+// degreeSinCos returns the sine and cosine of an angle in degrees. The
+// angle is reduced exactly modulo 360 and then to within 45° of a
+// quadrant boundary, so multiples of 30° and 45° give exact values and
+// the poles of tangent give an exact zero cosine.
+func degreeSinCos(degrees float64) (sin, cos float64) {
+	reduced := math.Mod(degrees, 360)
+	quadrant := math.Round(reduced / 90)
+	offset := reduced - 90*quadrant
+
+	if exact, ok := exactDegrees[math.Abs(offset)]; ok {
+		sin, cos = math.Copysign(exact[0], offset), exact[1]
+	} else {
+		radians := offset * (PI / 180.0)
+		sin, cos = math.Sin(radians), math.Cos(radians)
+	}
+
+	switch int(quadrant) & 3 {
+	case 1:
+		sin, cos = cos, -sin
+	case 2:
+		sin, cos = -sin, -cos
+	case 3:
+		sin, cos = -cos, sin
+	}
+	// Fold -0 into 0 so sin(-180) and cos(90) are plain zeros
+	return sin + 0, cos + 0
+}
+
+// sinCos returns the sine and cosine of an angle in degrees or radians
+func (s *ScientificOperations) sinCos(value float64, isDegree bool) (float64, float64) {
+	if isDegree {
+		return degreeSinCos(value)
+	}
+	return math.Sincos(value)
+}
+
 // Sin calculates sine
 func (s *ScientificOperations) Sin(value float64, isDegree bool) float64 {
 	if isDegree {
-		value = s.DegreesToRadians(value)
+		sin, _ := degreeSinCos(value)
+		return sin
 	}
 	return math.Sin(value)
 }
@@ -52,22 +96,49 @@ func (s *ScientificOperations) Sin(value float64, isDegree bool) float64 {
 // Cos calculates cosine
 func (s *ScientificOperations) Cos(value float64, isDegree bool) float64 {
 	if isDegree {
-		value = s.DegreesToRadians(value)
+		_, cos := degreeSinCos(value)
+		return cos
 	}
 	return math.Cos(value)
 }
 
 // Tan calculates tangent
 func (s *ScientificOperations) Tan(value float64, isDegree bool) (float64, error) {
-	if isDegree {
-		value = s.DegreesToRadians(value)
+	if !isDegree {
+		return math.Tan(value), nil
 	}
-	
-	result := math.Tan(value)
-	if math.IsInf(result, 0) {
-		return 0, errors.New("tangent is undefined at this point")
+	sin, cos := degreeSinCos(value)
+	if cos == 0 {
+		return 0, newError(ErrDomain, "tangent is undefined at this point")
 	}
-	return result, nil
+	return sin / cos, nil
+}
+
+// Sec calculates secant
+func (s *ScientificOperations) Sec(value float64, isDegree bool) (float64, error) {
+	_, cos := s.sinCos(value, isDegree)
+	if cos == 0 {
+		return 0, newError(ErrDomain, "secant is undefined at this point")
+	}
+	return 1 / cos, nil
+}
+
+// Csc calculates cosecant
+func (s *ScientificOperations) Csc(value float64, isDegree bool) (float64, error) {
+	sin, _ := s.sinCos(value, isDegree)
+	if sin == 0 {
+		return 0, newError(ErrDomain, "cosecant is undefined at this point")
+	}
+	return 1 / sin, nil
+}
+
+// Cot calculates cotangent
+func (s *ScientificOperations) Cot(value float64, isDegree bool) (float64, error) {
+	sin, cos := s.sinCos(value, isDegree)
+	if sin == 0 {
+		return 0, newError(ErrDomain, "cotangent is undefined at this point")
+	}
+	return cos / sin, nil
 }
 
 // Inverse Trigonometric Functions
@@ -75,7 +146,7 @@ func (s *ScientificOperations) Tan(value float64, isDegree bool) (float64, error
 // Asin calculates inverse sine (arcsin)
 func (s *ScientificOperations) Asin(value float64, returnDegree bool) (float64, error) {
 	if value < -1 || value > 1 {
-		return 0, errors.New("arcsin domain error: value must be between -1 and 1")
+		return 0, newError(ErrDomain, "arcsin domain error: value must be between -1 and 1")
 	}
 	
 	result := math.Asin(value)
@@ -88,7 +159,7 @@ func (s *ScientificOperations) Asin(value float64, returnDegree bool) (float64,
 // Acos calculates inverse cosine (arccos)
 func (s *ScientificOperations) Acos(value float64, returnDegree bool) (float64, error) {
 	if value < -1 || value > 1 {
-		return 0, errors.New("arccos domain error: value must be between -1 and 1")
+		return 0, newError(ErrDomain, "arccos domain error: value must be between -1 and 1")
 	}
 	
 	result := math.Acos(value)
@@ -107,12 +178,52 @@ func (s *ScientificOperations) Atan(value float64, returnDegree bool) float64 {
 	return result
 }
 
+// Asec calculates inverse secant, the angle in [0, π] whose secant is value
+func (s *ScientificOperations) Asec(value float64, returnDegree bool) (float64, error) {
+	if value > -1 && value < 1 {
+		return 0, newError(ErrDomain, "arcsec domain error: |value| must be at least 1")
+	}
+	return s.Acos(1/value, returnDegree)
+}
+
+// Acsc calculates inverse cosecant, the angle in [-π/2, π/2] whose
+// cosecant is value
+func (s *ScientificOperations) Acsc(value float64, returnDegree bool) (float64, error) {
+	if value > -1 && value < 1 {
+		return 0, newError(ErrDomain, "arccsc domain error: |value| must be at least 1")
+	}
+	return s.Asin(1/value, returnDegree)
+}
+
+// Acot calculates inverse cotangent, the angle in (0, π) whose cotangent
+// is value
+func (s *ScientificOperations) Acot(value float64, returnDegree bool) float64 {
+	result := PI/2 - math.Atan(value)
+	if returnDegree {
+		result = s.RadiansToDegrees(result)
+	}
+	return result
+}
+
+// Atan2 calculates the angle of the point (x, y) from the positive x axis
+func (s *ScientificOperations) Atan2(y, x float64, returnDegree bool) (float64, error) {
+	if x == 0 && y == 0 {
+		return 0, newError(ErrDomain, "atan2 is undefined at the origin")
+	}
+
+	result := math.Atan2(y, x)
+	if returnDegree {
+		result = s.RadiansToDegrees(result)
+	}
+	return result, nil
+}
+
 // Logarithmic Functions
 
 // Log calculates base-10 logarithm
 func (s *ScientificOperations) Log(value float64) (float64, error) {
 	if value <= 0 {
-		return 0, errors.New("logarithm domain error: value must be positive")
+		return 0, newError(ErrDomain, "logarithm domain error: value must be positive")
 	}
 	return math.Log10(value), nil
 }
@@ -120,7 +231,7 @@ func (s *ScientificOperations) Log(value float64) (float64, error) {
 // Ln calculates natural logarithm (base-e)
 func (s *ScientificOperations) Ln(value float64) (float64, error) {
 	if value <= 0 {
-		return 0, errors.New("natural logarithm domain error: value must be positive")
+		return 0, newError(ErrDomain, "natural logarithm domain error: value must be positive")
 	}
 	return math.Log(value), nil
 }
@@ -128,10 +239,10 @@ func (s *ScientificOperations) Ln(value float64) (float64, error) {
 // LogBase calculates logarithm with custom base
 func (s *ScientificOperations) LogBase(value, base float64) (float64, error) {
 	if value <= 0 {
-		return 0, errors.New("logarithm domain error: value must be positive")
+		return 0, newError(ErrDomain, "logarithm domain error: value must be positive")
 	}
 	if base <= 0 || base == 1 {
-		return 0, errors.New("logarithm base error: base must be positive and not equal to 1")
+		return 0, newError(ErrDomain, "logarithm base error: base must be positive and not equal to 1")
 	}
 	return math.Log(value) / math.Log(base), nil
 }
@@ -142,7 +253,7 @@ func (s *ScientificOperations) LogBase(value, base float64) (float64, error) {
 func (s *ScientificOperations) Exp(value float64) (float64, error) {
 	result := math.Exp(value)
 	if math.IsInf(result, 0) {
-		return 0, errors.New("exponential overflow")
+		return 0, newError(ErrOverflow, "exponential overflow")
 	}
 	return result, nil
 }
@@ -151,7 +262,7 @@ func (s *ScientificOperations) Exp(value float64) (float64, error) {
 func (s *ScientificOperations) Exp10(value float64) (float64, error) {
 	result := math.Pow(10, value)
 	if math.IsInf(result, 0) {
-		return 0, errors.New("exponential overflow")
+		return 0, newError(ErrOverflow, "exponential overflow")
 	}
 	return result, nil
 }
@@ -160,7 +271,7 @@ func (s *ScientificOperations) Exp10(value float64) (float64, error) {
 func (s *ScientificOperations) Exp2(value float64) (float64, error) {
 	result := math.Exp2(value)
 	if math.IsInf(result, 0) {
-		return 0, errors.New("exponential overflow")
+		return 0, newError(ErrOverflow, "exponential overflow")
 	}
 	return result, nil
 }
@@ -171,7 +282,7 @@ func (s *ScientificOperations) Exp2(value float64) (float64, error) {
 func (s *ScientificOperations) Sinh(value float64) (float64, error) {
 	result := math.Sinh(value)
 	if math.IsInf(result, 0) {
-		return 0, errors.New("hyperbolic sine overflow")
+		return 0, newError(ErrOverflow, "hyperbolic sine overflow")
 	}
 	return result, nil
 }
@@ -180,7 +291,7 @@ func (s *ScientificOperations) Sinh(value float64) (float64, error) {
 func (s *ScientificOperations) Cosh(value float64) (float64, error) {
 	result := math.Cosh(value)
 	if math.IsInf(result, 0) {
-		return 0, errors.New("hyperbolic cosine overflow")
+		return 0, newError(ErrOverflow, "hyperbolic cosine overflow")
 	}
 	return result, nil
 }
@@ -190,13 +301,37 @@ func (s *ScientificOperations) Tanh(value float64) float64 {
 	return math.Tanh(value)
 }
 
+// Asinh calculates inverse hyperbolic sine
+func (s *ScientificOperations) Asinh(value float64) float64 {
+	return math.Asinh(value)
+}
+
+// Acosh calculates inverse hyperbolic cosine
+func (s *ScientificOperations) Acosh(value float64) (float64, error) {
+	if value < 1 {
+		return 0, newError(ErrDomain, "arccosh domain error: value must be at least 1")
+	}
+	return math.Acosh(value), nil
+}
+
+// Atanh calculates inverse hyperbolic tangent
+func (s *ScientificOperations) Atanh(value float64) (float64, error) {
+	if value <= -1 || value >= 1 {
+		return 0, newError(ErrDomain, "arctanh domain error: value must be between -1 and 1, exclusive")
+	}
+	return math.Atanh(value), nil
+}
+
 // Additional Functions
 
 // Gamma calculates gamma function
 func (s *ScientificOperations) Gamma(value float64) (float64, error) {
 	result := math.Gamma(value)
-	if math.IsNaN(result) || math.IsInf(result, 0) {
-		return 0, errors.New("gamma function domain or overflow error")
+	if math.IsNaN(result) || (math.IsInf(result, 0) && value == math.Trunc(value) && value <= 0) {
+		return 0, newError(ErrDomain, "gamma function is undefined at non-positive integers")
+	}
+	if math.IsInf(result, 0) {
+		return 0, newError(ErrOverflow, "gamma function overflow")
 	}
 	return result, nil
 }
@@ -214,4 +349,23 @@ func (s *ScientificOperations) Ceil(value float64) float64 {
 // Round rounds to nearest integer
 func (s *ScientificOperations) Round(value float64) float64 {
 	return math.Round(value)
+}
+
+// RoundTo rounds to the given number of decimal places; negative digits
+// round to the left of the decimal point
+func (s *ScientificOperations) RoundTo(value float64, digits int) float64 {
+	scale := math.Pow(10, float64(digits))
+	if math.IsInf(scale, 0) || scale == 0 {
+		return value
+	}
+	return math.Round(value*scale) / scale
+}
+
+// Hypot calculates the Euclidean norm of its arguments
+func (s *ScientificOperations) Hypot(values ...float64) float64 {
+	result := 0.0
+	for _, v := range values {
+		result = math.Hypot(result, v)
+	}
+	return result
 }
\ No newline at end of file
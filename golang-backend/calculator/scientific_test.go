@@ -0,0 +1,207 @@
+package calculator
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestTrigonometricFamily(t *testing.T) {
+	cases := []struct {
+		expr string
+		mode AngleMode
+		want float64
+	}{
+		{"sec(60)", AngleDegree, 2},
+		{"csc(30)", AngleDegree, 2},
+		{"cot(45)", AngleDegree, 1},
+		{"sec(pi)", AngleRadian, -1},
+		{"cot(pi/4)", AngleRadian, 1},
+		{"asec(2)", AngleDegree, 60},
+		{"acsc(2)", AngleDegree, 30},
+		{"acot(1)", AngleDegree, 45},
+		{"acot(-1)", AngleDegree, 135},
+		{"asec(-1)", AngleRadian, math.Pi},
+		{"acsc(-1)", AngleRadian, -math.Pi / 2},
+		{"sinh(0)", AngleDegree, 0},
+		{"cosh(0)", AngleDegree, 1},
+		{"tanh(1)", AngleDegree, math.Tanh(1)},
+		{"asinh(sinh(2))", AngleDegree, 2},
+		{"acosh(cosh(2))", AngleDegree, 2},
+		{"atanh(tanh(0.5))", AngleDegree, 0.5},
+		{"cbrt(-27)", AngleDegree, -3},
+	}
+	p := New()
+	for _, tc := range cases {
+		got, err := p.Evaluate(tc.expr, EvalOptions{AngleMode: tc.mode})
+		if err != nil {
+			t.Errorf("%s: %v", tc.expr, err)
+			continue
+		}
+		if math.Abs(got-tc.want) > 1e-12 {
+			t.Errorf("%s = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestTrigonometricDomains(t *testing.T) {
+	cases := []struct {
+		expr string
+		mode AngleMode
+		kind error
+	}{
+		{"sec(90)", AngleDegree, ErrDomain},
+		{"csc(0)", AngleDegree, ErrDomain},
+		{"csc(180)", AngleDegree, ErrDomain},
+		{"cot(0)", AngleRadian, ErrDomain},
+		{"asec(0.5)", AngleDegree, ErrDomain},
+		{"acsc(0)", AngleDegree, ErrDomain},
+		{"acosh(0.5)", AngleDegree, ErrDomain},
+		{"atanh(1)", AngleDegree, ErrDomain},
+		{"atanh(-1)", AngleDegree, ErrDomain},
+		{"sinh(1000)", AngleDegree, ErrOverflow},
+		{"cosh(-1000)", AngleDegree, ErrOverflow},
+	}
+	p := New()
+	for _, tc := range cases {
+		if _, err := p.Evaluate(tc.expr, EvalOptions{AngleMode: tc.mode}); !errors.Is(err, tc.kind) {
+			t.Errorf("%s: error %v, want %v", tc.expr, err, tc.kind)
+		}
+	}
+}
+
+// TestCallMatchesExpressions checks that the direct call path used by
+// /api/scientific and the expression path share one dispatch table
+func TestCallMatchesExpressions(t *testing.T) {
+	names := []string{
+		"sin", "cos", "tan", "sec", "csc", "cot",
+		"asin", "acos", "atan", "asec", "acsc", "acot",
+		"sinh", "cosh", "tanh", "asinh", "acosh", "atanh", "cbrt",
+	}
+	p := New()
+	for _, name := range names {
+		for _, x := range []float64{0.5, 1.5, 37} {
+			for _, mode := range []AngleMode{AngleDegree, AngleRadian} {
+				opts := EvalOptions{AngleMode: mode, Variables: map[string]float64{"x": x}}
+				want, wantErr := p.Evaluate(name+"(x)", opts)
+				got, err := p.Call(name, []float64{x}, opts)
+				if (err == nil) != (wantErr == nil) || (err == nil && got != want) {
+					t.Errorf("%s(%v) in mode %d: Call = %v, %v; Evaluate = %v, %v", name, x, mode, got, err, want, wantErr)
+				}
+			}
+		}
+	}
+	if _, err := p.Call("nosuch", []float64{1}, EvalOptions{}); !errors.Is(err, ErrUnknownFunction) {
+		t.Errorf("nosuch: error %v, want %v", err, ErrUnknownFunction)
+	}
+}
+
+func TestDegreeSpecialAngles(t *testing.T) {
+	s := NewScientificOperations()
+	half, root3, root2 := 0.5, math.Sqrt(3)/2, math.Sqrt2/2
+	cases := []struct {
+		degrees  float64
+		sin, cos float64
+	}{
+		{0, 0, 1},
+		{30, half, root3},
+		{45, root2, root2},
+		{60, root3, half},
+		{90, 1, 0},
+		{120, root3, -half},
+		{135, root2, -root2},
+		{150, half, -root3},
+		{180, 0, -1},
+		{210, -half, -root3},
+		{270, -1, 0},
+		{300, -root3, half},
+		{330, -half, root3},
+		{360, 0, 1},
+		{-90, -1, 0},
+		{-180, 0, -1},
+		{720 + 30, half, root3},
+		{-3600 + 45, root2, root2},
+		{1e6 * 360, 0, 1},
+	}
+	for _, tc := range cases {
+		// Exact means bit-for-bit equal to the nearest float64 value
+		if got := s.Sin(tc.degrees, true); got != tc.sin {
+			t.Errorf("sin(%v°) = %v, want %v", tc.degrees, got, tc.sin)
+		}
+		if got := s.Cos(tc.degrees, true); got != tc.cos {
+			t.Errorf("cos(%v°) = %v, want %v", tc.degrees, got, tc.cos)
+		}
+	}
+	for _, degrees := range []float64{-180, 0, 180, 360} {
+		if got := s.Sin(degrees, true); math.Signbit(got) {
+			t.Errorf("sin(%v°) = -0, want 0", degrees)
+		}
+	}
+}
+
+func TestDegreeTangent(t *testing.T) {
+	s := NewScientificOperations()
+	cases := []struct {
+		degrees float64
+		want    float64
+	}{
+		{0, 0},
+		{45, 1},
+		{135, -1},
+		{180, 0},
+		{225, 1},
+		{-45, -1},
+		{60, math.Sqrt(3)},
+	}
+	for _, tc := range cases {
+		got, err := s.Tan(tc.degrees, true)
+		if err != nil {
+			t.Errorf("tan(%v°): %v", tc.degrees, err)
+			continue
+		}
+		if math.Abs(got-tc.want) > 1e-15 {
+			t.Errorf("tan(%v°) = %v, want %v", tc.degrees, got, tc.want)
+		}
+	}
+
+	poles := []struct {
+		name    string
+		fn      func(float64, bool) (float64, error)
+		degrees []float64
+	}{
+		{"tan", s.Tan, []float64{90, 270, -90, 450, 90 + 360*1e6}},
+		{"sec", s.Sec, []float64{90, -270}},
+		{"csc", s.Csc, []float64{0, 180, -360}},
+		{"cot", s.Cot, []float64{0, 180, 540}},
+	}
+	for _, pole := range poles {
+		for _, degrees := range pole.degrees {
+			if _, err := pole.fn(degrees, true); !errors.Is(err, ErrDomain) {
+				t.Errorf("%s(%v°): error %v, want %v", pole.name, degrees, err, ErrDomain)
+			}
+		}
+	}
+}
+
+func TestDegreeExpressions(t *testing.T) {
+	cases := []struct {
+		expr string
+		want float64
+	}{
+		{"sin(180)", 0},
+		{"cos(90)", 0},
+		{"sin(30) * 2", 1},
+		{"cos(60) + sin(30)", 1},
+		{"sin(180) + cos(270)", 0},
+	}
+	p := New()
+	for _, tc := range cases {
+		got, err := p.Evaluate(tc.expr, EvalOptions{AngleMode: AngleDegree})
+		if err != nil || got != tc.want {
+			t.Errorf("%s = %v, %v; want exactly %v", tc.expr, got, err, tc.want)
+		}
+	}
+	if _, err := p.Evaluate("tan(90)", EvalOptions{AngleMode: AngleDegree}); !errors.Is(err, ErrDomain) {
+		t.Errorf("tan(90): error %v, want %v", err, ErrDomain)
+	}
+}
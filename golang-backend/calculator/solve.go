@@ -0,0 +1,430 @@
+package calculator
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// defaultSolveTolerance is the root accuracy when the caller sets none
+const defaultSolveTolerance = 1e-12
+
+// defaultSolveResidual is the largest residual a root found without a
+// sign change may have when the caller sets no limit
+const defaultSolveResidual = 1e-9
+
+// defaultSolveIterations bounds the iterations spent on each root
+const defaultSolveIterations = 100
+
+// maxSolveIterations bounds the iterations per root a caller may ask for
+const maxSolveIterations = 10000
+
+// defaultSolveSamples is how many subintervals the scan uses by default
+const defaultSolveSamples = 1000
+
+// maxSolveSamples bounds the subintervals a caller may ask for
+const maxSolveSamples = 100000
+
+// Equation is a parsed equation lhs = rhs. An expression without '='
+// stands for expression = 0.
+type Equation struct {
+	Left, Right Node
+	Source      string
+}
+
+// ParseEquation parses an equation of the form lhs = rhs. Spans in both
+// sides refer to the whole input.
+func ParseEquation(input string) (*Equation, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	assign := -1
+	for i, tok := range tokens {
+		if tok.kind == tokAssign {
+			assign = i
+			break
+		}
+	}
+	if assign < 0 {
+		tree, err := Parse(input)
+		if err != nil {
+			return nil, err
+		}
+		end := tokens[len(tokens)-1].end
+		return &Equation{Left: tree, Right: &NumberNode{Text: "0", span: Span{end, end}}, Source: input}, nil
+	}
+
+	side := func(toks []token) (Node, error) {
+		sp := &syntaxParser{tokens: toks}
+		if sp.peek().kind == tokEOF {
+			return nil, sp.unexpected(sp.peek())
+		}
+		tree, err := sp.parseBinary(precOr)
+		if err != nil {
+			return nil, err
+		}
+		if tok := sp.peek(); tok.kind != tokEOF {
+			return nil, sp.unexpected(tok)
+		}
+		return tree, nil
+	}
+
+	// The left side ends where '=' stands, so a missing side is reported there
+	eq := tokens[assign]
+	left := append(tokens[:assign:assign], token{tokEOF, "", eq.pos, eq.pos})
+	lhs, err := side(left)
+	if err != nil {
+		return nil, err
+	}
+	rhs, err := side(tokens[assign+1:])
+	if err != nil {
+		return nil, err
+	}
+	return &Equation{Left: lhs, Right: rhs, Source: strings.TrimSpace(input)}, nil
+}
+
+// Difference returns the expression lhs - rhs, whose zeros solve the
+// equation
+func (eq *Equation) Difference() Node {
+	span := Span{eq.Left.Span().Start, eq.Right.Span().End}
+	return &BinaryNode{Op: "-", Left: eq.Left, Right: eq.Right, span: span}
+}
+
+// SolveOptions sets the interval searched for roots and the accuracy
+type SolveOptions struct {
+	Lower, Upper float64
+	Tolerance    float64 // accuracy of each root; 0 means 1e-12
+	// Residual is the largest |lhs - rhs| accepted for a root where the
+	// two sides touch without crossing; 0 means 1e-9
+	Residual      float64
+	MaxIterations int // iterations per root; 0 means 100
+	Samples       int // subintervals scanned for sign changes; 0 means 1000
+}
+
+// Root is one solution of an equation
+type Root struct {
+	Value      float64
+	Residual   float64 // lhs - rhs at Value
+	Iterations int
+	Converged  bool
+	// Method is how the root was found: "brent" for a sign change refined
+	// by Brent's method and Newton steps, "newton" for a root where the
+	// sides touch without crossing, "exact" for a scan point that solved
+	// the equation outright
+	Method string
+}
+
+// Solution lists the roots found in an interval in increasing order
+type Solution struct {
+	Roots       []Root
+	Evaluations int // evaluations of lhs - rhs, including the scan
+}
+
+// Solve finds the real roots of an equation in one variable within an
+// interval. The interval is scanned at evenly spaced points; each sign
+// change is bracketed and refined with Brent's method and then polished
+// with Newton steps, and each point where |lhs - rhs| dips without
+// changing sign is tried as the start of a Newton iteration. Sign changes
+// across a pole are discarded. Other identifiers are bound from opts as
+// in Run.
+func (p *ExpressionParser) Solve(equation, variable string, bounds SolveOptions, opts EvalOptions) (*Solution, error) {
+	eq, err := ParseEquation(equation)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckName(variable); err != nil {
+		return nil, err
+	}
+	s, err := p.newSolver(eq, variable, bounds, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.solve()
+}
+
+// solver holds the state of one search for roots
+type solver struct {
+	f           func(float64) (float64, error)
+	df          func(float64) (float64, error)
+	lower       float64
+	upper       float64
+	tol         float64
+	residual    float64
+	iterations  int
+	samples     int
+	evaluations int
+}
+
+func (p *ExpressionParser) newSolver(eq *Equation, variable string, bounds SolveOptions, opts EvalOptions) (*solver, error) {
+	s := &solver{
+		lower:      bounds.Lower,
+		upper:      bounds.Upper,
+		tol:        bounds.Tolerance,
+		residual:   bounds.Residual,
+		iterations: bounds.MaxIterations,
+		samples:    bounds.Samples,
+	}
+	if math.IsNaN(s.lower) || math.IsNaN(s.upper) || math.IsInf(s.lower, 0) || math.IsInf(s.upper, 0) {
+		return nil, newError(ErrDomain, "the interval must have finite bounds")
+	}
+	if s.lower >= s.upper {
+		return nil, newError(ErrDomain, "the lower bound must be less than the upper bound")
+	}
+	if s.tol == 0 {
+		s.tol = defaultSolveTolerance
+	}
+	if s.residual == 0 {
+		s.residual = defaultSolveResidual
+	}
+	if !(s.tol > 0) || !(s.residual > 0) || math.IsInf(s.tol, 0) || math.IsInf(s.residual, 0) {
+		return nil, newError(ErrDomain, "tolerances must be positive numbers")
+	}
+	if s.iterations == 0 {
+		s.iterations = defaultSolveIterations
+	}
+	if s.samples == 0 {
+		s.samples = defaultSolveSamples
+	}
+	if s.iterations < 1 || s.iterations > maxSolveIterations {
+		return nil, newErrorf(ErrDomain, "the iteration limit must be between 1 and %d", maxSolveIterations)
+	}
+	if s.samples < 1 || s.samples > maxSolveSamples {
+		return nil, newErrorf(ErrDomain, "samples must be between 1 and %d", maxSolveSamples)
+	}
+
+	vars := make(map[string]float64, len(opts.Variables)+1)
+	for name, value := range opts.Variables {
+		vars[name] = value
+	}
+	run := func(prog *Program, x float64) (float64, error) {
+		vars[variable] = x
+		return prog.Run(EvalOptions{AngleMode: opts.AngleMode, Variables: vars, Scope: opts.Scope})
+	}
+
+	diff := eq.Difference()
+	prog := &Program{source: eq.Source, tree: diff, parser: p}
+	s.f = func(x float64) (float64, error) {
+		s.evaluations++
+		return run(prog, x)
+	}
+
+	// Newton steps use the symbolic derivative where there is one and a
+	// central difference otherwise
+	if deriv, err := p.Derivative(FormatExpression(diff), variable, opts); err == nil {
+		s.df = func(x float64) (float64, error) { return run(deriv, x) }
+	} else {
+		s.df = func(x float64) (float64, error) {
+			h := 1e-6 * math.Max(1, math.Abs(x))
+			above, err := run(prog, x+h)
+			if err != nil {
+				return 0, err
+			}
+			below, err := run(prog, x-h)
+			if err != nil {
+				return 0, err
+			}
+			return (above - below) / (2 * h), nil
+		}
+	}
+	return s, nil
+}
+
+func (s *solver) solve() (*Solution, error) {
+	n := s.samples
+	xs := make([]float64, n+1)
+	fs := make([]float64, n+1)
+	ok := make([]bool, n+1)
+	var firstErr error
+	evaluated := 0
+	for i := range xs {
+		xs[i] = s.lower + (s.upper-s.lower)*float64(i)/float64(n)
+		value, err := s.f(xs[i])
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		fs[i], ok[i] = value, true
+		evaluated++
+	}
+	if evaluated == 0 {
+		// Nothing could be evaluated, so the error is not about the domain
+		return nil, firstErr
+	}
+
+	var roots []Root
+	for i := range xs {
+		if ok[i] && fs[i] == 0 {
+			roots = append(roots, Root{Value: xs[i], Converged: true, Method: "exact"})
+		}
+	}
+	for i := 0; i < n; i++ {
+		if !ok[i] || !ok[i+1] || fs[i] == 0 || fs[i+1] == 0 || (fs[i] < 0) == (fs[i+1] < 0) {
+			continue
+		}
+		if root, found := s.bracketed(xs[i], xs[i+1], fs[i], fs[i+1]); found {
+			roots = append(roots, root)
+		}
+	}
+	touching := func(x, lo, hi float64) {
+		root := s.newton(x, lo, hi)
+		if root.Converged && math.Abs(root.Residual) <= s.residual {
+			root.Method = "newton"
+			roots = append(roots, root)
+		}
+	}
+	for i := 1; i < n; i++ {
+		if !ok[i-1] || !ok[i] || !ok[i+1] || fs[i] == 0 {
+			continue
+		}
+		sameSign := (fs[i-1] < 0) == (fs[i] < 0) && (fs[i] < 0) == (fs[i+1] < 0)
+		if sameSign && math.Abs(fs[i]) < math.Abs(fs[i-1]) && math.Abs(fs[i]) <= math.Abs(fs[i+1]) {
+			touching(xs[i], xs[i-1], xs[i+1])
+		}
+	}
+	// A root at either end of the interval shows no sign change, and the
+	// scan rarely lands on it exactly: sin(x) at pi is about 1e-16
+	if ok[0] && fs[0] != 0 && math.Abs(fs[0]) <= s.residual {
+		touching(xs[0], xs[0], xs[1])
+	}
+	if ok[n] && fs[n] != 0 && math.Abs(fs[n]) <= s.residual {
+		touching(xs[n], xs[n-1], xs[n])
+	}
+
+	return &Solution{Roots: s.distinct(roots), Evaluations: s.evaluations}, nil
+}
+
+// bracketed refines a sign change between a and b. It reports false when
+// the change turns out to be a pole or a gap in the domain rather than a
+// root.
+func (s *solver) bracketed(a, b, fa, fb float64) (Root, bool) {
+	root, err := s.brent(a, b, fa, fb)
+	if err != nil || math.Abs(root.Residual) > math.Max(math.Abs(fa), math.Abs(fb)) {
+		return Root{}, false
+	}
+	if root.Residual != 0 {
+		polished := s.newton(root.Value, a, b)
+		if polished.Converged && math.Abs(polished.Residual) <= math.Abs(root.Residual) {
+			root.Value, root.Residual = polished.Value, polished.Residual
+			root.Iterations += polished.Iterations
+		}
+	}
+	return root, true
+}
+
+// brent finds a root of f bracketed by a and b with Brent's method, which
+// takes inverse quadratic and secant steps while they shrink the bracket
+// fast enough and bisects otherwise
+func (s *solver) brent(a, b, fa, fb float64) (Root, error) {
+	c, fc := b, fb
+	var d, e float64
+	for iter := 1; iter <= s.iterations; iter++ {
+		if (fb > 0) == (fc > 0) {
+			c, fc = a, fa
+			d = b - a
+			e = d
+		}
+		if math.Abs(fc) < math.Abs(fb) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+		tol := 2*float64Epsilon*math.Abs(b) + s.tol/2
+		mid := (c - b) / 2
+		if math.Abs(mid) <= tol || fb == 0 {
+			return Root{Value: b, Residual: fb, Iterations: iter, Converged: true, Method: "brent"}, nil
+		}
+
+		if math.Abs(e) >= tol && math.Abs(fa) > math.Abs(fb) {
+			var p, q float64
+			ratio := fb / fa
+			if a == c {
+				p = 2 * mid * ratio
+				q = 1 - ratio
+			} else {
+				qa, rb := fa/fc, fb/fc
+				p = ratio * (2*mid*qa*(qa-rb) - (b-a)*(rb-1))
+				q = (qa - 1) * (rb - 1) * (ratio - 1)
+			}
+			if p > 0 {
+				q = -q
+			}
+			p = math.Abs(p)
+			if 2*p < math.Min(3*mid*q-math.Abs(tol*q), math.Abs(e*q)) {
+				e, d = d, p/q
+			} else {
+				d = mid
+				e = d
+			}
+		} else {
+			d = mid
+			e = d
+		}
+
+		a, fa = b, fb
+		if math.Abs(d) > tol {
+			b += d
+		} else {
+			b += math.Copysign(tol, mid)
+		}
+		var err error
+		if fb, err = s.f(b); err != nil {
+			return Root{}, err
+		}
+	}
+	return Root{Value: b, Residual: fb, Iterations: s.iterations, Method: "brent"}, nil
+}
+
+// newton runs Newton's method from x, giving up if an iterate leaves
+// [lo, hi]
+func (s *solver) newton(x, lo, hi float64) Root {
+	fx, err := s.f(x)
+	if err != nil {
+		return Root{Value: x}
+	}
+	for iter := 1; iter <= s.iterations; iter++ {
+		if fx == 0 {
+			return Root{Value: x, Iterations: iter - 1, Converged: true}
+		}
+		slope, err := s.df(x)
+		if err != nil || slope == 0 || math.IsNaN(slope) || math.IsInf(slope, 0) {
+			break
+		}
+		step := fx / slope
+		next := x - step
+		if next < lo || next > hi {
+			break
+		}
+		fnext, err := s.f(next)
+		if err != nil {
+			break
+		}
+		x, fx = next, fnext
+		if math.Abs(step) <= s.tol*math.Max(1, math.Abs(x)) {
+			return Root{Value: x, Residual: fx, Iterations: iter, Converged: true}
+		}
+	}
+	return Root{Value: x, Residual: fx, Iterations: s.iterations}
+}
+
+// distinct sorts roots and merges those that agree to within tolerance,
+// keeping the one with the smaller residual
+func (s *solver) distinct(roots []Root) []Root {
+	sort.Slice(roots, func(i, j int) bool { return roots[i].Value < roots[j].Value })
+	result := []Root{}
+	for _, root := range roots {
+		if last := len(result) - 1; last >= 0 {
+			prev := result[last]
+			if math.Abs(root.Value-prev.Value) <= 10*s.tol*math.Max(1, math.Abs(root.Value)) {
+				if math.Abs(root.Residual) < math.Abs(prev.Residual) {
+					result[last] = root
+				}
+				continue
+			}
+		}
+		result = append(result, root)
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+package calculator
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+	cases := []struct {
+		equation     string
+		lower, upper float64
+		mode         AngleMode
+		want         []float64
+		methods      []string
+	}{
+		{"x^3 - 2x = 5", -10, 10, AngleRadian, []float64{2.0945514815423265}, []string{"brent"}},
+		{"x^2 = 4", -5, 5, AngleRadian, []float64{-2, 2}, nil},
+		{"x^2 - 4", -5, 5, AngleRadian, []float64{-2, 2}, nil},
+		{"sin(x) = 0", -1, 10, AngleRadian, []float64{0, math.Pi, 2 * math.Pi, 3 * math.Pi}, nil},
+		{"sin(x) = 0.5", 0, 180, AngleDegree, []float64{30, 150}, nil},
+		{"(x - 1)^2 = 0", -3, 3.3, AngleRadian, []float64{1}, []string{"newton"}},
+		{"x = 0", -1, 1, AngleRadian, []float64{0}, []string{"exact"}},
+		{"sin(x) = 0", 0, math.Pi, AngleRadian, []float64{0, math.Pi}, nil},
+		{"1/x = 0", -1, 1, AngleRadian, nil, nil},
+		{"x^2 = -1", -5, 5, AngleRadian, nil, nil},
+		{"sqrt(x) = 1", -4, 4, AngleRadian, []float64{1}, nil},
+		{"a*x = b", -10, 10, AngleRadian, []float64{2}, nil},
+	}
+	p := New()
+	for _, tc := range cases {
+		opts := EvalOptions{AngleMode: tc.mode, Variables: map[string]float64{"a": 3, "b": 6}}
+		got, err := p.Solve(tc.equation, "x", SolveOptions{Lower: tc.lower, Upper: tc.upper}, opts)
+		if err != nil {
+			t.Errorf("%s: %v", tc.equation, err)
+			continue
+		}
+		if len(got.Roots) != len(tc.want) {
+			t.Errorf("%s on [%v, %v]: roots %+v, want %v", tc.equation, tc.lower, tc.upper, got.Roots, tc.want)
+			continue
+		}
+		for i, root := range got.Roots {
+			if math.Abs(root.Value-tc.want[i]) > 1e-9 || !root.Converged {
+				t.Errorf("%s: root %+v, want %v", tc.equation, root, tc.want[i])
+			}
+			if math.Abs(root.Residual) > 1e-9 {
+				t.Errorf("%s: root %v has residual %v", tc.equation, root.Value, root.Residual)
+			}
+			if tc.methods != nil && root.Method != tc.methods[i] {
+				t.Errorf("%s: root %v found by %s, want %s", tc.equation, root.Value, root.Method, tc.methods[i])
+			}
+		}
+		if got.Evaluations == 0 {
+			t.Errorf("%s: no evaluations counted", tc.equation)
+		}
+	}
+}
+
+func TestSolveIterationLimit(t *testing.T) {
+	p := New()
+	got, err := p.Solve("x^3 - 2x = 5", "x", SolveOptions{Lower: -10, Upper: 10, Samples: 1, MaxIterations: 2}, EvalOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, root := range got.Roots {
+		if root.Converged || root.Iterations > 2 {
+			t.Errorf("with 2 iterations: %+v", root)
+		}
+	}
+}
+
+func TestSolveErrors(t *testing.T) {
+	cases := []struct {
+		equation string
+		variable string
+		bounds   SolveOptions
+		kind     error
+	}{
+		{"x = ", "x", SolveOptions{Lower: 0, Upper: 1}, ErrSyntax},
+		{"= 1", "x", SolveOptions{Lower: 0, Upper: 1}, ErrSyntax},
+		{"x = 1 = 2", "x", SolveOptions{Lower: 0, Upper: 1}, ErrSyntax},
+		{"x = 1", "pi", SolveOptions{Lower: 0, Upper: 1}, ErrInvalidName},
+		{"x*y = 1", "x", SolveOptions{Lower: 0, Upper: 1}, ErrUnknownVariable},
+		{"x = 1", "x", SolveOptions{Lower: 1, Upper: 0}, ErrDomain},
+		{"x = 1", "x", SolveOptions{Lower: 0, Upper: math.Inf(1)}, ErrDomain},
+		{"x = 1", "x", SolveOptions{Lower: 0, Upper: 1, Tolerance: -1}, ErrDomain},
+		{"x = 1", "x", SolveOptions{Lower: 0, Upper: 1, MaxIterations: 10001}, ErrDomain},
+		{"x = 1", "x", SolveOptions{Lower: 0, Upper: 1, Samples: 100001}, ErrDomain},
+		{"ln(x) = 1", "x", SolveOptions{Lower: -2, Upper: -1}, ErrDomain},
+	}
+	p := New()
+	for _, tc := range cases {
+		if _, err := p.Solve(tc.equation, tc.variable, tc.bounds, EvalOptions{}); !errors.Is(err, tc.kind) {
+			t.Errorf("%s for %s: error %v, want %v", tc.equation, tc.variable, err, tc.kind)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package calculator
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSpecialFunctions(t *testing.T) {
+	const eulerGamma = 0.5772156649015329
+	cases := []struct {
+		expr string
+		want float64
+		tol  float64
+	}{
+		{"gamma(5)", 24, 0},
+		{"gamma(0.5)", math.Sqrt(math.Pi), 1e-15},
+		{"gamma(-0.5)", -2 * math.Sqrt(math.Pi), 1e-14},
+		{"lgamma(100)", 359.1342053695754, 1e-12},
+		{"lgamma(-0.5)", math.Log(2 * math.Sqrt(math.Pi)), 1e-14},
+		{"beta(2, 3)", 1.0 / 12, 1e-15},
+		{"beta(0.5, 0.5)", math.Pi, 1e-14},
+		{"ln(beta(500, 500))", -694.988722485713, 1e-9},
+		{"erf(0)", 0, 0},
+		{"erf(1)", 0.8427007929497149, 1e-15},
+		{"erfc(1) + erf(1)", 1, 1e-15},
+		{"erf(erfinv(0.3))", 0.3, 1e-15},
+		{"erfinv(-0.5)", -0.4769362762044699, 1e-15},
+		{"j0(0)", 1, 0},
+		{"j1(0)", 0, 0},
+		{"jn(2, 1)", 0.11490348493190049, 1e-15},
+		{"jn(0, 2.5)", math.J0(2.5), 0},
+		{"y0(1)", 0.08825696421567697, 1e-15},
+		{"yn(2, 1)", -1.6506826068162546, 1e-14},
+		{"digamma(1)", -eulerGamma, 1e-14},
+		{"digamma(0.5)", -eulerGamma - 2*math.Ln2, 1e-14},
+		{"digamma(-0.5)", 2 - eulerGamma - 2*math.Ln2, 1e-13},
+		{"digamma(100)", 4.600161852738087, 1e-13},
+		{"zeta(2)", math.Pi * math.Pi / 6, 1e-14},
+		{"zeta(4)", math.Pow(math.Pi, 4) / 90, 1e-14},
+		{"zeta(0)", -0.5, 0},
+		{"zeta(-1)", -1.0 / 12, 1e-15},
+		{"zeta(-2)", 0, 0},
+		{"zeta(0.5)", -1.4603545088095868, 1e-13},
+		{"zeta(100)", 1, 0},
+	}
+	p := New()
+	for _, tc := range cases {
+		got, err := p.Evaluate(tc.expr, EvalOptions{})
+		if err != nil {
+			t.Errorf("%s: %v", tc.expr, err)
+			continue
+		}
+		if math.Abs(got-tc.want) > tc.tol {
+			t.Errorf("%s = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestSpecialFunctionErrors(t *testing.T) {
+	cases := []struct {
+		expr string
+		kind error
+	}{
+		{"gamma(0)", ErrDomain},
+		{"gamma(-3)", ErrDomain},
+		{"gamma(172)", ErrOverflow},
+		{"lgamma(-2)", ErrDomain},
+		{"beta(-1, 2)", ErrDomain},
+		{"beta(2, 0)", ErrDomain},
+		{"erfinv(1)", ErrDomain},
+		{"erfinv(-1.5)", ErrDomain},
+		{"jn(1.5, 1)", ErrDomain},
+		{"yn(0.5, 1)", ErrDomain},
+		{"y0(0)", ErrDomain},
+		{"y1(-1)", ErrDomain},
+		{"digamma(0)", ErrDomain},
+		{"digamma(-4)", ErrDomain},
+		{"zeta(1)", ErrDomain},
+		{"zeta(-300)", nil},
+		{"zeta(-301)", ErrOverflow},
+		{"beta(2)", ErrArgumentCount},
+	}
+	p := New()
+	for _, tc := range cases {
+		if _, err := p.Evaluate(tc.expr, EvalOptions{}); !errors.Is(err, tc.kind) {
+			t.Errorf("%s: error %v, want %v", tc.expr, err, tc.kind)
+		}
+	}
+}
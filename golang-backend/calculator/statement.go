@@ -0,0 +1,201 @@
+package calculator
+
+import "strings"
+
+// StatementKind distinguishes the forms of input ParseStatement accepts
+type StatementKind int
+
+const (
+	// StatementExpression is a plain expression to evaluate
+	StatementExpression StatementKind = iota
+	// StatementAssignment binds a variable: r = 5
+	StatementAssignment
+	// StatementDefinition defines a function: area(r) = pi*r^2
+	StatementDefinition
+)
+
+// Statement is a line of calculator input
+type Statement struct {
+	Kind   StatementKind
+	Name   string   // variable or function being defined
+	Params []string // parameters of a function definition
+	Body   Node     // the expression, or the right-hand side of '='
+	Source string   // text of Body
+}
+
+// UserFunction is a function defined by a StatementDefinition. Its body
+// sees only its own parameters and the identifiers of the scope it is
+// called in, never the parameters of its caller.
+type UserFunction struct {
+	Name   string
+	Params []string
+	Body   Node
+	Source string
+}
+
+// Signature returns the function's name and parameters, such as area(r)
+func (fn *UserFunction) Signature() string {
+	return fn.Name + "(" + strings.Join(fn.Params, ", ") + ")"
+}
+
+// ParseStatement parses an expression, a variable assignment or a
+// function definition
+func ParseStatement(input string) (*Statement, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	assign := -1
+	for i, tok := range tokens {
+		if tok.kind == tokAssign {
+			assign = i
+			break
+		}
+	}
+	if assign < 0 {
+		tree, err := Parse(input)
+		if err != nil {
+			return nil, err
+		}
+		return &Statement{Kind: StatementExpression, Body: tree, Source: input}, nil
+	}
+
+	stmt, err := parseLeftSide(tokens[:assign], tokens[assign])
+	if err != nil {
+		return nil, err
+	}
+
+	// The right-hand side keeps offsets into the whole input
+	sp := &syntaxParser{tokens: tokens[assign+1:]}
+	if sp.peek().kind == tokEOF {
+		return nil, sp.unexpected(sp.peek())
+	}
+	if stmt.Body, err = sp.parseBinary(precOr); err != nil {
+		return nil, err
+	}
+	if tok := sp.peek(); tok.kind != tokEOF {
+		return nil, sp.unexpected(tok)
+	}
+	stmt.Source = strings.TrimSpace(string([]rune(input)[tokens[assign].end:]))
+	return stmt, nil
+}
+
+// parseLeftSide parses the target of an assignment: a name, or a function
+// name with a parenthesised list of distinct parameter names
+func parseLeftSide(lhs []token, assign token) (*Statement, error) {
+	invalid := func() error {
+		span := Span{assign.pos, assign.end}
+		if len(lhs) > 0 {
+			span = Span{lhs[0].pos, lhs[len(lhs)-1].end}
+		}
+		return errorAt(ErrSyntax, span, "left side of '=' must be a name or a function signature")
+	}
+
+	if len(lhs) == 0 || lhs[0].kind != tokIdent {
+		return nil, invalid()
+	}
+	if len(lhs) == 1 {
+		return &Statement{Kind: StatementAssignment, Name: lhs[0].text}, nil
+	}
+	if lhs[1].kind != tokLParen || lhs[len(lhs)-1].kind != tokRParen {
+		return nil, invalid()
+	}
+
+	stmt := &Statement{Kind: StatementDefinition, Name: lhs[0].text, Params: []string{}}
+	seen := make(map[string]bool)
+	inner := lhs[2 : len(lhs)-1]
+	for i, tok := range inner {
+		if i%2 == 1 {
+			if tok.kind != tokComma {
+				return nil, invalid()
+			}
+			continue
+		}
+		if tok.kind != tokIdent {
+			return nil, invalid()
+		}
+		if seen[tok.text] {
+			return nil, errorAt(ErrInvalidName, Span{tok.pos, tok.end}, "duplicate parameter '%s'", tok.text)
+		}
+		seen[tok.text] = true
+		stmt.Params = append(stmt.Params, tok.text)
+	}
+	if len(inner)%2 == 0 && len(inner) > 0 {
+		return nil, invalid() // trailing comma
+	}
+	return stmt, nil
+}
+
+// CompileStatement returns a program evaluating the statement's body
+func (p *ExpressionParser) CompileStatement(stmt *Statement) *Program {
+	return &Program{source: stmt.Source, tree: stmt.Body, parser: p}
+}
+
+// CheckName rejects names that cannot be assigned or defined because
+// expressions already give them a meaning
+func (p *ExpressionParser) CheckName(name string) error {
+	if _, ok := p.constants[name]; ok || name == "i" {
+		return newErrorf(ErrInvalidName, "'%s' is a built-in constant", name)
+	}
+	if _, ok := p.functions[name]; ok {
+		return newErrorf(ErrInvalidName, "'%s' is a built-in function", name)
+	}
+	if _, ok := matrixFunctions[name]; ok {
+		return newErrorf(ErrInvalidName, "'%s' is a built-in matrix function", name)
+	}
+	return nil
+}
+
+// DefineFunction builds a UserFunction from a definition statement. It
+// rejects reserved names and any definition that would call itself, directly
+// or through the functions already defined in scope; without conditionals
+// such recursion could never terminate.
+func (p *ExpressionParser) DefineFunction(stmt *Statement, scope Scope) (*UserFunction, error) {
+	if stmt.Kind != StatementDefinition {
+		return nil, newError(ErrInvalidName, "statement is not a function definition")
+	}
+	if err := p.CheckName(stmt.Name); err != nil {
+		return nil, err
+	}
+	for _, param := range stmt.Params {
+		if err := p.CheckName(param); err != nil {
+			return nil, err
+		}
+	}
+
+	fn := &UserFunction{Name: stmt.Name, Params: stmt.Params, Body: stmt.Body, Source: stmt.Source}
+	if path := callCycle(fn, scope); path != nil {
+		return nil, newErrorf(ErrRecursion, "recursive definition: %s", strings.Join(path, " → "))
+	}
+	return fn, nil
+}
+
+// callCycle returns the chain of calls by which fn would reach itself, or
+// nil when it cannot. Other functions are resolved through scope.
+func callCycle(fn *UserFunction, scope Scope) []string {
+	visited := make(map[string]bool)
+	var visit func(body Node, path []string) []string
+	visit = func(body Node, path []string) []string {
+		var cycle []string
+		Walk(body, func(n Node) {
+			call, ok := n.(*CallNode)
+			if !ok || cycle != nil {
+				return
+			}
+			if call.Name == fn.Name {
+				cycle = append(append([]string(nil), path...), call.Name)
+				return
+			}
+			if visited[call.Name] || scope == nil {
+				return
+			}
+			visited[call.Name] = true
+			if callee, ok := scope.Function(call.Name); ok {
+				cycle = visit(callee.Body, append(path, call.Name))
+			}
+		})
+		return cycle
+	}
+	return visit(fn.Body, []string{fn.Name})
+}
@@ -0,0 +1,228 @@
+package calculator
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testScope is a Scope backed by maps
+type testScope struct {
+	vars  map[string]float64
+	funcs map[string]*UserFunction
+}
+
+func (s *testScope) Lookup(name string) (float64, bool) {
+	v, ok := s.vars[name]
+	return v, ok
+}
+
+func (s *testScope) Function(name string) (*UserFunction, bool) {
+	fn, ok := s.funcs[name]
+	return fn, ok
+}
+
+// define parses and defines a function, adding it to the scope
+func (s *testScope) define(t *testing.T, p *ExpressionParser, input string) {
+	t.Helper()
+	stmt, err := ParseStatement(input)
+	if err != nil {
+		t.Fatalf("%s: %v", input, err)
+	}
+	fn, err := p.DefineFunction(stmt, s)
+	if err != nil {
+		t.Fatalf("%s: %v", input, err)
+	}
+	s.funcs[fn.Name] = fn
+}
+
+func newTestScope() *testScope {
+	return &testScope{vars: map[string]float64{}, funcs: map[string]*UserFunction{}}
+}
+
+func TestParseStatement(t *testing.T) {
+	cases := []struct {
+		input  string
+		kind   StatementKind
+		name   string
+		params []string
+		source string
+	}{
+		{"1 + 2", StatementExpression, "", nil, "1 + 2"},
+		{"r = 5", StatementAssignment, "r", nil, "5"},
+		{"area(r) = pi*r^2", StatementDefinition, "area", []string{"r"}, "pi*r^2"},
+		{"f(x, y) = x + y", StatementDefinition, "f", []string{"x", "y"}, "x + y"},
+		{"k() = 42", StatementDefinition, "k", []string{}, "42"},
+	}
+	for _, tc := range cases {
+		stmt, err := ParseStatement(tc.input)
+		if err != nil {
+			t.Errorf("%s: %v", tc.input, err)
+			continue
+		}
+		if stmt.Kind != tc.kind || stmt.Name != tc.name || stmt.Source != tc.source {
+			t.Errorf("%s: got kind %d name %q source %q", tc.input, stmt.Kind, stmt.Name, stmt.Source)
+		}
+		if strings.Join(stmt.Params, ",") != strings.Join(tc.params, ",") {
+			t.Errorf("%s: params %v, want %v", tc.input, stmt.Params, tc.params)
+		}
+	}
+}
+
+func TestParseStatementErrors(t *testing.T) {
+	cases := []struct {
+		input string
+		kind  error
+	}{
+		{"= 5", ErrSyntax},
+		{"x =", ErrSyntax},
+		{"2 = x", ErrSyntax},
+		{"f(x,) = x", ErrSyntax},
+		{"f(1) = 2", ErrSyntax},
+		{"f(x = x", ErrSyntax},
+		{"f(x, x) = x", ErrInvalidName},
+	}
+	for _, tc := range cases {
+		if _, err := ParseStatement(tc.input); !errors.Is(err, tc.kind) {
+			t.Errorf("%s: error %v, want %v", tc.input, err, tc.kind)
+		}
+	}
+}
+
+func TestDefineFunction(t *testing.T) {
+	p := New()
+	scope := newTestScope()
+	scope.vars["k"] = 10
+	scope.define(t, p, "sq(x) = x^2")
+	scope.define(t, p, "hyp(a, b) = sqrt(sq(a) + sq(b))")
+	scope.define(t, p, "shift(x) = x + k")
+
+	cases := []struct {
+		expr string
+		want float64
+	}{
+		{"sq(7)", 49},
+		{"hyp(3, 4)", 5},
+		{"shift(1)", 11},
+		{"sq(shift(2))", 144},
+	}
+	for _, tc := range cases {
+		got, err := p.Evaluate(tc.expr, EvalOptions{Scope: scope})
+		if err != nil {
+			t.Errorf("%s: %v", tc.expr, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+
+	// A function body does not see its caller's parameters
+	scope.define(t, p, "leak() = x")
+	if _, err := p.Evaluate("sq(leak())", EvalOptions{Scope: scope}); !errors.Is(err, ErrUnknownVariable) {
+		t.Errorf("leak(): error %v, want %v", err, ErrUnknownVariable)
+	}
+	if _, err := p.Evaluate("hyp(1)", EvalOptions{Scope: scope}); !errors.Is(err, ErrArgumentCount) {
+		t.Errorf("hyp(1): error %v, want %v", err, ErrArgumentCount)
+	}
+}
+
+func TestDefineFunctionErrors(t *testing.T) {
+	p := New()
+	scope := newTestScope()
+	scope.define(t, p, "g(x) = h(x) + 1")
+	scope.define(t, p, "h(x) = 2*x")
+
+	cases := []struct {
+		input string
+		kind  error
+		path  string
+	}{
+		{"f(x) = f(x - 1)", ErrRecursion, "f → f"},
+		{"f(x) = 1 + sin(f(x))", ErrRecursion, "f → f"},
+		{"h(x) = g(x)", ErrRecursion, "h → g → h"},
+		{"sin(x) = x", ErrInvalidName, ""},
+		{"f(pi) = pi", ErrInvalidName, ""},
+		{"e() = 1", ErrInvalidName, ""},
+		{"det(x) = x", ErrInvalidName, ""},
+		{"f(inv) = inv", ErrInvalidName, ""},
+	}
+	for _, tc := range cases {
+		stmt, err := ParseStatement(tc.input)
+		if err != nil {
+			t.Errorf("%s: %v", tc.input, err)
+			continue
+		}
+		_, err = p.DefineFunction(stmt, scope)
+		if !errors.Is(err, tc.kind) {
+			t.Errorf("%s: error %v, want %v", tc.input, err, tc.kind)
+			continue
+		}
+		if tc.path != "" && !strings.Contains(err.Error(), tc.path) {
+			t.Errorf("%s: error %q does not name the cycle %s", tc.input, err, tc.path)
+		}
+	}
+
+	stmt, _ := ParseStatement("x = 1")
+	if _, err := p.DefineFunction(stmt, scope); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("assignment: error %v, want %v", err, ErrInvalidName)
+	}
+}
+
+func TestCallDepth(t *testing.T) {
+	p := New()
+	scope := newTestScope()
+	scope.define(t, p, "f0(x) = x + 1")
+	for i := 1; i <= maxCallDepth; i++ {
+		scope.define(t, p, "f"+strconv.Itoa(i)+"(x) = f"+strconv.Itoa(i-1)+"(x)")
+	}
+
+	cases := []struct {
+		expr string
+		err  error
+	}{
+		{"f" + strconv.Itoa(maxCallDepth-1) + "(1)", nil},
+		{"f" + strconv.Itoa(maxCallDepth) + "(1)", ErrRecursion},
+	}
+	for _, tc := range cases {
+		got, err := p.Evaluate(tc.expr, EvalOptions{Scope: scope})
+		if !errors.Is(err, tc.err) || (tc.err == nil && got != 2) {
+			t.Errorf("%s = %v, %v; want error %v", tc.expr, got, err, tc.err)
+		}
+	}
+
+	// A cycle that bypassed DefineFunction is still cut off
+	stmt, _ := ParseStatement("loop(x) = loop(x)")
+	scope.funcs["loop"] = &UserFunction{Name: "loop", Params: stmt.Params, Body: stmt.Body, Source: stmt.Source}
+	if _, err := p.Evaluate("loop(1)", EvalOptions{Scope: scope}); !errors.Is(err, ErrRecursion) {
+		t.Errorf("loop(1): error %v, want %v", err, ErrRecursion)
+	}
+}
+
+func TestCallBudget(t *testing.T) {
+	p := New()
+	scope := newTestScope()
+	scope.define(t, p, "f0(x) = x + 1")
+	for i := 1; i <= 24; i++ {
+		prev := "f" + strconv.Itoa(i-1)
+		scope.define(t, p, "f"+strconv.Itoa(i)+"(x) = "+prev+"(x) + "+prev+"(x)")
+	}
+
+	cases := []struct {
+		expr string
+		want float64
+		err  error
+	}{
+		{"f15(1)", 65536, nil},
+		{"f16(1)", 0, ErrRecursion},
+		{"f24(1)", 0, ErrRecursion},
+		{"f15(1) + f15(1)", 0, ErrRecursion},
+	}
+	for _, tc := range cases {
+		got, err := p.Evaluate(tc.expr, EvalOptions{Scope: scope})
+		if !errors.Is(err, tc.err) || got != tc.want {
+			t.Errorf("%s = %v, %v; want %v, error %v", tc.expr, got, err, tc.want, tc.err)
+		}
+	}
+}
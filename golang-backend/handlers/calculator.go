@@ -2,7 +2,15 @@ package handlers
 
 import (
 	"calculator-backend/calculator"
+	"calculator-backend/linalg"
 	"calculator-backend/models"
+	"calculator-backend/session"
+	"calculator-backend/storage"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/big"
 	"net/http"
 	"strconv"
 
@@ -11,17 +19,29 @@ import (
 
 // CalculatorHandler handles calculator-related HTTP requests
 type CalculatorHandler struct {
-	basic      *calculator.BasicOperations
-	scientific *calculator.ScientificOperations
-	parser     *calculator.ExpressionParser
+	basic        *calculator.BasicOperations
+	scientific   *calculator.ScientificOperations
+	numberTheory *calculator.NumberTheoryOperations
+	parser       *calculator.ExpressionParser
+	history      storage.HistoryStore
+	sessions     *session.Store
 }
 
-// NewCalculatorHandler creates a new CalculatorHandler
-func NewCalculatorHandler() *CalculatorHandler {
+// NewCalculatorHandler creates a new CalculatorHandler that evaluates
+// expressions with engine, records them in history and resolves session
+// state from sessions. A nil engine uses the built-in functions; a nil
+// history or sessions disables that feature.
+func NewCalculatorHandler(engine *calculator.ExpressionParser, history storage.HistoryStore, sessions *session.Store) *CalculatorHandler {
+	if engine == nil {
+		engine = calculator.New()
+	}
 	return &CalculatorHandler{
-		basic:      calculator.NewBasicOperations(),
-		scientific: calculator.NewScientificOperations(),
-		parser:     calculator.NewExpressionParser(),
+		basic:        calculator.NewBasicOperations(),
+		scientific:   calculator.NewScientificOperations(),
+		numberTheory: calculator.NewNumberTheoryOperations(),
+		parser:       engine,
+		history:      history,
+		sessions:     sessions,
 	}
 }
 
@@ -37,29 +57,397 @@ func (h *CalculatorHandler) EvaluateExpression(c *gin.Context) {
 		return
 	}
 
-	// Set angle mode if specified
-	if req.Mode == "radian" {
-		h.parser.SetMode(false)
-	} else {
-		h.parser.SetMode(true) // Default to degree
+	// Options travel with the call so concurrent requests never share mode
+	opts := calculator.EvalOptions{
+		AngleMode: angleMode(req.Mode),
+		Digits:    req.Digits,
+		Variables: req.Variables,
+		Precision: req.Precision,
+	}
+
+	// A session supplies ans, memory registers and variables, and its
+	// preferences apply unless the request overrides them
+	sessionID := c.GetHeader(SessionHeader)
+	if sessionID == "" {
+		sessionID = req.SessionID
+	}
+	if sessionID != "" {
+		if h.sessions == nil {
+			sessionError(c, session.ErrNotFound)
+			return
+		}
+		s, err := h.sessions.Get(sessionID)
+		if err != nil {
+			sessionError(c, err)
+			return
+		}
+		opts.Scope = s
+		if req.Mode == "" {
+			opts.AngleMode = s.AngleMode
+		}
+		if req.Digits == 0 {
+			opts.Digits = s.Digits
+		}
+	}
+
+	if req.Base != 0 && (req.Base < 2 || req.Base > 36) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid base",
+			Code:    400,
+			Message: "Base must be between 2 and 36",
+		})
+		return
 	}
 
-	// Evaluate the expression
-	result, err := h.parser.Evaluate(req.Expression)
+	stmt, err := calculator.ParseStatement(req.Expression)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.CalculationResponse{
-			Original: req.Expression,
-			Success:  false,
-			Error:    err.Error(),
+		h.record(req.Expression, models.CalculationResponse{}, err)
+		expressionError(c, req.Expression, err)
+		return
+	}
+	if stmt.Kind != calculator.StatementExpression {
+		h.define(c, req, stmt, sessionID, opts)
+		return
+	}
+
+	var resp models.CalculationResponse
+	switch req.Number {
+	case "", "float":
+		if req.Precision > 0 {
+			resp, err = h.evaluateBig(req, opts)
+		} else {
+			resp, err = h.evaluateFloat(req, opts)
+		}
+	case "rational":
+		resp, err = h.evaluateRational(req, opts)
+	case "complex":
+		resp, err = h.evaluateComplex(req, opts)
+	case "int":
+		if opts.Int, err = intType(req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid integer type",
+				Code:    400,
+				Message: err.Error(),
+			})
+			return
+		}
+		resp, err = h.evaluateInt(req, opts)
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Unsupported number mode",
+			Code:    400,
+			Message: "Supported number modes: float, rational, complex, int",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.CalculationResponse{
+	h.record(req.Expression, resp, err)
+	if err != nil {
+		expressionError(c, req.Expression, err)
+		return
+	}
+	if req.Base != 0 {
+		if resp.Complex != nil && resp.Complex.Imag != 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Unsupported base",
+				Code:    400,
+				Message: "Complex results cannot be written in another base",
+			})
+			return
+		}
+		if resp.Matrix != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Unsupported base",
+				Code:    400,
+				Message: "Matrix results cannot be written in another base",
+			})
+			return
+		}
+		if err := renderBase(req, opts.Int, &resp); err != nil {
+			expressionError(c, req.Expression, err)
+			return
+		}
+	}
+	if sessionID != "" {
+		h.storeAnswer(sessionID, resp)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// define stores a variable assignment or function definition in the
+// session. An assignment's value becomes the session's ans.
+func (h *CalculatorHandler) define(c *gin.Context, req models.CalculationRequest, stmt *calculator.Statement, sessionID string, opts calculator.EvalOptions) {
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Session required",
+			Code:    400,
+			Message: "Assignments and function definitions are stored in a session; create one with POST /api/sessions",
+		})
+		return
+	}
+	// Session variables hold float64 values, so the other evaluators have
+	// nowhere to put their results
+	if stmt.Kind == calculator.StatementAssignment && ((req.Number != "" && req.Number != "float") || req.Precision > 0) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Unsupported number mode",
+			Code:    400,
+			Message: "Assignments are evaluated in float mode without extra precision",
+		})
+		return
+	}
+
+	resp := models.CalculationResponse{Original: req.Expression, Success: true}
+	var err error
+	if stmt.Kind == calculator.StatementAssignment {
+		resp.Definition = stmt.Name
+		if err = h.parser.CheckName(stmt.Name); err == nil {
+			resp.Result, err = h.parser.CompileStatement(stmt).Run(opts)
+		}
+		if err == nil {
+			err = h.sessions.Update(sessionID, func(s *session.Session) error {
+				if err := s.Assign(stmt.Name, resp.Result); err != nil {
+					return err
+				}
+				s.Ans, s.HasAns = resp.Result, true
+				return nil
+			})
+		}
+	} else {
+		err = h.sessions.Update(sessionID, func(s *session.Session) error {
+			// Checked under the session lock so concurrent definitions
+			// cannot form a cycle between them
+			fn, err := h.parser.DefineFunction(stmt, s)
+			if err != nil {
+				return err
+			}
+			resp.Definition = fn.Signature()
+			return s.Define(fn)
+		})
+	}
+
+	h.record(req.Expression, resp, err)
+	var calcErr *calculator.Error
+	switch {
+	case errors.As(err, &calcErr):
+		expressionError(c, req.Expression, err)
+	case err != nil:
+		sessionError(c, err)
+	default:
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// storeAnswer makes a real result the session's ans. Complex results with
+// a non-zero imaginary part and matrices leave ans unchanged.
+func (h *CalculatorHandler) storeAnswer(sessionID string, resp models.CalculationResponse) {
+	if (resp.Complex != nil && resp.Complex.Imag != 0) || resp.Matrix != nil {
+		return
+	}
+	// The session may have expired since it was read; the result stands
+	_ = h.sessions.Update(sessionID, func(s *session.Session) error {
+		s.Ans = resp.Result
+		s.HasAns = true
+		return nil
+	})
+}
+
+// record adds an evaluation to history. Failing to record never fails
+// the calculation itself.
+func (h *CalculatorHandler) record(expression string, resp models.CalculationResponse, err error) {
+	if h.history == nil {
+		return
+	}
+
+	entry := models.HistoryResponse{Expression: expression, Result: resp.Result}
+	switch {
+	case err != nil:
+		entry.Error = err.Error()
+	case resp.Definition != "":
+		entry.Display = resp.Definition
+	case resp.Decimal != "":
+		entry.Display = resp.Decimal
+	case resp.Rational != nil:
+		entry.Display = resp.Rational.Fraction
+	case resp.Complex != nil:
+		entry.Display = resp.Complex.Text
+	case resp.Integer != nil:
+		entry.Display = resp.Integer.Decimal
+	case resp.Matrix != nil:
+		entry.Display = resp.Matrix.Text
+	}
+
+	if _, err := h.history.Add(entry); err != nil {
+		log.Printf("Failed to record history: %v", err)
+	}
+}
+
+// evaluateFloat evaluates an expression with float64 arithmetic.
+// Expressions with matrix literals or matrix functions are evaluated on
+// matrices.
+func (h *CalculatorHandler) evaluateFloat(req models.CalculationRequest, opts calculator.EvalOptions) (models.CalculationResponse, error) {
+	prog, err := h.parser.Compile(req.Expression)
+	if err != nil {
+		return models.CalculationResponse{}, err
+	}
+	if prog.IsMatrix() {
+		return evaluateMatrix(prog, req, opts)
+	}
+	result, err := prog.Run(opts)
+	if err != nil {
+		return models.CalculationResponse{}, err
+	}
+
+	return models.CalculationResponse{
 		Result:   result,
 		Original: req.Expression,
 		Success:  true,
-	})
+	}, nil
+}
+
+// evaluateMatrix evaluates an expression whose values may be matrices.
+// A matrix result is carried in matrix; a number in result.
+func evaluateMatrix(prog *calculator.Program, req models.CalculationRequest, opts calculator.EvalOptions) (models.CalculationResponse, error) {
+	value, err := prog.RunMatrix(opts)
+	if err != nil {
+		return models.CalculationResponse{}, err
+	}
+
+	resp := models.CalculationResponse{Original: req.Expression, Success: true}
+	if value.IsMatrix() {
+		resp.Matrix = matrixResult(value.Matrix)
+	} else {
+		resp.Result = value.Scalar
+	}
+	return resp, nil
+}
+
+// evaluateBig evaluates an expression with arbitrary-precision arithmetic.
+// The decimal field carries every digit; result is the nearest float64,
+// or 0 when the value is outside float64 range.
+func (h *CalculatorHandler) evaluateBig(req models.CalculationRequest, opts calculator.EvalOptions) (models.CalculationResponse, error) {
+	value, err := h.parser.EvaluateBig(req.Expression, opts)
+	if err != nil {
+		return models.CalculationResponse{}, err
+	}
+
+	result, _ := value.Float64()
+	if math.IsInf(result, 0) {
+		result = 0
+	}
+
+	return models.CalculationResponse{
+		Result:   result,
+		Decimal:  calculator.FormatBig(value, req.Digits),
+		Original: req.Expression,
+		Success:  true,
+	}, nil
+}
+
+// evaluateRational evaluates an expression with exact rational arithmetic,
+// reporting in fallback when an irrational function forced floating point.
+// Result is the nearest float64, or 0 when the value is outside its range.
+func (h *CalculatorHandler) evaluateRational(req models.CalculationRequest, opts calculator.EvalOptions) (models.CalculationResponse, error) {
+	value, err := h.parser.EvaluateRational(req.Expression, opts)
+	if err != nil {
+		return models.CalculationResponse{}, err
+	}
+
+	result := value.Float
+	if math.IsInf(result, 0) {
+		result = 0
+	}
+
+	resp := models.CalculationResponse{
+		Result:   result,
+		Fallback: value.Fallback,
+		Original: req.Expression,
+		Success:  true,
+	}
+	if value.Exact() {
+		resp.Rational = &models.RationalResult{
+			Numerator:   value.Value.Num().String(),
+			Denominator: value.Value.Denom().String(),
+			Fraction:    value.Value.RatString(),
+			Mixed:       calculator.MixedNumber(value.Value),
+		}
+	}
+	return resp, nil
+}
+
+// evaluateComplex evaluates an expression over the complex numbers.
+// Result carries the real part; complex carries both parts.
+func (h *CalculatorHandler) evaluateComplex(req models.CalculationRequest, opts calculator.EvalOptions) (models.CalculationResponse, error) {
+	value, err := h.parser.EvaluateComplex(req.Expression, opts)
+	if err != nil {
+		return models.CalculationResponse{}, err
+	}
+
+	return models.CalculationResponse{
+		Result: real(value.Value),
+		Complex: &models.ComplexResult{
+			Real:     real(value.Value),
+			Imag:     imag(value.Value),
+			Modulus:  value.Modulus,
+			Argument: value.Argument,
+			Text:     calculator.FormatComplex(value.Value),
+		},
+		Original: req.Expression,
+		Success:  true,
+	}, nil
+}
+
+// intType reads the integer type of int mode from the request
+func intType(req models.CalculationRequest) (calculator.IntType, error) {
+	typ := calculator.IntType{Bits: req.Width}
+	if !typ.Valid() {
+		return typ, fmt.Errorf("width must be 8, 16, 32 or 64, got %d", req.Width)
+	}
+	if req.Signed != nil {
+		typ.Unsigned = !*req.Signed
+	}
+	switch req.Overflow {
+	case "", "wrap":
+		typ.Wrap = true
+	case "error":
+	default:
+		return typ, fmt.Errorf("overflow must be \"wrap\" or \"error\", got %q", req.Overflow)
+	}
+	return typ, nil
+}
+
+// evaluateInt evaluates an expression on fixed-width integers. Result is
+// the nearest float64; integer carries the exact value in each base.
+func (h *CalculatorHandler) evaluateInt(req models.CalculationRequest, opts calculator.EvalOptions) (models.CalculationResponse, error) {
+	value, err := h.parser.EvaluateInt(req.Expression, opts)
+	if err != nil {
+		return models.CalculationResponse{}, err
+	}
+
+	result, _ := new(big.Float).SetInt(value.Value).Float64()
+	return models.CalculationResponse{
+		Result: result,
+		Integer: &models.IntegerResult{
+			Type:    value.Type.String(),
+			Decimal: value.Format(10),
+			Hex:     value.Format(16),
+			Octal:   value.Format(8),
+			Binary:  value.Format(2),
+		},
+		Original: req.Expression,
+		Success:  true,
+	}, nil
+}
+
+// angleMode maps the request mode string to an angle mode. An empty mode
+// leaves the choice to the engine, which defaults to degrees.
+func angleMode(mode string) calculator.AngleMode {
+	switch mode {
+	case "radian":
+		return calculator.AngleRadian
+	case "":
+		return calculator.AngleDefault
+	}
+	return calculator.AngleDegree
 }
 
 // BasicOperation handles basic arithmetic operations
@@ -100,11 +488,7 @@ func (h *CalculatorHandler) BasicOperation(c *gin.Context) {
 	}
 
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.CalculationResponse{
-			Original: req.Operator,
-			Success:  false,
-			Error:    err.Error(),
-		})
+		expressionError(c, req.Operator, err)
 		return
 	}
 
@@ -127,50 +511,12 @@ func (h *CalculatorHandler) ScientificOperation(c *gin.Context) {
 		return
 	}
 
-	isDegree := req.Mode != "radian"
-	var result float64
-	var err error
-
-	switch req.Function {
-	case "sin":
-		result = h.scientific.Sin(req.Value, isDegree)
-	case "cos":
-		result = h.scientific.Cos(req.Value, isDegree)
-	case "tan":
-		result, err = h.scientific.Tan(req.Value, isDegree)
-	case "asin":
-		result, err = h.scientific.Asin(req.Value, isDegree)
-	case "acos":
-		result, err = h.scientific.Acos(req.Value, isDegree)
-	case "atan":
-		result = h.scientific.Atan(req.Value, isDegree)
-	case "log":
-		result, err = h.scientific.Log(req.Value)
-	case "ln":
-		result, err = h.scientific.Ln(req.Value)
-	case "exp":
-		result, err = h.scientific.Exp(req.Value)
-	case "sqrt":
-		result, err = h.basic.SquareRoot(req.Value)
-	case "cbrt":
-		result = h.basic.CubeRoot(req.Value)
-	case "abs":
-		result = h.basic.Absolute(req.Value)
-	case "factorial":
-		result, err = h.basic.Factorial(req.Value)
-	case "floor":
-		result = h.scientific.Floor(req.Value)
-	case "ceil":
-		result = h.scientific.Ceil(req.Value)
-	case "round":
-		result = h.scientific.Round(req.Value)
-	case "sinh":
-		result, err = h.scientific.Sinh(req.Value)
-	case "cosh":
-		result, err = h.scientific.Cosh(req.Value)
-	case "tanh":
-		result = h.scientific.Tanh(req.Value)
-	default:
+	// Functions come from the engine's table, so every function an
+	// expression can call is available here as well
+	opts := calculator.EvalOptions{AngleMode: angleMode(req.Mode)}
+	args := append([]float64{req.Value}, req.Args...)
+	result, err := h.parser.Call(req.Function, args, opts)
+	if errors.Is(err, calculator.ErrUnknownFunction) {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{
 			Error:   "Unsupported function",
 			Code:    400,
@@ -178,13 +524,8 @@ func (h *CalculatorHandler) ScientificOperation(c *gin.Context) {
 		})
 		return
 	}
-
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.CalculationResponse{
-			Original: req.Function,
-			Success:  false,
-			Error:    err.Error(),
-		})
+		expressionError(c, req.Function, err)
 		return
 	}
 
@@ -195,15 +536,11 @@ func (h *CalculatorHandler) ScientificOperation(c *gin.Context) {
 	})
 }
 
-// GetConstants returns mathematical constants
+// GetConstants returns the constants expressions can use, including any
+// registered with the engine
 func (h *CalculatorHandler) GetConstants(c *gin.Context) {
-	constants := gin.H{
-		"pi": h.scientific.GetPi(),
-		"e":  h.scientific.GetE(),
-	}
-
 	c.JSON(http.StatusOK, gin.H{
-		"constants": constants,
+		"constants": h.parser.Constants(),
 		"success":   true,
 	})
 }
@@ -264,4 +601,50 @@ func (h *CalculatorHandler) ConvertAngle(c *gin.Context) {
 		"to":       toMode,
 		"success":  true,
 	})
-}
\ No newline at end of file
+}
+
+// calculationError reports a failed evaluation with the error's stable
+// code, the span of the expression that caused it when known, and the
+// condition number of a singular or ill-conditioned matrix
+func calculationError(c *gin.Context, err error) {
+	code, span, condition := errorDetails(err)
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error:     "Calculation failed",
+		Code:      400,
+		Message:   err.Error(),
+		ErrorCode: code,
+		Span:      span,
+		Condition: condition,
+	})
+}
+
+// expressionError reports a failed calculation in the CalculationResponse
+// shape that /api/calculate, /api/basic and /api/scientific have always
+// returned, with success false and the message in error, adding the same
+// details as calculationError
+func expressionError(c *gin.Context, original string, err error) {
+	code, span, condition := errorDetails(err)
+	c.JSON(http.StatusBadRequest, models.CalculationResponse{
+		Original:  original,
+		Success:   false,
+		Error:     err.Error(),
+		ErrorCode: code,
+		Span:      span,
+		Condition: condition,
+	})
+}
+
+// errorDetails returns the stable code of err, the span of the expression
+// that caused it when known, and the condition number of a singular or
+// ill-conditioned matrix
+func errorDetails(err error) (code string, span *models.Span, condition *float64) {
+	var calcErr *calculator.Error
+	if errors.As(err, &calcErr) && calcErr.Located() {
+		span = &models.Span{Start: calcErr.Span.Start, End: calcErr.Span.End}
+	}
+	var condErr *linalg.ConditionError
+	if errors.As(err, &condErr) && !math.IsInf(condErr.Condition, 0) {
+		condition = &condErr.Condition
+	}
+	return calculator.ErrorCode(err), span, condition
+}
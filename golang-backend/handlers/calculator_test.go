@@ -0,0 +1,480 @@
+package handlers
+
+import (
+	"bytes"
+	"calculator-backend/calculator"
+	"calculator-backend/models"
+	"calculator-backend/session"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestRouter() *gin.Engine {
+	return newHandlerRouter(NewCalculatorHandler(nil, nil, nil))
+}
+
+// newHandlerRouter serves every endpoint of handler at the path main
+// gives it
+func newHandlerRouter(handler *CalculatorHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api := router.Group("/api")
+	api.POST("/calculate", handler.EvaluateExpression)
+	api.POST("/basic", handler.BasicOperation)
+	api.POST("/scientific", handler.ScientificOperation)
+	api.POST("/number-theory", handler.NumberTheory)
+	api.POST("/derivative", handler.Derivative)
+	api.POST("/integrate", handler.Integrate)
+	api.POST("/solve", handler.Solve)
+	api.POST("/polynomial/arithmetic", handler.PolynomialArithmetic)
+	api.POST("/polynomial/evaluate", handler.PolynomialEvaluate)
+	api.POST("/polynomial/roots", handler.PolynomialRoots)
+	api.POST("/polynomial/factor", handler.PolynomialFactor)
+	api.POST("/matrix", handler.Matrix)
+	api.GET("/constants", handler.GetConstants)
+	api.GET("/functions", handler.ListFunctions)
+	api.GET("/convert-angle", handler.ConvertAngle)
+	api.POST("/convert-base", handler.ConvertBase)
+	return router
+}
+
+func postJSON(router http.Handler, path string, body interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestEvaluateExpressionConcurrentModes fires degree and radian requests in
+// parallel; run with -race to check the handler shares no mutable state.
+func TestEvaluateExpressionConcurrentModes(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		mode string
+		want float64
+	}{
+		{"degree", 1},
+		{"radian", math.Sin(90)},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		tc := cases[i%len(cases)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := postJSON(router, "/api/calculate", models.CalculationRequest{
+				Expression: "sin(90)",
+				Mode:       tc.mode,
+			})
+			if rec.Code != http.StatusOK {
+				t.Errorf("mode %s: status %d: %s", tc.mode, rec.Code, rec.Body.String())
+				return
+			}
+
+			var resp models.CalculationResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Errorf("mode %s: decode response: %v", tc.mode, err)
+				return
+			}
+			if math.Abs(resp.Result-tc.want) > 1e-12 {
+				t.Errorf("mode %s: sin(90) = %v, want %v", tc.mode, resp.Result, tc.want)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestAssignmentNumberModes checks that assignments, which store float64
+// session variables, refuse the other evaluators
+func TestAssignmentNumberModes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sessions := session.NewStore(session.DefaultTTL, 0)
+	s, err := sessions.Create(calculator.AngleDefault, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newHandlerRouter(NewCalculatorHandler(nil, nil, sessions))
+
+	cases := []struct {
+		number    string
+		precision uint
+		want      int
+	}{
+		{"", 0, http.StatusOK},
+		{"float", 0, http.StatusOK},
+		{"float", 128, http.StatusBadRequest},
+		{"rational", 0, http.StatusBadRequest},
+		{"complex", 0, http.StatusBadRequest},
+		{"int", 0, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/calculate", models.CalculationRequest{
+			Expression: "x = 1/3",
+			Number:     tc.number,
+			Precision:  tc.precision,
+			SessionID:  s.ID,
+		})
+		if rec.Code != tc.want {
+			t.Errorf("number %q precision %d: status %d, want %d: %s", tc.number, tc.precision, rec.Code, tc.want, rec.Body.String())
+		}
+	}
+}
+
+func TestEvaluateExpressionLiterals(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		expression string
+		want       float64
+	}{
+		{"1.5e-3 * 1000", 1.5},
+		{"6.02E23", 6.02e23},
+		{".5 + .25", 0.75},
+		{"1_000_000", 1e6},
+		{"0xff + 0b1 + 0o7", 263},
+		{"exp(0) + ceil(0.5) + sec(0)", 3},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/calculate", models.CalculationRequest{Expression: tc.expression})
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status %d: %s", tc.expression, rec.Code, rec.Body.String())
+			continue
+		}
+		var resp models.CalculationResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(resp.Result-tc.want) > 1e-12*math.Abs(tc.want) {
+			t.Errorf("%s = %v, want %v", tc.expression, resp.Result, tc.want)
+		}
+	}
+}
+
+func TestEvaluateExpressionVariables(t *testing.T) {
+	router := newTestRouter()
+
+	rec := postJSON(router, "/api/calculate", models.CalculationRequest{
+		Expression: "a*x^2 + b",
+		Variables:  map[string]float64{"a": 2, "x": 3, "b": 1},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp models.CalculationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Result != 19 {
+		t.Errorf("result = %v, want 19", resp.Result)
+	}
+
+	rec = postJSON(router, "/api/calculate", models.CalculationRequest{
+		Expression: "a*x^2 + b",
+		Variables:  map[string]float64{"a": 2, "x": 3},
+	})
+	var errResp models.CalculationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusBadRequest || errResp.ErrorCode != "unknown_variable" {
+		t.Errorf("unbound b: status %d, code %q", rec.Code, errResp.ErrorCode)
+	}
+}
+
+func TestEvaluateExpressionCustomFunctions(t *testing.T) {
+	reg := calculator.NewRegistry()
+	triple := func(args []float64, opts calculator.EvalOptions) (float64, error) {
+		return 3 * args[0], nil
+	}
+	if err := reg.RegisterFunction("triple", calculator.Arity{Min: 1, Max: 1}, triple, calculator.FunctionInfo{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.RegisterConstant("dozen", 12, "Twelve"); err != nil {
+		t.Fatal(err)
+	}
+
+	router := newHandlerRouter(NewCalculatorHandler(calculator.New(calculator.WithFunctions(reg)), nil, nil))
+
+	cases := []struct {
+		expr string
+		want float64
+	}{
+		{"triple(dozen)", 36},
+		{"triple(2) + sqrt(16)", 10},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/calculate", models.CalculationRequest{Expression: tc.expr})
+		var resp models.CalculationResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusOK || resp.Result != tc.want {
+			t.Errorf("%s: status %d: %s, want %v", tc.expr, rec.Code, rec.Body.String(), tc.want)
+		}
+	}
+
+	rec := postJSON(router, "/api/calculate", models.CalculationRequest{Expression: "triple(1, 2)"})
+	var errResp models.CalculationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil || errResp.ErrorCode != "argument_count" {
+		t.Errorf("triple(1, 2): status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = request(router, http.MethodGet, "/api/constants")
+	var constants struct {
+		Constants map[string]float64 `json:"constants"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &constants); err != nil || constants.Constants["dozen"] != 12 || constants.Constants["pi"] != math.Pi {
+		t.Errorf("constants: status %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestEvaluateExpressionPrecision(t *testing.T) {
+	router := newTestRouter()
+
+	rec := postJSON(router, "/api/calculate", models.CalculationRequest{
+		Expression: "2^100 + 1 - 2^100 + sqrt(2)",
+		Precision:  200,
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp models.CalculationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if want := "2.414213562373095048801688724209698078569671875376948"; !strings.HasPrefix(resp.Decimal, want) {
+		t.Errorf("decimal = %s, want %s…", resp.Decimal, want)
+	}
+
+	// Degree arguments are reduced exactly, leaving no rounding noise
+	for expression, want := range map[string]string{"sin(180)": "0", "cos(90)": "0", "tan(45)": "1"} {
+		rec := postJSON(router, "/api/calculate", models.CalculationRequest{Expression: expression, Precision: 100, Mode: "degree"})
+		var resp models.CalculationResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusOK || resp.Decimal != want {
+			t.Errorf("%s at 100 bits: status %d: %s, want %s", expression, rec.Code, rec.Body.String(), want)
+		}
+	}
+}
+
+func TestEvaluateExpressionRational(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		expression string
+		rational   *models.RationalResult
+		fallback   bool
+	}{
+		{"1/3 + 1/6", &models.RationalResult{Numerator: "1", Denominator: "2", Fraction: "1/2", Mixed: "1/2"}, false},
+		{"-7/2", &models.RationalResult{Numerator: "-7", Denominator: "2", Fraction: "-7/2", Mixed: "-3 1/2"}, false},
+		{"sqrt(2) + 1/3", nil, true},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/calculate", models.CalculationRequest{Expression: tc.expression, Number: "rational"})
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status %d: %s", tc.expression, rec.Code, rec.Body.String())
+			continue
+		}
+		var resp models.CalculationResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case tc.rational == nil && resp.Rational != nil:
+			t.Errorf("%s: rational %+v, want none", tc.expression, resp.Rational)
+		case tc.rational != nil && (resp.Rational == nil || *resp.Rational != *tc.rational):
+			t.Errorf("%s: rational %+v, want %+v", tc.expression, resp.Rational, tc.rational)
+		}
+		if (resp.Fallback != "") != tc.fallback {
+			t.Errorf("%s: fallback %q", tc.expression, resp.Fallback)
+		}
+	}
+}
+
+func TestEvaluateExpressionComplex(t *testing.T) {
+	router := newTestRouter()
+
+	rec := postJSON(router, "/api/calculate", models.CalculationRequest{Expression: "sqrt(-4) + 1", Number: "complex"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp models.CalculationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Complex == nil || resp.Complex.Real != 1 || resp.Complex.Imag != 2 || resp.Complex.Text != "1 + 2i" {
+		t.Errorf("complex = %+v, want 1 + 2i", resp.Complex)
+	}
+	if resp.Result != 1 {
+		t.Errorf("result = %v, want the real part 1", resp.Result)
+	}
+}
+
+func TestEvaluateExpressionErrorSpan(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		expression string
+		code       string
+		span       models.Span
+	}{
+		{"2 + sqrt(-1)", "domain_error", models.Span{Start: 4, End: 12}},
+		{"1 + foo(2)", "unknown_function", models.Span{Start: 4, End: 10}},
+		{"π ÷ 0", "division_by_zero", models.Span{Start: 0, End: 5}},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/calculate", models.CalculationRequest{Expression: tc.expression})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d", tc.expression, rec.Code)
+			continue
+		}
+		var resp models.CalculationResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.ErrorCode != tc.code || resp.Span == nil || *resp.Span != tc.span {
+			t.Errorf("%s: code %q span %v, want %q %v", tc.expression, resp.ErrorCode, resp.Span, tc.code, tc.span)
+		}
+		// Failures keep the success and error fields clients already read
+		if resp.Success || resp.Error == "" || resp.Original != tc.expression {
+			t.Errorf("%s: %s", tc.expression, rec.Body.String())
+		}
+	}
+}
+
+func TestScientificOperation(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		req  models.ScientificOperationRequest
+		want float64
+	}{
+		{models.ScientificOperationRequest{Function: "sec", Value: 60}, 2},
+		{models.ScientificOperationRequest{Function: "cot", Value: math.Pi / 4, Mode: "radian"}, 1},
+		{models.ScientificOperationRequest{Function: "acsc", Value: 2}, 30},
+		{models.ScientificOperationRequest{Function: "asinh", Value: math.Sinh(1.5)}, 1.5},
+		{models.ScientificOperationRequest{Function: "cbrt", Value: 64}, 4},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/scientific", tc.req)
+		var resp models.CalculationResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusOK || math.Abs(resp.Result-tc.want) > 1e-12 {
+			t.Errorf("%s(%v): status %d: %s, want %v", tc.req.Function, tc.req.Value, rec.Code, rec.Body.String(), tc.want)
+		}
+	}
+
+	errorCases := []struct {
+		req  models.ScientificOperationRequest
+		code string
+	}{
+		{models.ScientificOperationRequest{Function: "acosh", Value: 0.5}, "domain_error"},
+		{models.ScientificOperationRequest{Function: "sec", Value: 90}, "domain_error"},
+		{models.ScientificOperationRequest{Function: "sinh", Value: 1000}, "overflow"},
+		{models.ScientificOperationRequest{Function: "nosuch", Value: 1}, ""},
+	}
+	for _, tc := range errorCases {
+		rec := postJSON(router, "/api/scientific", tc.req)
+		var resp models.CalculationResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusBadRequest || resp.ErrorCode != tc.code {
+			t.Errorf("%s(%v): status %d: %s, want %q", tc.req.Function, tc.req.Value, rec.Code, rec.Body.String(), tc.code)
+		}
+	}
+}
+
+func TestScientificOperationSpecialFunctions(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		req    models.ScientificOperationRequest
+		status int
+		want   float64
+		code   string
+	}{
+		{models.ScientificOperationRequest{Function: "gamma", Value: 5}, http.StatusOK, 24, ""},
+		{models.ScientificOperationRequest{Function: "beta", Value: 2, Args: []float64{3}}, http.StatusOK, 1.0 / 12, ""},
+		{models.ScientificOperationRequest{Function: "zeta", Value: 2}, http.StatusOK, math.Pi * math.Pi / 6, ""},
+		{models.ScientificOperationRequest{Function: "gamma", Value: -2}, http.StatusBadRequest, 0, "domain_error"},
+		{models.ScientificOperationRequest{Function: "beta", Value: 2}, http.StatusBadRequest, 0, "argument_count"},
+		{models.ScientificOperationRequest{Function: "jn", Value: 1.5, Args: []float64{1}}, http.StatusBadRequest, 0, "domain_error"},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/scientific", tc.req)
+		if rec.Code != tc.status {
+			t.Errorf("%s(%v, %v): status %d: %s", tc.req.Function, tc.req.Value, tc.req.Args, rec.Code, rec.Body.String())
+			continue
+		}
+		if tc.status == http.StatusOK {
+			var resp models.CalculationResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || math.Abs(resp.Result-tc.want) > 1e-14 {
+				t.Errorf("%s: %s, want %v", tc.req.Function, rec.Body.String(), tc.want)
+			}
+			continue
+		}
+		var resp models.CalculationResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.ErrorCode != tc.code {
+			t.Errorf("%s: %s, want %s", tc.req.Function, rec.Body.String(), tc.code)
+		}
+	}
+}
+
+func TestEvaluateExpressionInt(t *testing.T) {
+	router := newTestRouter()
+	unsigned := false
+
+	cases := []struct {
+		req  models.CalculationRequest
+		want models.IntegerResult
+	}{
+		{
+			models.CalculationRequest{Expression: "0xF0 | 0x0F", Number: "int", Width: 16},
+			models.IntegerResult{Type: "int16", Decimal: "255", Hex: "0xFF", Octal: "0o377", Binary: "0b11111111"},
+		},
+		{
+			models.CalculationRequest{Expression: "127 + 1", Number: "int", Width: 8},
+			models.IntegerResult{Type: "int8", Decimal: "-128", Hex: "0x80", Octal: "0o200", Binary: "0b10000000"},
+		},
+		{
+			models.CalculationRequest{Expression: "~0", Number: "int", Width: 8, Signed: &unsigned},
+			models.IntegerResult{Type: "uint8", Decimal: "255", Hex: "0xFF", Octal: "0o377", Binary: "0b11111111"},
+		},
+		{
+			models.CalculationRequest{Expression: "5 xor 3", Number: "int"},
+			models.IntegerResult{Type: "int64", Decimal: "6", Hex: "0x6", Octal: "0o6", Binary: "0b110"},
+		},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/calculate", tc.req)
+		var resp models.CalculationResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusOK || resp.Integer == nil {
+			t.Errorf("%s: status %d: %s", tc.req.Expression, rec.Code, rec.Body.String())
+			continue
+		}
+		if *resp.Integer != tc.want {
+			t.Errorf("%s: integer = %+v, want %+v", tc.req.Expression, *resp.Integer, tc.want)
+		}
+	}
+
+	errorCases := []struct {
+		req  models.CalculationRequest
+		code string
+	}{
+		{models.CalculationRequest{Expression: "127 + 1", Number: "int", Width: 8, Overflow: "error"}, "overflow"},
+		{models.CalculationRequest{Expression: "1", Number: "int", Width: 12}, ""},
+		{models.CalculationRequest{Expression: "1", Number: "int", Overflow: "saturate"}, ""},
+		{models.CalculationRequest{Expression: "6 & 3"}, "unsupported"},
+	}
+	for _, tc := range errorCases {
+		rec := postJSON(router, "/api/calculate", tc.req)
+		var resp models.ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusBadRequest || resp.ErrorCode != tc.code {
+			t.Errorf("%s: status %d: %s, want %q", tc.req.Expression, rec.Code, rec.Body.String(), tc.code)
+		}
+	}
+}
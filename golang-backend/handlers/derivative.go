@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"calculator-backend/calculator"
+	"calculator-backend/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Derivative differentiates an expression symbolically and, when given a
+// point, evaluates the derivative there
+func (h *CalculatorHandler) Derivative(c *gin.Context) {
+	var req models.DerivativeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request format",
+			Code:    400,
+			Message: err.Error(),
+		})
+		return
+	}
+	if req.Variable == "" {
+		req.Variable = "x"
+	}
+
+	opts := calculator.EvalOptions{AngleMode: angleMode(req.Mode)}
+	prog, err := h.parser.Derivative(req.Expression, req.Variable, opts)
+	if err != nil {
+		calculationError(c, err)
+		return
+	}
+
+	resp := models.DerivativeResponse{
+		Original:   req.Expression,
+		Variable:   req.Variable,
+		Derivative: prog.Source(),
+		Success:    true,
+	}
+	if req.At != nil {
+		vars := make(map[string]float64, len(req.Variables)+1)
+		for name, value := range req.Variables {
+			vars[name] = value
+		}
+		vars[req.Variable] = *req.At
+		opts.Variables = vars
+
+		value, err := prog.Run(opts)
+		if err != nil {
+			calculationError(c, err)
+			return
+		}
+		resp.Value = &value
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"calculator-backend/models"
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+)
+
+func TestDerivativeEndpoint(t *testing.T) {
+	router := newTestRouter()
+	at := func(v float64) *float64 { return &v }
+
+	cases := []struct {
+		req        models.DerivativeRequest
+		derivative string
+		value      *float64
+	}{
+		{models.DerivativeRequest{Expression: "sin(x)^2", Mode: "radian"}, "2*sin(x)*cos(x)", nil},
+		{models.DerivativeRequest{Expression: "sin(x)^2"}, "sin(x)*pi*cos(x)/90", nil},
+		{models.DerivativeRequest{Expression: "x^3", At: at(2)}, "3*x^2", at(12)},
+		{models.DerivativeRequest{Expression: "t*k", Variable: "t", At: at(5), Variables: map[string]float64{"k": 4}}, "k", at(4)},
+		{models.DerivativeRequest{Expression: "sin(x)", Mode: "degree", At: at(60)}, "pi*cos(x)/180", at(math.Pi / 360)},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/derivative", tc.req)
+		var resp models.DerivativeResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusOK {
+			t.Errorf("%s: status %d: %s", tc.req.Expression, rec.Code, rec.Body.String())
+			continue
+		}
+		if resp.Derivative != tc.derivative {
+			t.Errorf("%s: derivative %s, want %s", tc.req.Expression, resp.Derivative, tc.derivative)
+		}
+		switch {
+		case tc.value == nil && resp.Value != nil:
+			t.Errorf("%s: unexpected value %v", tc.req.Expression, *resp.Value)
+		case tc.value != nil && (resp.Value == nil || math.Abs(*resp.Value-*tc.value) > 1e-12):
+			t.Errorf("%s: value %v, want %v", tc.req.Expression, resp.Value, *tc.value)
+		}
+	}
+
+	errorCases := []struct {
+		req  models.DerivativeRequest
+		code string
+	}{
+		{models.DerivativeRequest{Expression: "max(x, 1)"}, "unsupported"},
+		{models.DerivativeRequest{Expression: "foo(x)"}, "unknown_function"},
+		{models.DerivativeRequest{Expression: "x +"}, "syntax_error"},
+		{models.DerivativeRequest{Expression: "x*y", At: at(1)}, "unknown_variable"},
+		{models.DerivativeRequest{Expression: "ln(x)", At: at(0)}, "division_by_zero"},
+	}
+	for _, tc := range errorCases {
+		rec := postJSON(router, "/api/derivative", tc.req)
+		var resp models.ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusBadRequest || resp.ErrorCode != tc.code {
+			t.Errorf("%s: status %d: %s, want %q", tc.req.Expression, rec.Code, rec.Body.String(), tc.code)
+		}
+	}
+}
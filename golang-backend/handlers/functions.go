@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"calculator-backend/calculator"
+	"calculator-backend/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// catalogLanguages are the languages function descriptions are written in,
+// matching the frontend's content files
+var catalogLanguages = map[string]bool{"en": true, "id": true}
+
+// ListFunctions returns the operators and functions the engine supports,
+// described in the language given by the lang query parameter
+func (h *CalculatorHandler) ListFunctions(c *gin.Context) {
+	lang := c.DefaultQuery("lang", "en")
+	if !catalogLanguages[lang] {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid language",
+			Code:    400,
+			Message: "lang must be 'en' or 'id'",
+		})
+		return
+	}
+
+	resp := models.CatalogResponse{
+		Language:  lang,
+		Operators: []models.CatalogEntry{},
+		Functions: []models.CatalogEntry{},
+	}
+	for _, entry := range h.parser.Catalog() {
+		converted := catalogEntry(entry, lang)
+		if entry.Kind == "operator" {
+			resp.Operators = append(resp.Operators, converted)
+		} else {
+			resp.Functions = append(resp.Functions, converted)
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// catalogEntry converts a catalog entry to its API form
+func catalogEntry(entry calculator.CatalogEntry, lang string) models.CatalogEntry {
+	examples := entry.Info.Examples
+	if examples == nil {
+		examples = []string{}
+	}
+	return models.CatalogEntry{
+		Name:        entry.Name,
+		Notation:    entry.Notation,
+		Category:    entry.Info.Category,
+		MinArgs:     entry.Arity.Min,
+		MaxArgs:     entry.Arity.Max,
+		Domain:      entry.Info.Domain,
+		Angle:       entry.Info.Angle.String(),
+		Description: entry.Info.DescriptionIn(lang),
+		Examples:    examples,
+		Modes:       entry.Modes,
+	}
+}
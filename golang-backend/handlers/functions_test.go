@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"calculator-backend/models"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestListFunctions(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		path        string
+		lang        string
+		description string
+	}{
+		{"/api/functions", "en", "Sine of an angle"},
+		{"/api/functions?lang=en", "en", "Sine of an angle"},
+		{"/api/functions?lang=id", "id", ""},
+	}
+	for _, tc := range cases {
+		rec := request(router, http.MethodGet, tc.path)
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status %d: %s", tc.path, rec.Code, rec.Body.String())
+			continue
+		}
+		var resp models.CatalogResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Language != tc.lang || len(resp.Operators) == 0 || len(resp.Functions) == 0 {
+			t.Errorf("%s: language %q, %d operators, %d functions", tc.path, resp.Language, len(resp.Operators), len(resp.Functions))
+		}
+
+		var sin *models.CatalogEntry
+		for i := range resp.Functions {
+			if resp.Functions[i].Name == "sin" {
+				sin = &resp.Functions[i]
+			}
+		}
+		if sin == nil {
+			t.Errorf("%s: sin is missing", tc.path)
+			continue
+		}
+		if sin.Angle != "argument" || sin.MinArgs != 1 || sin.MaxArgs != 1 || len(sin.Examples) == 0 {
+			t.Errorf("%s: sin = %+v", tc.path, *sin)
+		}
+		if tc.description != "" && sin.Description != tc.description {
+			t.Errorf("%s: sin description %q, want %q", tc.path, sin.Description, tc.description)
+		}
+		if tc.lang == "id" && (sin.Description == "" || sin.Description == "Sine of an angle") {
+			t.Errorf("%s: sin description %q is not Indonesian", tc.path, sin.Description)
+		}
+	}
+
+	if rec := request(router, http.MethodGet, "/api/functions?lang=fr"); rec.Code != http.StatusBadRequest {
+		t.Errorf("lang=fr: status %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"calculator-backend/models"
+	"calculator-backend/storage"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Page sizes for history listings
+const (
+	defaultHistoryPageSize = 50
+	maxHistoryPageSize     = 500
+)
+
+// HistoryHandler handles calculation history HTTP requests
+type HistoryHandler struct {
+	store storage.HistoryStore
+}
+
+// NewHistoryHandler creates a new HistoryHandler
+func NewHistoryHandler(store storage.HistoryStore) *HistoryHandler {
+	return &HistoryHandler{store: store}
+}
+
+// ListHistory returns a page of history, newest first. Query parameters:
+// q (expression substring), status (success or error), since and until
+// (RFC 3339 times), offset and limit.
+func (h *HistoryHandler) ListHistory(c *gin.Context) {
+	filter, err := historyFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid query parameter",
+			Code:    400,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	entries, total, err := h.store.List(filter)
+	if err != nil {
+		historyStoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.HistoryListResponse{
+		Entries: entries,
+		Total:   total,
+		Offset:  filter.Offset,
+		Limit:   filter.Limit,
+	})
+}
+
+// historyFilter builds a store filter from the request's query parameters
+func historyFilter(c *gin.Context) (storage.HistoryFilter, error) {
+	filter := storage.HistoryFilter{
+		Query: c.Query("q"),
+		Limit: defaultHistoryPageSize,
+	}
+
+	var err error
+	if value := c.Query("offset"); value != "" {
+		if filter.Offset, err = strconv.Atoi(value); err != nil || filter.Offset < 0 {
+			return filter, errors.New("offset must be a non-negative integer")
+		}
+	}
+	if value := c.Query("limit"); value != "" {
+		if filter.Limit, err = strconv.Atoi(value); err != nil || filter.Limit < 1 || filter.Limit > maxHistoryPageSize {
+			return filter, errors.New("limit must be an integer between 1 and " + strconv.Itoa(maxHistoryPageSize))
+		}
+	}
+	if value := c.Query("since"); value != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, value); err != nil {
+			return filter, errors.New("since must be an RFC 3339 time")
+		}
+	}
+	if value := c.Query("until"); value != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, value); err != nil {
+			return filter, errors.New("until must be an RFC 3339 time")
+		}
+	}
+
+	switch c.Query("status") {
+	case "":
+	case "success":
+		failed := false
+		filter.Failed = &failed
+	case "error":
+		failed := true
+		filter.Failed = &failed
+	default:
+		return filter, errors.New("status must be success or error")
+	}
+	return filter, nil
+}
+
+// GetHistoryEntry returns a single history entry
+func (h *HistoryHandler) GetHistoryEntry(c *gin.Context) {
+	id, ok := historyID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.store.Get(id)
+	if err != nil {
+		historyStoreError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// DeleteHistoryEntry removes a single history entry
+func (h *HistoryHandler) DeleteHistoryEntry(c *gin.Context) {
+	id, ok := historyID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.store.Delete(id); err != nil {
+		historyStoreError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ClearHistory removes every history entry
+func (h *HistoryHandler) ClearHistory(c *gin.Context) {
+	if err := h.store.Clear(); err != nil {
+		historyStoreError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// historyID parses the :id path parameter, responding with an error when
+// it is not a valid ID
+func historyID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid history ID",
+			Code:    400,
+			Message: "History ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// historyStoreError reports a failed store operation
+func historyStoreError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrHistoryNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "History entry not found",
+			Code:    404,
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		Error:   "History storage failed",
+		Code:    500,
+		Message: err.Error(),
+	})
+}
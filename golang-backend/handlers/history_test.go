@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"calculator-backend/models"
+	"calculator-backend/storage"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newHistoryRouter() *gin.Engine {
+	store := storage.NewMemoryHistoryStore(0)
+	router := newHandlerRouter(NewCalculatorHandler(nil, store, nil))
+	history := NewHistoryHandler(store)
+	router.GET("/api/history", history.ListHistory)
+	router.GET("/api/history/:id", history.GetHistoryEntry)
+	router.DELETE("/api/history/:id", history.DeleteHistoryEntry)
+	return router
+}
+
+func request(router http.Handler, method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestHistory(t *testing.T) {
+	router := newHistoryRouter()
+	for _, expr := range []string{"1 + 1", "1/0", "sqrt(16)"} {
+		postJSON(router, "/api/calculate", models.CalculationRequest{Expression: expr})
+	}
+
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"sqrt(16)", "1/0", "1 + 1"}},
+		{"?status=error", []string{"1/0"}},
+		{"?status=success&limit=1", []string{"sqrt(16)"}},
+		{"?q=SQRT", []string{"sqrt(16)"}},
+		{"?offset=2", []string{"1 + 1"}},
+	}
+	for _, tc := range cases {
+		rec := request(router, http.MethodGet, "/api/history"+tc.query)
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status %d: %s", tc.query, rec.Code, rec.Body.String())
+			continue
+		}
+		var resp models.HistoryListResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		got := make([]string, len(resp.Entries))
+		for i, entry := range resp.Entries {
+			got[i] = entry.Expression
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: %v, want %v", tc.query, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: %v, want %v", tc.query, got, tc.want)
+				break
+			}
+		}
+	}
+
+	for _, query := range []string{"?limit=0", "?offset=-1", "?since=yesterday", "?status=maybe"} {
+		if rec := request(router, http.MethodGet, "/api/history"+query); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", query, rec.Code)
+		}
+	}
+
+	var entry models.HistoryResponse
+	rec := request(router, http.MethodGet, "/api/history/3")
+	if err := json.Unmarshal(rec.Body.Bytes(), &entry); err != nil || entry.Result != 4 {
+		t.Errorf("entry 3 = %s", rec.Body.String())
+	}
+	if rec := request(router, http.MethodDelete, "/api/history/3"); rec.Code/100 != 2 {
+		t.Errorf("delete: status %d", rec.Code)
+	}
+	if rec := request(router, http.MethodGet, "/api/history/3"); rec.Code != http.StatusNotFound {
+		t.Errorf("deleted entry: status %d, want 404", rec.Code)
+	}
+	if rec := request(router, http.MethodGet, "/api/history/abc"); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad ID: status %d, want 400", rec.Code)
+	}
+}
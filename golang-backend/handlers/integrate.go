@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"calculator-backend/calculator"
+	"calculator-backend/models"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Integrate evaluates a definite integral of an expression numerically.
+// Anything the calculator evaluates can be integrated, over a finite or
+// infinite range.
+func (h *CalculatorHandler) Integrate(c *gin.Context) {
+	var req models.IntegralRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request format",
+			Code:    400,
+			Message: err.Error(),
+		})
+		return
+	}
+	if req.Variable == "" {
+		req.Variable = "x"
+	}
+
+	opts := calculator.EvalOptions{AngleMode: angleMode(req.Mode), Variables: req.Variables}
+	lower, err := h.integrationBound(req.Lower, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:     "Invalid lower bound",
+			Code:      400,
+			Message:   err.Error(),
+			ErrorCode: calculator.ErrorCode(err),
+		})
+		return
+	}
+	upper, err := h.integrationBound(req.Upper, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:     "Invalid upper bound",
+			Code:      400,
+			Message:   err.Error(),
+			ErrorCode: calculator.ErrorCode(err),
+		})
+		return
+	}
+
+	bounds := calculator.IntegralOptions{
+		Lower:          lower,
+		Upper:          upper,
+		Tolerance:      req.Tolerance,
+		MaxEvaluations: req.MaxEvaluations,
+	}
+	result, err := h.parser.Integrate(req.Expression, req.Variable, bounds, opts)
+	if err != nil {
+		calculationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.IntegralResponse{
+		Original:      req.Expression,
+		Variable:      req.Variable,
+		Lower:         req.Lower,
+		Upper:         req.Upper,
+		Result:        result.Value,
+		ErrorEstimate: result.ErrorEstimate,
+		Evaluations:   result.Evaluations,
+		Intervals:     result.Intervals,
+		Converged:     result.Converged,
+		Success:       true,
+	})
+}
+
+// integrationBound evaluates a bound of an integral, which may also be
+// written as inf, infinity or ∞ with an optional sign
+func (h *CalculatorHandler) integrationBound(text string, opts calculator.EvalOptions) (float64, error) {
+	trimmed := strings.TrimSpace(text)
+	sign := 1
+	if rest := strings.TrimPrefix(trimmed, "-"); rest != trimmed {
+		trimmed, sign = strings.TrimSpace(rest), -1
+	} else {
+		trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "+"))
+	}
+	switch strings.ToLower(trimmed) {
+	case "inf", "infinity", "∞":
+		return math.Inf(sign), nil
+	}
+	return h.parser.Evaluate(text, opts)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"calculator-backend/models"
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+)
+
+func TestIntegrate(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		req  models.IntegralRequest
+		want float64
+	}{
+		{models.IntegralRequest{Expression: "x^2", Lower: "0", Upper: "3"}, 9},
+		{models.IntegralRequest{Expression: "sin(x)", Lower: "0", Upper: "pi", Mode: "radian"}, 2},
+		{models.IntegralRequest{Expression: "sin(t)", Variable: "t", Lower: "0", Upper: "180"}, 360 / math.Pi},
+		{models.IntegralRequest{Expression: "exp(-x^2)", Lower: "-inf", Upper: "∞"}, math.Sqrt(math.Pi)},
+		{models.IntegralRequest{Expression: "exp(-a*x)", Lower: "0", Upper: "+infinity", Variables: map[string]float64{"a": 2}}, 0.5},
+		{models.IntegralRequest{Expression: "1", Lower: "sqrt(4)", Upper: "2^3"}, 6},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/integrate", tc.req)
+		var resp models.IntegralResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusOK {
+			t.Errorf("%s: status %d: %s", tc.req.Expression, rec.Code, rec.Body.String())
+			continue
+		}
+		if math.Abs(resp.Result-tc.want) > 1e-8 || !resp.Converged || resp.Evaluations == 0 || resp.Intervals == 0 {
+			t.Errorf("%s from %s to %s: %s, want %v", tc.req.Expression, tc.req.Lower, tc.req.Upper, rec.Body.String(), tc.want)
+		}
+	}
+}
+
+func TestIntegrateErrors(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		req   models.IntegralRequest
+		error string
+		code  string
+	}{
+		{models.IntegralRequest{Expression: "1/x", Lower: "-1", Upper: "1"}, "Calculation failed", "domain_error"},
+		{models.IntegralRequest{Expression: "x +", Lower: "0", Upper: "1"}, "Calculation failed", "syntax_error"},
+		{models.IntegralRequest{Expression: "x", Lower: "y", Upper: "1"}, "Invalid lower bound", "unknown_variable"},
+		{models.IntegralRequest{Expression: "x", Lower: "0", Upper: "1/0"}, "Invalid upper bound", "division_by_zero"},
+		{models.IntegralRequest{Expression: "x", Lower: "0", Upper: "1", MaxEvaluations: 5}, "Calculation failed", "domain_error"},
+		{models.IntegralRequest{Expression: "x", Lower: "0"}, "Invalid request format", ""},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/integrate", tc.req)
+		var resp models.ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusBadRequest || resp.Error != tc.error || resp.ErrorCode != tc.code {
+			t.Errorf("%s from %s to %s: status %d: %s", tc.req.Expression, tc.req.Lower, tc.req.Upper, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"calculator-backend/calculator"
+	"calculator-backend/linalg"
+	"calculator-backend/models"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Matrix applies an operation to one or two dense matrices: arithmetic,
+// transpose, determinant, inverse, rank, LU and QR decomposition, or the
+// solution of A·x = b. Singular and ill-conditioned matrices are reported
+// with their condition number.
+func (h *CalculatorHandler) Matrix(c *gin.Context) {
+	var req models.MatrixRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request format",
+			Code:    400,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var binary bool
+	switch req.Operation {
+	case "add", "subtract", "multiply", "solve":
+		binary = true
+	case "transpose", "determinant", "inverse", "rank", "lu", "qr":
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid operation",
+			Code:    400,
+			Message: "Supported operations: add, subtract, multiply, transpose, determinant, inverse, rank, lu, qr, solve",
+		})
+		return
+	}
+
+	a, ok := bindMatrix(c, "a", req.A)
+	if !ok {
+		return
+	}
+	var b *linalg.Matrix
+	if binary {
+		if req.B == nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Missing matrix",
+				Code:    400,
+				Message: fmt.Sprintf("Operation %s requires a second matrix in 'b'", req.Operation),
+			})
+			return
+		}
+		if b, ok = bindMatrix(c, "b", req.B); !ok {
+			return
+		}
+	}
+
+	resp := models.MatrixResponse{Operation: req.Operation, Success: true}
+	var (
+		result *linalg.Matrix
+		err    error
+	)
+	switch req.Operation {
+	case "add":
+		result, err = linalg.Add(a, b)
+	case "subtract":
+		result, err = linalg.Sub(a, b)
+	case "multiply":
+		result, err = linalg.Mul(a, b)
+	case "transpose":
+		result = a.Transpose()
+	case "determinant":
+		var det float64
+		if det, err = a.Det(); err == nil {
+			resp.Value = &det
+		}
+	case "rank":
+		rank := float64(a.Rank())
+		resp.Value = &rank
+	case "inverse", "solve":
+		if req.Operation == "inverse" {
+			result, err = a.Inverse()
+		} else {
+			result, err = linalg.Solve(a, b)
+		}
+		if err == nil {
+			cond, _ := a.Condition()
+			resp.Condition = &cond
+		}
+	case "lu":
+		var lu *linalg.LU
+		if lu, err = a.LU(); err == nil {
+			resp.L, resp.U, resp.P = matrixResult(lu.L()), matrixResult(lu.U()), matrixResult(lu.P())
+		}
+	case "qr":
+		qr := a.QR()
+		resp.Q, resp.R = matrixResult(qr.Q), matrixResult(qr.R)
+	}
+	if err != nil {
+		calculationError(c, err)
+		return
+	}
+	if result != nil {
+		resp.Result = matrixResult(result)
+	}
+	if !finiteMatrixResponse(resp) {
+		calculationError(c, calculator.NewError(calculator.ErrOverflow, "result out of range"))
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// finiteMatrixResponse reports whether every number in the response is
+// finite, as JSON requires
+func finiteMatrixResponse(resp models.MatrixResponse) bool {
+	if resp.Value != nil && (math.IsInf(*resp.Value, 0) || math.IsNaN(*resp.Value)) {
+		return false
+	}
+	for _, m := range []*models.MatrixResult{resp.Result, resp.L, resp.U, resp.P, resp.Q, resp.R} {
+		if m == nil {
+			continue
+		}
+		for _, row := range m.Entries {
+			for _, v := range row {
+				if math.IsInf(v, 0) || math.IsNaN(v) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// bindMatrix builds a matrix from the rows of a request field, writing an
+// error response and reporting false when they are empty or ragged
+func bindMatrix(c *gin.Context, field string, rows [][]float64) (*linalg.Matrix, bool) {
+	m, err := linalg.FromRows(rows)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:     "Invalid matrix",
+			Code:      400,
+			Message:   fmt.Sprintf("Matrix '%s': %v", field, err),
+			ErrorCode: calculator.ErrorCode(err),
+		})
+		return nil, false
+	}
+	return m, true
+}
+
+// matrixResult converts a matrix to its response form
+func matrixResult(m *linalg.Matrix) *models.MatrixResult {
+	return &models.MatrixResult{
+		Rows:    m.Rows(),
+		Cols:    m.Cols(),
+		Entries: m.ToRows(),
+		Text:    m.String(),
+	}
+}
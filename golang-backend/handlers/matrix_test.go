@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"calculator-backend/models"
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+)
+
+func TestMatrix(t *testing.T) {
+	router := newTestRouter()
+	a := [][]float64{{2, -1, 0}, {-1, 2, -1}, {0, -1, 2}}
+
+	cases := []struct {
+		name  string
+		req   models.MatrixRequest
+		check func(t *testing.T, resp models.MatrixResponse)
+	}{
+		{"determinant", models.MatrixRequest{Operation: "determinant", A: a}, func(t *testing.T, resp models.MatrixResponse) {
+			if resp.Value == nil || math.Abs(*resp.Value-4) > 1e-12 {
+				t.Errorf("value = %v, want 4", resp.Value)
+			}
+		}},
+		{"inverse", models.MatrixRequest{Operation: "inverse", A: a}, func(t *testing.T, resp models.MatrixResponse) {
+			if resp.Result == nil || math.Abs(resp.Result.Entries[1][1]-1) > 1e-12 {
+				t.Fatalf("result = %+v, want 1 in the centre", resp.Result)
+			}
+			if resp.Condition == nil || math.Abs(*resp.Condition-8) > 1e-12 {
+				t.Errorf("condition = %v, want 8", resp.Condition)
+			}
+		}},
+		{"multiply", models.MatrixRequest{Operation: "multiply", A: [][]float64{{1, 2}}, B: [][]float64{{3}, {4}}}, func(t *testing.T, resp models.MatrixResponse) {
+			if resp.Result == nil || resp.Result.Text != "[11]" {
+				t.Errorf("result = %+v, want [11]", resp.Result)
+			}
+		}},
+		{"rank", models.MatrixRequest{Operation: "rank", A: [][]float64{{1, 2}, {2, 4}}}, func(t *testing.T, resp models.MatrixResponse) {
+			if resp.Value == nil || *resp.Value != 1 {
+				t.Errorf("value = %v, want 1", resp.Value)
+			}
+		}},
+		{"lu", models.MatrixRequest{Operation: "lu", A: a}, func(t *testing.T, resp models.MatrixResponse) {
+			if resp.L == nil || resp.U == nil || resp.P == nil {
+				t.Errorf("missing factors: %+v", resp)
+			}
+		}},
+		{"qr", models.MatrixRequest{Operation: "qr", A: a}, func(t *testing.T, resp models.MatrixResponse) {
+			if resp.Q == nil || resp.R == nil || resp.R.Rows != 3 {
+				t.Errorf("missing factors: %+v", resp)
+			}
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := postJSON(router, "/api/matrix", tc.req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+			}
+			var resp models.MatrixResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			tc.check(t, resp)
+		})
+	}
+}
+
+func TestMatrixErrors(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		name      string
+		body      interface{}
+		errorCode string
+		condition bool
+	}{
+		{"ragged a", models.MatrixRequest{Operation: "determinant", A: [][]float64{{1, 2}, {3}}}, "dimension_mismatch", false},
+		{"ragged b", models.MatrixRequest{Operation: "add", A: [][]float64{{1}}, B: [][]float64{{1, 2}, {3}}}, "dimension_mismatch", false},
+		{"add without b", models.MatrixRequest{Operation: "add", A: [][]float64{{1}}}, "", false},
+		{"solve without b", models.MatrixRequest{Operation: "solve", A: [][]float64{{1}}}, "", false},
+		{"unknown operation", models.MatrixRequest{Operation: "eigen", A: [][]float64{{1}}}, "", false},
+		{"missing a", json.RawMessage(`{"operation": "rank"}`), "", false},
+		{"shape mismatch", models.MatrixRequest{Operation: "multiply", A: [][]float64{{1, 2}}, B: [][]float64{{1, 2}}}, "dimension_mismatch", false},
+		{"singular", models.MatrixRequest{Operation: "inverse", A: [][]float64{{1, 2}, {2, 4}}}, "singular_matrix", false},
+		{"ill-conditioned", models.MatrixRequest{Operation: "inverse", A: [][]float64{{1, 1}, {1, 1 + 1e-13}}}, "ill_conditioned", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := postJSON(router, "/api/matrix", tc.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+			}
+			var resp models.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.ErrorCode != tc.errorCode {
+				t.Errorf("error code %q, want %q", resp.ErrorCode, tc.errorCode)
+			}
+			if (resp.Condition != nil) != tc.condition {
+				t.Errorf("condition = %v", resp.Condition)
+			}
+		})
+	}
+}
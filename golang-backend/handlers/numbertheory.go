@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"calculator-backend/calculator"
+	"calculator-backend/models"
+	"errors"
+	"math/big"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NumberTheory applies an integer operation to exact arguments. Besides the
+// integer functions available in expressions it offers "factor", whose
+// result is a list of prime factors.
+func (h *CalculatorHandler) NumberTheory(c *gin.Context) {
+	var req models.NumberTheoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request format",
+			Code:    400,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	args := make([]*big.Int, len(req.Args))
+	for i, arg := range req.Args {
+		value, ok := new(big.Int).SetString(arg.String(), 10)
+		if !ok {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid argument",
+				Code:    400,
+				Message: "Arguments must be integers, got " + arg.String(),
+			})
+			return
+		}
+		args[i] = value
+	}
+
+	resp := models.NumberTheoryResponse{Operation: req.Operation, Success: true}
+	if req.Operation == "factor" {
+		if len(args) != 1 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid argument",
+				Code:    400,
+				Message: "factor expects 1 argument",
+			})
+			return
+		}
+		factors, err := h.numberTheory.Factor(args[0])
+		if err != nil {
+			calculationError(c, err)
+			return
+		}
+		resp.Factors = make([]models.PrimeFactor, len(factors))
+		for i, f := range factors {
+			resp.Factors[i] = models.PrimeFactor{Prime: f.Prime.String(), Exponent: f.Exponent}
+		}
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+
+	result, err := h.numberTheory.Call(req.Operation, args)
+	if errors.Is(err, calculator.ErrUnknownFunction) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Unsupported operation",
+			Code:    400,
+			Message: "Operation not supported: " + req.Operation,
+		})
+		return
+	}
+	if err != nil {
+		calculationError(c, err)
+		return
+	}
+	resp.Result = result.String()
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"calculator-backend/models"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestNumberTheory(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		body string
+		want string
+	}{
+		{`{"operation": "gcd", "args": [12, 18, 30]}`, "6"},
+		{`{"operation": "mod", "args": [-7, 3]}`, "2"},
+		{`{"operation": "rem", "args": [-7, 3]}`, "-1"},
+		{`{"operation": "isPrime", "args": ["18446744073709551557"]}`, "1"},
+		{`{"operation": "modpow", "args": ["123456789012345678901234567890", 65537, "1000000007"]}`, "921051386"},
+		{`{"operation": "fib", "args": [100]}`, "354224848179261915075"},
+		{`{"operation": "nCr", "args": [60, 30]}`, "118264581564861424"},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/number-theory", json.RawMessage(tc.body))
+		var resp models.NumberTheoryResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusOK || resp.Result != tc.want {
+			t.Errorf("%s: status %d: %s, want %s", tc.body, rec.Code, rec.Body.String(), tc.want)
+		}
+	}
+}
+
+func TestNumberTheoryFactor(t *testing.T) {
+	router := newTestRouter()
+
+	rec := postJSON(router, "/api/number-theory", json.RawMessage(`{"operation": "factor", "args": ["18446744073709551617"]}`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp models.NumberTheoryResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	want := []models.PrimeFactor{{Prime: "274177", Exponent: 1}, {Prime: "67280421310721", Exponent: 1}}
+	if len(resp.Factors) != len(want) || resp.Factors[0] != want[0] || resp.Factors[1] != want[1] {
+		t.Errorf("factors = %v, want %v", resp.Factors, want)
+	}
+
+	rec = postJSON(router, "/api/number-theory", json.RawMessage(`{"operation": "factor", "args": [720]}`))
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	want = []models.PrimeFactor{{Prime: "2", Exponent: 4}, {Prime: "3", Exponent: 2}, {Prime: "5", Exponent: 1}}
+	if len(resp.Factors) != len(want) || resp.Factors[0] != want[0] || resp.Factors[1] != want[1] || resp.Factors[2] != want[2] {
+		t.Errorf("factors = %v, want %v", resp.Factors, want)
+	}
+}
+
+func TestNumberTheoryErrors(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		body string
+		code string
+	}{
+		{`{"operation": "gcd", "args": [1.5, 3]}`, ""},
+		{`{"operation": "totient", "args": [9]}`, ""},
+		{`{"operation": "factor", "args": [1, 2]}`, ""},
+		{`{"operation": "factor", "args": [0]}`, "domain_error"},
+		{`{"operation": "factor", "args": ["340282366920938463463374607431768211456"]}`, "domain_error"},
+		{`{"operation": "mod", "args": [5, 0]}`, "division_by_zero"},
+		{`{"operation": "modinv", "args": [2, 4]}`, "domain_error"},
+		{`{"operation": "nCr", "args": [5]}`, "argument_count"},
+		{`{"args": [5]}`, ""},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/number-theory", json.RawMessage(tc.body))
+		var resp models.ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusBadRequest || resp.ErrorCode != tc.code {
+			t.Errorf("%s: status %d: %s, want %q", tc.body, rec.Code, rec.Body.String(), tc.code)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"calculator-backend/calculator"
+	"calculator-backend/models"
+	"calculator-backend/polynomial"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PolynomialRoots finds every complex root of a polynomial with its
+// multiplicity
+func (h *CalculatorHandler) PolynomialRoots(c *gin.Context) {
+	p, _, ok := h.bindPolynomial(c)
+	if !ok {
+		return
+	}
+	roots, err := p.Roots()
+	if err != nil {
+		calculationError(c, err)
+		return
+	}
+
+	resp := models.PolynomialResponse{
+		Polynomial: polynomialResult(p),
+		Roots:      make([]models.PolynomialRoot, len(roots)),
+		Success:    true,
+	}
+	for i, root := range roots {
+		resp.Roots[i] = models.PolynomialRoot{
+			Real:         real(root.Value),
+			Imag:         imag(root.Value),
+			Multiplicity: root.Multiplicity,
+		}
+		if root.Rational != nil {
+			resp.Roots[i].Exact = root.Rational.RatString()
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// PolynomialFactor factors a polynomial over the rationals
+func (h *CalculatorHandler) PolynomialFactor(c *gin.Context) {
+	p, _, ok := h.bindPolynomial(c)
+	if !ok {
+		return
+	}
+	factorization, err := p.Factor()
+	if err != nil {
+		calculationError(c, err)
+		return
+	}
+
+	factors := &models.PolynomialFactors{
+		Factored: factorization.String(),
+		Content:  factorization.Content.RatString(),
+		Factors:  make([]models.PolynomialFactor, len(factorization.Factors)),
+		Complete: factorization.Complete(),
+	}
+	for i, f := range factorization.Factors {
+		factors.Factors[i] = models.PolynomialFactor{
+			Factor:       f.Polynomial.String(),
+			Multiplicity: f.Multiplicity,
+			Irreducible:  f.Irreducible,
+		}
+	}
+	c.JSON(http.StatusOK, models.PolynomialResponse{
+		Polynomial: polynomialResult(p),
+		Factors:    factors,
+		Success:    true,
+	})
+}
+
+// PolynomialEvaluate evaluates a polynomial by Horner's method, exactly
+// when the point is rational
+func (h *CalculatorHandler) PolynomialEvaluate(c *gin.Context) {
+	p, req, ok := h.bindPolynomial(c)
+	if !ok {
+		return
+	}
+	if req.At == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Missing point",
+			Code:    400,
+			Message: "Evaluation requires a point in 'at'",
+		})
+		return
+	}
+	point, err := h.parser.EvaluateRational(req.At, calculator.EvalOptions{})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:     "Invalid point",
+			Code:      400,
+			Message:   err.Error(),
+			ErrorCode: calculator.ErrorCode(err),
+		})
+		return
+	}
+
+	resp := models.PolynomialResponse{Polynomial: polynomialResult(p), Success: true}
+	var value float64
+	if point.Exact() {
+		exact := p.Evaluate(point.Value)
+		value, _ = exact.Float64()
+		resp.Exact = exact.RatString()
+	} else {
+		value = p.EvaluateFloat(point.Float)
+	}
+	resp.Value = &value
+	c.JSON(http.StatusOK, resp)
+}
+
+// PolynomialArithmetic adds, subtracts, multiplies or divides two
+// polynomials. Division is long division with a remainder.
+func (h *CalculatorHandler) PolynomialArithmetic(c *gin.Context) {
+	var req models.PolynomialArithmeticRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request format",
+			Code:    400,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	variable := req.Variable
+	if variable == "" {
+		var err error
+		if variable, err = polynomial.Variable(req.A, req.B); err != nil {
+			calculationError(c, err)
+			return
+		}
+	}
+	a, err := polynomial.Parse(req.A, variable)
+	if err != nil {
+		calculationError(c, err)
+		return
+	}
+	b, err := polynomial.Parse(req.B, variable)
+	if err != nil {
+		calculationError(c, err)
+		return
+	}
+
+	resp := models.PolynomialArithmeticResponse{
+		Operation: req.Operation,
+		A:         polynomialResult(a),
+		B:         polynomialResult(b),
+		Success:   true,
+	}
+	var result *polynomial.Polynomial
+	switch req.Operation {
+	case "add":
+		result = polynomial.Add(a, b)
+	case "subtract":
+		result = polynomial.Sub(a, b)
+	case "multiply":
+		result, err = polynomial.Mul(a, b)
+	case "divide":
+		var quotient, remainder *polynomial.Polynomial
+		if quotient, remainder, err = polynomial.DivMod(a, b); err == nil {
+			q, r := polynomialResult(quotient), polynomialResult(remainder)
+			resp.Quotient, resp.Remainder = &q, &r
+		}
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid operation",
+			Code:    400,
+			Message: "Supported operations: add, subtract, multiply, divide",
+		})
+		return
+	}
+	if err != nil {
+		calculationError(c, err)
+		return
+	}
+	if result != nil {
+		r := polynomialResult(result)
+		resp.Result = &r
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// bindPolynomial reads a polynomial request and parses its polynomial,
+// writing an error response and reporting false when either fails
+func (h *CalculatorHandler) bindPolynomial(c *gin.Context) (*polynomial.Polynomial, models.PolynomialRequest, bool) {
+	var req models.PolynomialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request format",
+			Code:    400,
+			Message: err.Error(),
+		})
+		return nil, req, false
+	}
+	p, err := polynomial.Parse(req.Polynomial, req.Variable)
+	if err != nil {
+		calculationError(c, err)
+		return nil, req, false
+	}
+	return p, req, true
+}
+
+// polynomialResult converts a polynomial to its response form
+func polynomialResult(p *polynomial.Polynomial) models.PolynomialResult {
+	coeffs := p.Coefficients()
+	result := models.PolynomialResult{
+		Expression:   p.String(),
+		Variable:     p.Variable,
+		Degree:       p.Degree(),
+		Coefficients: make([]string, len(coeffs)),
+	}
+	for i, c := range coeffs {
+		result.Coefficients[i] = c.RatString()
+	}
+	return result
+}
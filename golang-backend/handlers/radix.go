@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"calculator-backend/calculator"
+	"calculator-backend/models"
+	"math/big"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConvertBase converts a number between bases 2 to 36, including
+// fractional parts. In two's-complement representation the digits in a
+// base other than ten are bit patterns; base ten keeps its minus sign.
+func (h *CalculatorHandler) ConvertBase(c *gin.Context) {
+	var req models.BaseConversionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request format",
+			Code:    400,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	width := 0
+	switch req.Representation {
+	case "", "sign-magnitude":
+	case "twos-complement":
+		if req.Width <= 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Missing width",
+				Code:    400,
+				Message: "Two's-complement conversion requires a width in bits",
+			})
+			return
+		}
+		width = req.Width
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid representation",
+			Code:    400,
+			Message: "Supported representations: sign-magnitude, twos-complement",
+		})
+		return
+	}
+
+	patternWidth := func(base int) int {
+		if base == 10 {
+			return 0
+		}
+		return width
+	}
+
+	value, err := calculator.ParseRadix(req.Value, req.From, patternWidth(req.From))
+	if err != nil {
+		calculationError(c, err)
+		return
+	}
+	radix, err := calculator.FormatRadix(value, calculator.RadixOptions{Base: req.To, MaxDigits: req.Digits, Width: patternWidth(req.To)})
+	if err != nil {
+		calculationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BaseConversionResponse{
+		Original: req.Value,
+		From:     req.From,
+		To:       req.To,
+		Result:   radix.Text,
+		Radix:    radixResult(radix, req.To),
+		Rational: value.RatString(),
+		Success:  true,
+	})
+}
+
+// radixResult converts a formatted number to its response form
+func radixResult(r *calculator.RadixResult, base int) *models.RadixResult {
+	return &models.RadixResult{
+		Base:      base,
+		Text:      r.Text,
+		Negative:  r.Negative,
+		Integer:   r.Integer,
+		Fraction:  r.Fraction,
+		Repeating: r.Repeating,
+		Exact:     r.Exact,
+	}
+}
+
+// renderBase writes the result of a calculation in the requested base.
+// Exact results are written exactly; a float64 result is written from its
+// shortest decimal form, so 0.1 repeats in base 2 as one tenth does.
+// Signed integer results are written in two's complement outside base ten.
+func renderBase(req models.CalculationRequest, typ calculator.IntType, resp *models.CalculationResponse) error {
+	opts := calculator.RadixOptions{Base: req.Base}
+	text := strconv.FormatFloat(resp.Result, 'g', -1, 64)
+	switch {
+	case resp.Integer != nil:
+		text = resp.Integer.Decimal
+		if !typ.Unsigned && req.Base != 10 {
+			opts.Width = typ.Bits
+			if opts.Width == 0 {
+				opts.Width = 64
+			}
+		}
+	case resp.Rational != nil:
+		text = resp.Rational.Fraction
+	case resp.Decimal != "":
+		text = resp.Decimal
+	}
+
+	value, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return nil
+	}
+	radix, err := calculator.FormatRadix(value, opts)
+	if err != nil {
+		return err
+	}
+	resp.Radix = radixResult(radix, req.Base)
+	return nil
+}
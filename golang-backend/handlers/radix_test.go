@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"calculator-backend/models"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestConvertBase(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		req      models.BaseConversionRequest
+		result   string
+		rational string
+		exact    bool
+	}{
+		{models.BaseConversionRequest{Value: "255", From: 10, To: 16}, "FF", "255", true},
+		{models.BaseConversionRequest{Value: "-1A.8", From: 16, To: 10}, "-26.5", "-53/2", true},
+		{models.BaseConversionRequest{Value: "0.1", From: 10, To: 2}, "0.0(0011)", "1/10", true},
+		{models.BaseConversionRequest{Value: "0.(3)", From: 10, To: 3}, "0.1", "1/3", true},
+		{models.BaseConversionRequest{Value: "zz", From: 36, To: 10}, "1295", "1295", true},
+		{models.BaseConversionRequest{Value: "0.1", From: 7, To: 10, Digits: 3}, "0.142", "1/7", false},
+		{models.BaseConversionRequest{Value: "-1", From: 10, To: 2, Representation: "twos-complement", Width: 8}, "11111111", "-1", true},
+		{models.BaseConversionRequest{Value: "FF", From: 16, To: 10, Representation: "twos-complement", Width: 8}, "-1", "-1", true},
+		{models.BaseConversionRequest{Value: "-5", From: 10, To: 2, Representation: "sign-magnitude"}, "-101", "-5", true},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/convert-base", tc.req)
+		var resp models.BaseConversionResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusOK {
+			t.Errorf("%+v: status %d: %s", tc.req, rec.Code, rec.Body.String())
+			continue
+		}
+		if resp.Result != tc.result || resp.Rational != tc.rational || resp.Radix == nil || resp.Radix.Exact != tc.exact {
+			t.Errorf("%+v: got %s", tc.req, rec.Body.String())
+		}
+	}
+}
+
+func TestConvertBaseErrors(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		req  models.BaseConversionRequest
+		code string
+	}{
+		{models.BaseConversionRequest{Value: "12", From: 10, To: 37}, "domain_error"},
+		{models.BaseConversionRequest{Value: "12", From: 2, To: 10}, "syntax_error"},
+		{models.BaseConversionRequest{Value: "1", From: 10, To: 2, Digits: 20000}, "domain_error"},
+		{models.BaseConversionRequest{Value: "128", From: 10, To: 2, Representation: "twos-complement", Width: 8}, "overflow"},
+		{models.BaseConversionRequest{Value: "0.5", From: 10, To: 2, Representation: "twos-complement", Width: 8}, "domain_error"},
+		{models.BaseConversionRequest{Value: "1", From: 10, To: 2, Representation: "twos-complement"}, ""},
+		{models.BaseConversionRequest{Value: "1", From: 10, To: 2, Representation: "ones-complement"}, ""},
+		{models.BaseConversionRequest{Value: "1", From: 10}, ""},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/convert-base", tc.req)
+		var resp models.ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusBadRequest || resp.ErrorCode != tc.code {
+			t.Errorf("%+v: status %d: %s, want %q", tc.req, rec.Code, rec.Body.String(), tc.code)
+		}
+	}
+}
+
+func TestEvaluateExpressionBase(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		req  models.CalculationRequest
+		text string
+	}{
+		{models.CalculationRequest{Expression: "200 + 55", Base: 16}, "FF"},
+		{models.CalculationRequest{Expression: "0.1", Base: 2}, "0.0(0011)"},
+		{models.CalculationRequest{Expression: "1/3", Number: "rational", Base: 3}, "0.1"},
+		{models.CalculationRequest{Expression: "-1", Number: "int", Width: 8, Base: 16}, "FF"},
+		{models.CalculationRequest{Expression: "-1", Number: "int", Width: 8, Base: 10}, "-1"},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/calculate", tc.req)
+		var resp models.CalculationResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusOK || resp.Radix == nil {
+			t.Errorf("%s in base %d: status %d: %s", tc.req.Expression, tc.req.Base, rec.Code, rec.Body.String())
+			continue
+		}
+		if resp.Radix.Text != tc.text || resp.Radix.Base != tc.req.Base {
+			t.Errorf("%s in base %d: radix %+v, want %s", tc.req.Expression, tc.req.Base, *resp.Radix, tc.text)
+		}
+	}
+
+	for _, req := range []models.CalculationRequest{
+		{Expression: "1", Base: 40},
+		{Expression: "2*i", Number: "complex", Base: 2},
+	} {
+		if rec := postJSON(router, "/api/calculate", req); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s in base %d: status %d, want 400", req.Expression, req.Base, rec.Code)
+		}
+	}
+}
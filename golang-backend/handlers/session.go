@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"calculator-backend/calculator"
+	"calculator-backend/models"
+	"calculator-backend/session"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHeader carries the session ID on calculator requests
+const SessionHeader = "X-Session-ID"
+
+// SessionHandler handles calculator session HTTP requests
+type SessionHandler struct {
+	store *session.Store
+}
+
+// NewSessionHandler creates a new SessionHandler
+func NewSessionHandler(store *session.Store) *SessionHandler {
+	return &SessionHandler{store: store}
+}
+
+// CreateSession starts a session with optional angle mode and digits
+func (h *SessionHandler) CreateSession(c *gin.Context) {
+	var req models.SessionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Invalid request format",
+				Code:    400,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+	if !validSessionRequest(c, req) {
+		return
+	}
+
+	digits := 0
+	if req.Digits != nil {
+		digits = *req.Digits
+	}
+	s, err := h.store.Create(angleMode(req.Mode), digits)
+	if errors.Is(err, session.ErrFull) {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error:   "Too many sessions",
+			Code:    503,
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Failed to create session",
+			Code:    500,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.Header(SessionHeader, s.ID)
+	c.JSON(http.StatusCreated, sessionResponse(s))
+}
+
+// GetSession returns a session's state
+func (h *SessionHandler) GetSession(c *gin.Context) {
+	s, err := h.store.Get(c.Param("id"))
+	if err != nil {
+		sessionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sessionResponse(s))
+}
+
+// UpdateSession changes a session's angle mode or display digits
+func (h *SessionHandler) UpdateSession(c *gin.Context) {
+	var req models.SessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request format",
+			Code:    400,
+			Message: err.Error(),
+		})
+		return
+	}
+	if !validSessionRequest(c, req) {
+		return
+	}
+
+	id := c.Param("id")
+	err := h.store.Update(id, func(s *session.Session) error {
+		if req.Mode != "" {
+			s.AngleMode = angleMode(req.Mode)
+		}
+		if req.Digits != nil {
+			s.Digits = *req.Digits
+		}
+		return nil
+	})
+	if err != nil {
+		sessionError(c, err)
+		return
+	}
+	h.GetSession(c)
+}
+
+// DeleteSession ends a session
+func (h *SessionHandler) DeleteSession(c *gin.Context) {
+	if err := h.store.Delete(c.Param("id")); err != nil {
+		sessionError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Memory applies M+, M-, MR, MC or MS to one of a session's registers
+func (h *SessionHandler) Memory(c *gin.Context) {
+	var req models.MemoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request format",
+			Code:    400,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	register := req.Register
+	if register == "" {
+		register = session.DefaultRegister
+	}
+
+	var value float64
+	err := h.store.Update(c.Param("id"), func(s *session.Session) error {
+		var err error
+		value, err = s.ApplyMemory(session.MemoryOp(req.Operation), register, req.Value)
+		return err
+	})
+	if err != nil {
+		sessionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.MemoryResponse{Register: register, Value: value})
+}
+
+// ListDefinitions returns a session's user-defined variables and functions
+func (h *SessionHandler) ListDefinitions(c *gin.Context) {
+	s, err := h.store.Get(c.Param("id"))
+	if err != nil {
+		sessionError(c, err)
+		return
+	}
+
+	names := make([]string, 0, len(s.Functions))
+	for name := range s.Functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	functions := make([]models.FunctionResponse, len(names))
+	for i, name := range names {
+		fn := s.Functions[name]
+		functions[i] = models.FunctionResponse{
+			Name:      fn.Name,
+			Params:    fn.Params,
+			Body:      fn.Source,
+			Signature: fn.Signature(),
+		}
+	}
+	c.JSON(http.StatusOK, models.DefinitionsResponse{Variables: s.Variables, Functions: functions})
+}
+
+// DeleteDefinition removes a session variable or function by name
+func (h *SessionHandler) DeleteDefinition(c *gin.Context) {
+	err := h.store.Update(c.Param("id"), func(s *session.Session) error {
+		return s.Undefine(c.Param("name"))
+	})
+	if errors.Is(err, session.ErrUndefined) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Definition not found",
+			Code:    404,
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		sessionError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ClearDefinitions removes every session variable and function
+func (h *SessionHandler) ClearDefinitions(c *gin.Context) {
+	err := h.store.Update(c.Param("id"), func(s *session.Session) error {
+		s.ClearDefinitions()
+		return nil
+	})
+	if err != nil {
+		sessionError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// validSessionRequest checks session preferences, responding with an
+// error when they are invalid
+func validSessionRequest(c *gin.Context, req models.SessionRequest) bool {
+	if req.Mode != "" && req.Mode != "degree" && req.Mode != "radian" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid angle mode",
+			Code:    400,
+			Message: "Supported modes: degree, radian",
+		})
+		return false
+	}
+	if req.Digits != nil && (*req.Digits < 0 || *req.Digits > 17) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid digits",
+			Code:    400,
+			Message: "Digits must be between 0 and 17",
+		})
+		return false
+	}
+	return true
+}
+
+// sessionResponse converts a session snapshot to its API form
+func sessionResponse(s *session.Session) models.SessionResponse {
+	resp := models.SessionResponse{
+		ID:        s.ID,
+		Memory:    s.Memory,
+		Variables: s.Variables,
+		Mode:      angleModeName(s.AngleMode),
+		Digits:    s.Digits,
+		ExpiresAt: s.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+	if s.HasAns {
+		ans := s.Ans
+		resp.Ans = &ans
+	}
+	return resp
+}
+
+// sessionError reports a failed session operation
+func sessionError(c *gin.Context, err error) {
+	if errors.Is(err, session.ErrNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Session not found",
+			Code:    404,
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error:   "Invalid session operation",
+		Code:    400,
+		Message: err.Error(),
+	})
+}
+
+// angleModeName is the inverse of angleMode; sessions created without a
+// mode report "default" and follow the engine's angle mode
+func angleModeName(mode calculator.AngleMode) string {
+	switch mode {
+	case calculator.AngleRadian:
+		return "radian"
+	case calculator.AngleDefault:
+		return "default"
+	}
+	return "degree"
+}
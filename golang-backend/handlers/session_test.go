@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"calculator-backend/models"
+	"calculator-backend/session"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newSessionRouter(ttl time.Duration) *gin.Engine {
+	sessions := session.NewStore(ttl, 0)
+	router := newHandlerRouter(NewCalculatorHandler(nil, nil, sessions))
+	handler := NewSessionHandler(sessions)
+	router.POST("/api/sessions", handler.CreateSession)
+	router.GET("/api/sessions/:id", handler.GetSession)
+	router.POST("/api/sessions/:id/memory", handler.Memory)
+	return router
+}
+
+func createSession(t *testing.T, router http.Handler, req models.SessionRequest) string {
+	t.Helper()
+	rec := postJSON(router, "/api/sessions", req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create session: status %d: %s", rec.Code, rec.Body.String())
+	}
+	return rec.Header().Get(SessionHeader)
+}
+
+// calculate evaluates an expression in a session and returns the result
+func calculate(t *testing.T, router http.Handler, id, expression string) float64 {
+	t.Helper()
+	rec := postJSON(router, "/api/calculate", models.CalculationRequest{Expression: expression, SessionID: id})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: status %d: %s", expression, rec.Code, rec.Body.String())
+	}
+	var resp models.CalculationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Result
+}
+
+func TestSessionState(t *testing.T) {
+	router := newSessionRouter(0)
+	id := createSession(t, router, models.SessionRequest{Mode: "radian"})
+
+	if got := calculate(t, router, id, "cos(pi)"); got != -1 {
+		t.Errorf("cos(pi) in a radian session = %v", got)
+	}
+	if got := calculate(t, router, id, "ans * 10"); got != -10 {
+		t.Errorf("ans * 10 = %v, want -10", got)
+	}
+
+	four, two := 4.0, 2.0
+	steps := []struct {
+		req  models.MemoryRequest
+		want float64
+	}{
+		{models.MemoryRequest{Operation: "MS"}, -10},
+		{models.MemoryRequest{Operation: "M+", Value: &four}, -6},
+		{models.MemoryRequest{Operation: "MS", Register: "r1", Value: &two}, 2},
+	}
+	for _, step := range steps {
+		rec := postJSON(router, "/api/sessions/"+id+"/memory", step.req)
+		var resp models.MemoryResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Value != step.want {
+			t.Errorf("%+v: %s, want %v", step.req, rec.Body.String(), step.want)
+		}
+	}
+	if got := calculate(t, router, id, "mem * r1"); got != -12 {
+		t.Errorf("mem * r1 = %v, want -12", got)
+	}
+}
+
+func TestSessionErrors(t *testing.T) {
+	router := newSessionRouter(20 * time.Millisecond)
+	id := createSession(t, router, models.SessionRequest{})
+
+	rec := postJSON(router, "/api/sessions/"+id+"/memory", models.MemoryRequest{Operation: "M+"})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("M+ without an answer: status %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = postJSON(router, "/api/sessions/"+id+"/memory", models.MemoryRequest{Operation: "MS", Register: "pi"})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("reserved register: status %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := postJSON(router, "/api/sessions", models.SessionRequest{Mode: "gradian"}); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown mode: status %d", rec.Code)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	rec = postJSON(router, "/api/calculate", models.CalculationRequest{Expression: "1", SessionID: id})
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expired session: status %d, want 404", rec.Code)
+	}
+	if rec := request(router, http.MethodGet, "/api/sessions/"+id); rec.Code != http.StatusNotFound {
+		t.Errorf("get expired session: status %d, want 404", rec.Code)
+	}
+}
+
+func TestSessionDefinitions(t *testing.T) {
+	router := newSessionRouter(0)
+	id := createSession(t, router, models.SessionRequest{})
+
+	for _, def := range []string{"r = 3", "area(x) = pi*x^2", "g(x) = h(x) + 1", "h(x) = 2*x"} {
+		if rec := postJSON(router, "/api/calculate", models.CalculationRequest{Expression: def, SessionID: id}); rec.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", def, rec.Code, rec.Body.String())
+		}
+	}
+	if got := calculate(t, router, id, "g(r)"); got != 7 {
+		t.Errorf("g(r) = %v, want 7", got)
+	}
+
+	cases := []struct {
+		expr string
+		want string
+	}{
+		{"f(x) = f(x) + 1", "recursion"},
+		{"h(x) = g(x)", "recursion"},
+		{"sin(x) = x", "invalid_name"},
+		{"pi = 3", "invalid_name"},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/calculate", models.CalculationRequest{Expression: tc.expr, SessionID: id})
+		var resp models.ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusBadRequest || resp.ErrorCode != tc.want {
+			t.Errorf("%s: status %d: %s, want %s", tc.expr, rec.Code, rec.Body.String(), tc.want)
+		}
+	}
+
+	// The rejected redefinition leaves h in place
+	if got := calculate(t, router, id, "h(1)"); got != 2 {
+		t.Errorf("h(1) = %v, want 2", got)
+	}
+	if rec := postJSON(router, "/api/calculate", models.CalculationRequest{Expression: "r = 1"}); rec.Code != http.StatusBadRequest {
+		t.Errorf("assignment without a session: status %d, want 400", rec.Code)
+	}
+}
+
+func TestCreateSessionLimit(t *testing.T) {
+	sessions := session.NewStore(0, 1)
+	router := newHandlerRouter(NewCalculatorHandler(nil, nil, sessions))
+	router.POST("/api/sessions", NewSessionHandler(sessions).CreateSession)
+
+	createSession(t, router, models.SessionRequest{})
+	rec := postJSON(router, "/api/sessions", models.SessionRequest{})
+	var resp models.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusServiceUnavailable || resp.Code != 503 {
+		t.Errorf("second session: status %d: %s", rec.Code, rec.Body.String())
+	}
+}
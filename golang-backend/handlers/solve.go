@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"calculator-backend/calculator"
+	"calculator-backend/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Solve finds the real roots of an equation in one variable within an
+// interval
+func (h *CalculatorHandler) Solve(c *gin.Context) {
+	var req models.SolveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Invalid request format",
+			Code:    400,
+			Message: err.Error(),
+		})
+		return
+	}
+	if req.Variable == "" {
+		req.Variable = "x"
+	}
+
+	opts := calculator.EvalOptions{AngleMode: angleMode(req.Mode), Variables: req.Variables}
+	lower, err := h.parser.Evaluate(req.Lower, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:     "Invalid lower bound",
+			Code:      400,
+			Message:   err.Error(),
+			ErrorCode: calculator.ErrorCode(err),
+		})
+		return
+	}
+	upper, err := h.parser.Evaluate(req.Upper, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:     "Invalid upper bound",
+			Code:      400,
+			Message:   err.Error(),
+			ErrorCode: calculator.ErrorCode(err),
+		})
+		return
+	}
+
+	bounds := calculator.SolveOptions{
+		Lower:         lower,
+		Upper:         upper,
+		Tolerance:     req.Tolerance,
+		MaxIterations: req.MaxIterations,
+		Samples:       req.Samples,
+	}
+	solution, err := h.parser.Solve(req.Equation, req.Variable, bounds, opts)
+	if err != nil {
+		calculationError(c, err)
+		return
+	}
+
+	resp := models.SolveResponse{
+		Original:    req.Equation,
+		Variable:    req.Variable,
+		Lower:       lower,
+		Upper:       upper,
+		Roots:       make([]models.SolveRoot, len(solution.Roots)),
+		Converged:   true,
+		Evaluations: solution.Evaluations,
+		Success:     true,
+	}
+	for i, root := range solution.Roots {
+		resp.Roots[i] = models.SolveRoot{
+			Value:      root.Value,
+			Residual:   root.Residual,
+			Iterations: root.Iterations,
+			Converged:  root.Converged,
+			Method:     root.Method,
+		}
+		resp.Converged = resp.Converged && root.Converged
+	}
+	c.JSON(http.StatusOK, resp)
+}
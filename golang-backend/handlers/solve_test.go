@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"calculator-backend/models"
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+)
+
+func TestSolveEndpoint(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		req   models.SolveRequest
+		roots []float64
+	}{
+		{models.SolveRequest{Equation: "x^3 - 2x = 5", Lower: "-10", Upper: "10"}, []float64{2.0945514815423265}},
+		{models.SolveRequest{Equation: "x^2 = 4", Lower: "-5", Upper: "5"}, []float64{-2, 2}},
+		{models.SolveRequest{Equation: "sin(t) = 0", Variable: "t", Lower: "-1", Upper: "2*pi + 1", Mode: "radian"}, []float64{0, math.Pi, 2 * math.Pi}},
+		{models.SolveRequest{Equation: "sin(x) = 0.5", Lower: "0", Upper: "180"}, []float64{30, 150}},
+		{models.SolveRequest{Equation: "a*x = b", Lower: "-10", Upper: "10", Variables: map[string]float64{"a": 3, "b": 6}}, []float64{2}},
+		{models.SolveRequest{Equation: "1/x", Lower: "-1", Upper: "1"}, []float64{}},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/solve", tc.req)
+		var resp models.SolveResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusOK {
+			t.Errorf("%s: status %d: %s", tc.req.Equation, rec.Code, rec.Body.String())
+			continue
+		}
+		if len(resp.Roots) != len(tc.roots) || !resp.Converged || resp.Evaluations == 0 {
+			t.Errorf("%s on [%s, %s]: %s, want %v", tc.req.Equation, tc.req.Lower, tc.req.Upper, rec.Body.String(), tc.roots)
+			continue
+		}
+		for i, root := range resp.Roots {
+			if math.Abs(root.Value-tc.roots[i]) > 1e-9 {
+				t.Errorf("%s: root %v, want %v", tc.req.Equation, root.Value, tc.roots[i])
+			}
+		}
+	}
+}
+
+func TestSolveEndpointErrors(t *testing.T) {
+	router := newTestRouter()
+
+	cases := []struct {
+		req   models.SolveRequest
+		error string
+		code  string
+	}{
+		{models.SolveRequest{Equation: "x = 1", Lower: "1", Upper: "0"}, "Calculation failed", "domain_error"},
+		{models.SolveRequest{Equation: "x =", Lower: "0", Upper: "1"}, "Calculation failed", "syntax_error"},
+		{models.SolveRequest{Equation: "x = 1", Variable: "pi", Lower: "0", Upper: "1"}, "Calculation failed", "invalid_name"},
+		{models.SolveRequest{Equation: "x = 1", Lower: "0", Upper: "1", MaxIterations: 20000}, "Calculation failed", "domain_error"},
+		{models.SolveRequest{Equation: "x = 1", Lower: "y", Upper: "1"}, "Invalid lower bound", "unknown_variable"},
+		{models.SolveRequest{Equation: "x = 1", Lower: "0", Upper: "1/0"}, "Invalid upper bound", "division_by_zero"},
+		{models.SolveRequest{Equation: "x = 1", Lower: "0"}, "Invalid request format", ""},
+	}
+	for _, tc := range cases {
+		rec := postJSON(router, "/api/solve", tc.req)
+		var resp models.ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || rec.Code != http.StatusBadRequest || resp.Error != tc.error || resp.ErrorCode != tc.code {
+			t.Errorf("%s on [%s, %s]: status %d: %s", tc.req.Equation, tc.req.Lower, tc.req.Upper, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package linalg
+
+import (
+	"math"
+)
+
+// LU is the factorization P·A = L·U of a square matrix by Gaussian
+// elimination with partial pivoting. L is unit lower triangular and U
+// upper triangular; both are stored together in one matrix.
+type LU struct {
+	packed *Matrix
+	pivot  []int   // row i of P·A is row pivot[i] of A
+	sign   float64 // determinant of P
+	norm   float64 // 1-norm of A, for the condition number
+}
+
+// LU factors a square matrix. A singular matrix factors without error;
+// its U has a zero on the diagonal.
+func (m *Matrix) LU() (*LU, error) {
+	if err := m.requireSquare("LU decomposition"); err != nil {
+		return nil, err
+	}
+	n := m.rows
+	lu := &LU{packed: m.clone(), pivot: make([]int, n), sign: 1, norm: m.Norm1()}
+	for i := range lu.pivot {
+		lu.pivot[i] = i
+	}
+	a := lu.packed
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a.At(i, k)) > math.Abs(a.At(p, k)) {
+				p = i
+			}
+		}
+		if p != k {
+			a.swapRows(p, k)
+			lu.pivot[p], lu.pivot[k] = lu.pivot[k], lu.pivot[p]
+			lu.sign = -lu.sign
+		}
+		pivot := a.At(k, k)
+		if pivot == 0 {
+			continue
+		}
+		for i := k + 1; i < n; i++ {
+			factor := a.At(i, k) / pivot
+			a.set(i, k, factor)
+			if factor == 0 {
+				continue
+			}
+			for j := k + 1; j < n; j++ {
+				a.set(i, j, a.At(i, j)-factor*a.At(k, j))
+			}
+		}
+	}
+	return lu, nil
+}
+
+// L returns the unit lower triangular factor
+func (lu *LU) L() *Matrix {
+	n := lu.packed.rows
+	l := Identity(n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			l.set(i, j, lu.packed.At(i, j))
+		}
+	}
+	return l
+}
+
+// U returns the upper triangular factor
+func (lu *LU) U() *Matrix {
+	n := lu.packed.rows
+	u := Zeros(n, n)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			u.set(i, j, lu.packed.At(i, j))
+		}
+	}
+	return u
+}
+
+// P returns the permutation matrix with P·A = L·U
+func (lu *LU) P() *Matrix {
+	n := lu.packed.rows
+	p := Zeros(n, n)
+	for i, row := range lu.pivot {
+		p.set(i, row, 1)
+	}
+	return p
+}
+
+// Det returns the determinant of the factored matrix
+func (lu *LU) Det() float64 {
+	det := lu.sign
+	for i := 0; i < lu.packed.rows; i++ {
+		det *= lu.packed.At(i, i)
+	}
+	return det
+}
+
+// singular reports whether a pivot vanished exactly
+func (lu *LU) singular() bool {
+	for i := 0; i < lu.packed.rows; i++ {
+		if lu.packed.At(i, i) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// condition returns the 1-norm condition number of the factored matrix,
+// computing its inverse explicitly; it is +Inf for a singular matrix
+func (lu *LU) condition() float64 {
+	if lu.singular() {
+		return math.Inf(1)
+	}
+	cond := lu.norm * lu.solve(Identity(lu.packed.rows)).Norm1()
+	if math.IsNaN(cond) {
+		return math.Inf(1)
+	}
+	return cond
+}
+
+// solve returns x with A·x = b by forward and back substitution. The
+// factored matrix must not be singular.
+func (lu *LU) solve(b *Matrix) *Matrix {
+	n, cols := lu.packed.rows, b.cols
+	x := Zeros(n, cols)
+	for i, row := range lu.pivot {
+		copy(x.data[i*cols:(i+1)*cols], b.data[row*cols:(row+1)*cols])
+	}
+	a := lu.packed
+	for j := 0; j < cols; j++ {
+		for i := 0; i < n; i++ {
+			sum := x.At(i, j)
+			for k := 0; k < i; k++ {
+				sum -= a.At(i, k) * x.At(k, j)
+			}
+			x.set(i, j, sum)
+		}
+		for i := n - 1; i >= 0; i-- {
+			sum := x.At(i, j)
+			for k := i + 1; k < n; k++ {
+				sum -= a.At(i, k) * x.At(k, j)
+			}
+			x.set(i, j, sum/a.At(i, i))
+		}
+	}
+	return x
+}
+
+// QR is the factorization A = Q·R of a matrix into an orthogonal Q and an
+// upper triangular R of the same shape as A
+type QR struct {
+	Q, R *Matrix
+}
+
+// QR factors a matrix by Householder reflections
+func (m *Matrix) QR() *QR {
+	rows, cols := m.rows, m.cols
+	r := m.clone()
+	q := Identity(rows)
+	v := make([]float64, rows)
+	for k := 0; k < min(rows-1, cols); k++ {
+		// The reflection I - 2·v·vᵀ/(vᵀ·v) maps column k below the
+		// diagonal onto a multiple of the k-th unit vector
+		var norm float64
+		for i := k; i < rows; i++ {
+			norm = math.Hypot(norm, r.At(i, k))
+		}
+		if norm == 0 {
+			continue
+		}
+		alpha := -math.Copysign(norm, r.At(k, k))
+		var vv float64
+		for i := k; i < rows; i++ {
+			v[i] = r.At(i, k)
+			if i == k {
+				v[i] -= alpha
+			}
+			vv += v[i] * v[i]
+		}
+		if vv == 0 {
+			continue
+		}
+
+		for j := k; j < cols; j++ {
+			var dot float64
+			for i := k; i < rows; i++ {
+				dot += v[i] * r.At(i, j)
+			}
+			f := 2 * dot / vv
+			for i := k; i < rows; i++ {
+				r.set(i, j, r.At(i, j)-f*v[i])
+			}
+		}
+		// Accumulate Q = H_1·H_2·…, applying each reflection on the right
+		for i := 0; i < rows; i++ {
+			var dot float64
+			for j := k; j < rows; j++ {
+				dot += q.At(i, j) * v[j]
+			}
+			f := 2 * dot / vv
+			for j := k; j < rows; j++ {
+				q.set(i, j, q.At(i, j)-f*v[j])
+			}
+		}
+		for i := k + 1; i < rows; i++ {
+			r.set(i, k, 0)
+		}
+	}
+	return &QR{Q: q, R: r}
+}
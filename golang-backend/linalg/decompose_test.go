@@ -0,0 +1,83 @@
+package linalg
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLU(t *testing.T) {
+	cases := []struct {
+		name string
+		rows [][]float64
+		det  float64
+	}{
+		{"needs pivoting", [][]float64{{0, 2, 1}, {1, 1, 1}, {2, 1, 0}}, 3},
+		{"tridiagonal", [][]float64{{2, -1, 0}, {-1, 2, -1}, {0, -1, 2}}, 4},
+		{"singular", [][]float64{{1, 2}, {2, 4}}, 0},
+		{"1×1", [][]float64{{-5}}, -5},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := mustMatrix(t, tc.rows)
+			lu, err := a.LU()
+			if err != nil {
+				t.Fatal(err)
+			}
+			l, u := lu.L(), lu.U()
+			n := a.Rows()
+			for i := 0; i < n; i++ {
+				if l.At(i, i) != 1 {
+					t.Errorf("L[%d][%d] = %v, want 1", i, i, l.At(i, i))
+				}
+				for j := i + 1; j < n; j++ {
+					if l.At(i, j) != 0 || u.At(j, i) != 0 {
+						t.Errorf("L or U not triangular at (%d, %d)", i, j)
+					}
+				}
+			}
+
+			pa, _ := Mul(lu.P(), a)
+			product, _ := Mul(l, u)
+			assertClose(t, "L·U", product, pa, 1e-12)
+			if math.Abs(lu.Det()-tc.det) > 1e-12 {
+				t.Errorf("det = %v, want %v", lu.Det(), tc.det)
+			}
+		})
+	}
+}
+
+func TestQR(t *testing.T) {
+	cases := []struct {
+		name string
+		m    *Matrix
+	}{
+		{"square", mustMatrix(t, [][]float64{{12, -51, 4}, {6, 167, -68}, {-4, 24, -41}})},
+		{"tall", mustMatrix(t, [][]float64{{1, 2}, {3, 4}, {5, 6}, {7, 8}})},
+		{"wide", mustMatrix(t, [][]float64{{1, 2, 3}, {4, 5, 6}})},
+		{"zero column", mustMatrix(t, [][]float64{{0, 1}, {0, 2}})},
+		{"Hilbert", hilbert(8)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			qr := tc.m.QR()
+			rows, cols := tc.m.Rows(), tc.m.Cols()
+
+			product, err := Mul(qr.Q, qr.R)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertClose(t, "Q·R", product, tc.m, 1e-12*math.Max(1, tc.m.Norm1()))
+
+			qtq, _ := Mul(qr.Q.Transpose(), qr.Q)
+			assertClose(t, "Qᵀ·Q", qtq, Identity(rows), 1e-12)
+
+			for i := 0; i < rows; i++ {
+				for j := 0; j < min(i, cols); j++ {
+					if qr.R.At(i, j) != 0 {
+						t.Errorf("R[%d][%d] = %v, want 0", i, j, qr.R.At(i, j))
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,364 @@
+// Package linalg implements dense real matrices: arithmetic, determinants,
+// inverses, rank, LU and QR decompositions and the solution of linear
+// systems.
+package linalg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Errors returned by the package. A ConditionError unwraps to
+// ErrSingular or ErrIllConditioned.
+var (
+	ErrDimension      = errors.New("dimension mismatch")
+	ErrSingular       = errors.New("singular matrix")
+	ErrIllConditioned = errors.New("ill-conditioned matrix")
+)
+
+// MaxCondition is the largest condition number a matrix may have before
+// inverting it or solving with it is refused. Beyond it fewer than four
+// significant digits of a float64 solution can be trusted.
+const MaxCondition = 1e12
+
+// MaxSize bounds the rows and columns of a matrix
+const MaxSize = 1000
+
+// ConditionError reports a matrix that is singular or too close to it.
+// Condition is the 1-norm condition number, +Inf when a pivot vanished
+// exactly.
+type ConditionError struct {
+	Condition float64
+}
+
+// Singular reports whether the matrix is singular to working precision
+func (e *ConditionError) Singular() bool {
+	return e.Condition*epsilon >= 1
+}
+
+func (e *ConditionError) Error() string {
+	switch {
+	case math.IsInf(e.Condition, 1):
+		return "matrix is singular"
+	case e.Singular():
+		return fmt.Sprintf("matrix is singular to working precision (condition number %.3g)", e.Condition)
+	}
+	return fmt.Sprintf("matrix is ill-conditioned (condition number %.3g)", e.Condition)
+}
+
+// Unwrap returns ErrSingular or ErrIllConditioned
+func (e *ConditionError) Unwrap() error {
+	if e.Singular() {
+		return ErrSingular
+	}
+	return ErrIllConditioned
+}
+
+// epsilon is the spacing of float64 values just above 1
+const epsilon = 0x1p-52
+
+// Matrix is a dense real matrix stored by rows. Operations return new
+// matrices and never modify their operands.
+type Matrix struct {
+	rows, cols int
+	data       []float64
+}
+
+// Zeros returns a rows×cols matrix of zeros
+func Zeros(rows, cols int) *Matrix {
+	return &Matrix{rows: rows, cols: cols, data: make([]float64, rows*cols)}
+}
+
+// Identity returns the n×n identity matrix
+func Identity(n int) *Matrix {
+	m := Zeros(n, n)
+	for i := 0; i < n; i++ {
+		m.data[i*n+i] = 1
+	}
+	return m
+}
+
+// FromRows builds a matrix from its rows, which must be non-empty and of
+// equal length
+func FromRows(rows [][]float64) (*Matrix, error) {
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return nil, fmt.Errorf("%w: a matrix needs at least one row and one column", ErrDimension)
+	}
+	if len(rows) > MaxSize || len(rows[0]) > MaxSize {
+		return nil, fmt.Errorf("%w: matrices are limited to %d rows and columns", ErrDimension, MaxSize)
+	}
+	m := Zeros(len(rows), len(rows[0]))
+	for i, row := range rows {
+		if len(row) != m.cols {
+			return nil, fmt.Errorf("%w: row %d has length %d, expected %d", ErrDimension, i+1, len(row), m.cols)
+		}
+		copy(m.data[i*m.cols:], row)
+	}
+	return m, nil
+}
+
+// Rows returns the number of rows
+func (m *Matrix) Rows() int { return m.rows }
+
+// Cols returns the number of columns
+func (m *Matrix) Cols() int { return m.cols }
+
+// At returns the entry in row i and column j, counting from zero
+func (m *Matrix) At(i, j int) float64 { return m.data[i*m.cols+j] }
+
+func (m *Matrix) set(i, j int, v float64) { m.data[i*m.cols+j] = v }
+
+// ToRows returns the entries as a slice of rows
+func (m *Matrix) ToRows() [][]float64 {
+	rows := make([][]float64, m.rows)
+	for i := range rows {
+		rows[i] = append([]float64(nil), m.data[i*m.cols:(i+1)*m.cols]...)
+	}
+	return rows
+}
+
+// IsSquare reports whether m has as many rows as columns
+func (m *Matrix) IsSquare() bool {
+	return m.rows == m.cols
+}
+
+// clone returns a copy of m
+func (m *Matrix) clone() *Matrix {
+	return &Matrix{rows: m.rows, cols: m.cols, data: append([]float64(nil), m.data...)}
+}
+
+// String writes m as a matrix literal: "[1, 2; 3, 4]"
+func (m *Matrix) String() string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i := 0; i < m.rows; i++ {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		for j := 0; j < m.cols; j++ {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(strconv.FormatFloat(m.At(i, j), 'g', -1, 64))
+		}
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// shape describes the dimensions of m for error messages
+func (m *Matrix) shape() string {
+	return fmt.Sprintf("%d×%d", m.rows, m.cols)
+}
+
+// requireSquare reports an error naming the operation if m is not square
+func (m *Matrix) requireSquare(operation string) error {
+	if !m.IsSquare() {
+		return fmt.Errorf("%w: %s requires a square matrix, got %s", ErrDimension, operation, m.shape())
+	}
+	return nil
+}
+
+// Add returns a + b
+func Add(a, b *Matrix) (*Matrix, error) {
+	if a.rows != b.rows || a.cols != b.cols {
+		return nil, fmt.Errorf("%w: cannot add %s and %s matrices", ErrDimension, a.shape(), b.shape())
+	}
+	result := a.clone()
+	for i, v := range b.data {
+		result.data[i] += v
+	}
+	return result, nil
+}
+
+// Sub returns a - b
+func Sub(a, b *Matrix) (*Matrix, error) {
+	if a.rows != b.rows || a.cols != b.cols {
+		return nil, fmt.Errorf("%w: cannot subtract %s and %s matrices", ErrDimension, a.shape(), b.shape())
+	}
+	return Add(a, b.Scale(-1))
+}
+
+// Mul returns the matrix product a·b
+func Mul(a, b *Matrix) (*Matrix, error) {
+	if a.cols != b.rows {
+		return nil, fmt.Errorf("%w: cannot multiply %s by %s matrices", ErrDimension, a.shape(), b.shape())
+	}
+	result := Zeros(a.rows, b.cols)
+	for i := 0; i < a.rows; i++ {
+		for k := 0; k < a.cols; k++ {
+			aik := a.At(i, k)
+			if aik == 0 {
+				continue
+			}
+			for j := 0; j < b.cols; j++ {
+				result.data[i*b.cols+j] += aik * b.At(k, j)
+			}
+		}
+	}
+	return result, nil
+}
+
+// Scale returns c·m
+func (m *Matrix) Scale(c float64) *Matrix {
+	result := m.clone()
+	for i := range result.data {
+		result.data[i] *= c
+	}
+	return result
+}
+
+// Transpose returns the transpose of m
+func (m *Matrix) Transpose() *Matrix {
+	result := Zeros(m.cols, m.rows)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			result.set(j, i, m.At(i, j))
+		}
+	}
+	return result
+}
+
+// Trace returns the sum of the diagonal of a square matrix
+func (m *Matrix) Trace() (float64, error) {
+	if err := m.requireSquare("trace"); err != nil {
+		return 0, err
+	}
+	var sum float64
+	for i := 0; i < m.rows; i++ {
+		sum += m.At(i, i)
+	}
+	return sum, nil
+}
+
+// Pow returns m^n for a square matrix; a negative n raises the inverse
+func (m *Matrix) Pow(n int) (*Matrix, error) {
+	if err := m.requireSquare("a power"); err != nil {
+		return nil, err
+	}
+	base := m
+	if n < 0 {
+		inv, err := m.Inverse()
+		if err != nil {
+			return nil, err
+		}
+		base, n = inv, -n
+	}
+	result := Identity(m.rows)
+	for ; n > 0; n >>= 1 {
+		var err error
+		if n&1 == 1 {
+			if result, err = Mul(result, base); err != nil {
+				return nil, err
+			}
+		}
+		if n > 1 {
+			if base, err = Mul(base, base); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// Norm1 returns the 1-norm of m, its largest absolute column sum
+func (m *Matrix) Norm1() float64 {
+	var norm float64
+	for j := 0; j < m.cols; j++ {
+		var sum float64
+		for i := 0; i < m.rows; i++ {
+			sum += math.Abs(m.At(i, j))
+		}
+		norm = math.Max(norm, sum)
+	}
+	return norm
+}
+
+// Det returns the determinant of a square matrix
+func (m *Matrix) Det() (float64, error) {
+	lu, err := m.LU()
+	if err != nil {
+		return 0, err
+	}
+	return lu.Det(), nil
+}
+
+// Condition returns the 1-norm condition number ‖A‖·‖A⁻¹‖ of a square
+// matrix, +Inf when it is singular
+func (m *Matrix) Condition() (float64, error) {
+	lu, err := m.LU()
+	if err != nil {
+		return 0, err
+	}
+	return lu.condition(), nil
+}
+
+// Inverse returns the inverse of a square matrix. It returns a
+// ConditionError when the matrix is singular or ill-conditioned.
+func (m *Matrix) Inverse() (*Matrix, error) {
+	return Solve(m, Identity(m.rows))
+}
+
+// Solve returns x with a·x = b for a square matrix a and a matrix b with
+// as many rows, solving for every column of b at once. It returns a
+// ConditionError when a is singular or ill-conditioned.
+func Solve(a, b *Matrix) (*Matrix, error) {
+	lu, err := a.LU()
+	if err != nil {
+		return nil, err
+	}
+	if b.rows != a.rows {
+		return nil, fmt.Errorf("%w: cannot solve a %s system with %s right-hand side", ErrDimension, a.shape(), b.shape())
+	}
+	if cond := lu.condition(); cond > MaxCondition {
+		return nil, &ConditionError{Condition: cond}
+	}
+	return lu.solve(b), nil
+}
+
+// Rank returns the numerical rank of m: the number of pivots of Gaussian
+// elimination with partial pivoting that exceed a tolerance scaled to the
+// size of the matrix and its entries
+func (m *Matrix) Rank() int {
+	work := m.clone()
+	var scale float64
+	for _, v := range work.data {
+		scale = math.Max(scale, math.Abs(v))
+	}
+	tol := float64(max(m.rows, m.cols)) * epsilon * scale
+
+	rank := 0
+	for col := 0; col < m.cols && rank < m.rows; col++ {
+		pivot := rank
+		for i := rank + 1; i < m.rows; i++ {
+			if math.Abs(work.At(i, col)) > math.Abs(work.At(pivot, col)) {
+				pivot = i
+			}
+		}
+		if math.Abs(work.At(pivot, col)) <= tol {
+			continue
+		}
+		work.swapRows(pivot, rank)
+		for i := rank + 1; i < m.rows; i++ {
+			factor := work.At(i, col) / work.At(rank, col)
+			for j := col; j < m.cols; j++ {
+				work.set(i, j, work.At(i, j)-factor*work.At(rank, j))
+			}
+		}
+		rank++
+	}
+	return rank
+}
+
+func (m *Matrix) swapRows(i, j int) {
+	if i == j {
+		return
+	}
+	for k := 0; k < m.cols; k++ {
+		a, b := i*m.cols+k, j*m.cols+k
+		m.data[a], m.data[b] = m.data[b], m.data[a]
+	}
+}
@@ -0,0 +1,213 @@
+package linalg
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func mustMatrix(t *testing.T, rows [][]float64) *Matrix {
+	t.Helper()
+	m, err := FromRows(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// hilbert returns the n×n Hilbert matrix, with entries 1/(i+j+1)
+func hilbert(n int) *Matrix {
+	m := Zeros(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			m.set(i, j, 1/float64(i+j+1))
+		}
+	}
+	return m
+}
+
+// assertClose fails unless got and want have the same shape and entries
+// equal to within tol
+func assertClose(t *testing.T, name string, got, want *Matrix, tol float64) {
+	t.Helper()
+	if got.Rows() != want.Rows() || got.Cols() != want.Cols() {
+		t.Fatalf("%s: shape %s, want %s", name, got.shape(), want.shape())
+	}
+	for i, v := range got.data {
+		if math.Abs(v-want.data[i]) > tol {
+			t.Fatalf("%s = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestFromRows(t *testing.T) {
+	cases := []struct {
+		name string
+		rows [][]float64
+	}{
+		{"empty", nil},
+		{"empty row", [][]float64{{}}},
+		{"ragged", [][]float64{{1, 2}, {3}}},
+		{"too many columns", [][]float64{make([]float64, MaxSize+1)}},
+	}
+	for _, tc := range cases {
+		if _, err := FromRows(tc.rows); !errors.Is(err, ErrDimension) {
+			t.Errorf("%s: error %v, want %v", tc.name, err, ErrDimension)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a := mustMatrix(t, [][]float64{{1, 2}, {3, 4}})
+	b := mustMatrix(t, [][]float64{{5, 6}, {7, 8}})
+
+	sum, err := Add(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "a+b", sum, mustMatrix(t, [][]float64{{6, 8}, {10, 12}}), 0)
+
+	diff, err := Sub(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "a-b", diff, mustMatrix(t, [][]float64{{-4, -4}, {-4, -4}}), 0)
+
+	prod, err := Mul(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "a·b", prod, mustMatrix(t, [][]float64{{19, 22}, {43, 50}}), 0)
+
+	cube, err := a.Pow(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "a^3", cube, mustMatrix(t, [][]float64{{37, 54}, {81, 118}}), 0)
+
+	assertClose(t, "transpose", a.Transpose(), mustMatrix(t, [][]float64{{1, 3}, {2, 4}}), 0)
+	if got := a.String(); got != "[1, 2; 3, 4]" {
+		t.Errorf("String() = %s", got)
+	}
+
+	row := mustMatrix(t, [][]float64{{1, 2, 3}})
+	if _, err := Add(a, row); !errors.Is(err, ErrDimension) {
+		t.Errorf("adding 2×2 and 1×3: error %v, want %v", err, ErrDimension)
+	}
+	if _, err := Mul(a, row.Transpose()); !errors.Is(err, ErrDimension) {
+		t.Errorf("multiplying 2×2 by 3×1: error %v, want %v", err, ErrDimension)
+	}
+	if _, err := row.Det(); !errors.Is(err, ErrDimension) {
+		t.Errorf("determinant of 1×3: error %v, want %v", err, ErrDimension)
+	}
+}
+
+func TestInverse(t *testing.T) {
+	a := mustMatrix(t, [][]float64{{2, -1, 0}, {-1, 2, -1}, {0, -1, 2}})
+
+	det, err := a.Det()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(det-4) > 1e-12 {
+		t.Errorf("det = %v, want 4", det)
+	}
+
+	inv, err := a.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := mustMatrix(t, [][]float64{{3, 2, 1}, {2, 4, 2}, {1, 2, 3}}).Scale(0.25)
+	assertClose(t, "inverse", inv, want, 1e-12)
+
+	// The 1-norm condition number is ‖A‖·‖A⁻¹‖ = 4·2
+	cond, err := a.Condition()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(cond-8) > 1e-12 {
+		t.Errorf("condition = %v, want 8", cond)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	a := mustMatrix(t, [][]float64{{0, 2, 1}, {1, 1, 1}, {2, 1, 0}})
+	b := mustMatrix(t, [][]float64{{7, 1}, {6, 1}, {4, 3}})
+
+	x, err := Solve(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClose(t, "x", x, mustMatrix(t, [][]float64{{1, 1}, {2, 1}, {3, -1}}), 1e-12)
+
+	if _, err := Solve(a, mustMatrix(t, [][]float64{{1}, {2}})); !errors.Is(err, ErrDimension) {
+		t.Errorf("3×3 system with 2×1 right-hand side: error %v, want %v", err, ErrDimension)
+	}
+}
+
+func TestSingular(t *testing.T) {
+	cases := []struct {
+		name     string
+		m        *Matrix
+		infinite bool // a pivot vanishes exactly
+	}{
+		{"zero row", mustMatrix(t, [][]float64{{1, 2}, {0, 0}}), true},
+		{"dependent rows", mustMatrix(t, [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}), false},
+		{"Hilbert 14×14", hilbert(14), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.m.Inverse()
+			if !errors.Is(err, ErrSingular) {
+				t.Fatalf("error %v, want %v", err, ErrSingular)
+			}
+			var condErr *ConditionError
+			if !errors.As(err, &condErr) {
+				t.Fatalf("error %T is not a *ConditionError", err)
+			}
+			if math.IsInf(condErr.Condition, 1) != tc.infinite {
+				t.Errorf("condition = %v", condErr.Condition)
+			}
+		})
+	}
+}
+
+func TestIllConditioned(t *testing.T) {
+	// The 1-norm condition number of the 10×10 Hilbert matrix is 3.5353e13
+	_, err := Solve(hilbert(10), Identity(10))
+	if !errors.Is(err, ErrIllConditioned) {
+		t.Fatalf("error %v, want %v", err, ErrIllConditioned)
+	}
+	var condErr *ConditionError
+	if !errors.As(err, &condErr) {
+		t.Fatalf("error %T is not a *ConditionError", err)
+	}
+	if math.Abs(condErr.Condition-3.5353e13) > 0.01e13 {
+		t.Errorf("condition = %.5g, want 3.5353e13", condErr.Condition)
+	}
+
+	// The 6×6 Hilbert matrix, with condition 2.9070e7, still inverts
+	if _, err := hilbert(6).Inverse(); err != nil {
+		t.Errorf("Hilbert 6×6: %v", err)
+	}
+}
+
+func TestRank(t *testing.T) {
+	cases := []struct {
+		name string
+		rows [][]float64
+		want int
+	}{
+		{"full", [][]float64{{1, 2}, {3, 4}}, 2},
+		{"dependent rows", [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}, 2},
+		{"multiples", [][]float64{{1, 2}, {2, 4}, {-3, -6}}, 1},
+		{"wide", [][]float64{{1, 0, 1, 0}, {0, 1, 0, 1}}, 2},
+		{"zero", [][]float64{{0, 0}, {0, 0}}, 0},
+		{"rounding", [][]float64{{0.1, 0.2}, {0.3, 0.6}}, 1},
+	}
+	for _, tc := range cases {
+		if got := mustMatrix(t, tc.rows).Rank(); got != tc.want {
+			t.Errorf("%s: rank %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
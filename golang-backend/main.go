@@ -1,9 +1,13 @@
 package main
 
 import (
+	"calculator-backend/calculator"
 	"calculator-backend/handlers"
+	"calculator-backend/session"
+	"calculator-backend/storage"
 	"log"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -17,16 +21,34 @@ func main() {
 	// Configure CORS
 	config := cors.Config{
 		AllowOrigins:     []string{"*"}, // In production, specify exact origins
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", handlers.SessionHeader},
+		ExposeHeaders:    []string{"Content-Length", handlers.SessionHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}
 	router.Use(cors.New(config))
 
-	// Create calculator handler
-	calculatorHandler := handlers.NewCalculatorHandler()
+	// History is kept in memory unless HISTORY_FILE names a file to persist it
+	var history storage.HistoryStore = storage.NewMemoryHistoryStore(storage.DefaultHistoryLimit)
+	if path := os.Getenv("HISTORY_FILE"); path != "" {
+		fileStore, err := storage.NewFileHistoryStore(path, storage.DefaultHistoryLimit)
+		if err != nil {
+			log.Fatal("Failed to open history file:", err)
+		}
+		history = fileStore
+	}
+
+	sessions := session.NewStore(session.DefaultTTL, 0)
+
+	// Functions registered here are available over the API as well.
+	registry := calculator.NewRegistry()
+	engine := calculator.New(calculator.WithFunctions(registry))
+
+	// Create handlers
+	calculatorHandler := handlers.NewCalculatorHandler(engine, history, sessions)
+	historyHandler := handlers.NewHistoryHandler(history)
+	sessionHandler := handlers.NewSessionHandler(sessions)
 
 	// API routes
 	api := router.Group("/api")
@@ -38,10 +60,41 @@ func main() {
 		api.POST("/calculate", calculatorHandler.EvaluateExpression)
 		api.POST("/basic", calculatorHandler.BasicOperation)
 		api.POST("/scientific", calculatorHandler.ScientificOperation)
+		api.POST("/number-theory", calculatorHandler.NumberTheory)
+		api.POST("/derivative", calculatorHandler.Derivative)
+		api.POST("/integrate", calculatorHandler.Integrate)
+		api.POST("/solve", calculatorHandler.Solve)
+
+		// Polynomials with exact rational coefficients
+		api.POST("/polynomial/arithmetic", calculatorHandler.PolynomialArithmetic)
+		api.POST("/polynomial/evaluate", calculatorHandler.PolynomialEvaluate)
+		api.POST("/polynomial/roots", calculatorHandler.PolynomialRoots)
+		api.POST("/polynomial/factor", calculatorHandler.PolynomialFactor)
+
+		// Dense real matrices
+		api.POST("/matrix", calculatorHandler.Matrix)
 		
 		// Utility endpoints
 		api.GET("/constants", calculatorHandler.GetConstants)
+		api.GET("/functions", calculatorHandler.ListFunctions)
 		api.GET("/convert-angle", calculatorHandler.ConvertAngle)
+		api.POST("/convert-base", calculatorHandler.ConvertBase)
+
+		// Calculation history
+		api.GET("/history", historyHandler.ListHistory)
+		api.DELETE("/history", historyHandler.ClearHistory)
+		api.GET("/history/:id", historyHandler.GetHistoryEntry)
+		api.DELETE("/history/:id", historyHandler.DeleteHistoryEntry)
+
+		// Sessions carrying ans, memory registers and preferences
+		api.POST("/sessions", sessionHandler.CreateSession)
+		api.GET("/sessions/:id", sessionHandler.GetSession)
+		api.PATCH("/sessions/:id", sessionHandler.UpdateSession)
+		api.DELETE("/sessions/:id", sessionHandler.DeleteSession)
+		api.POST("/sessions/:id/memory", sessionHandler.Memory)
+		api.GET("/sessions/:id/definitions", sessionHandler.ListDefinitions)
+		api.DELETE("/sessions/:id/definitions", sessionHandler.ClearDefinitions)
+		api.DELETE("/sessions/:id/definitions/:name", sessionHandler.DeleteDefinition)
 	}
 
 	// Root endpoint
@@ -55,8 +108,18 @@ func main() {
 				"calculate":    "POST /api/calculate",
 				"basic":        "POST /api/basic",
 				"scientific":   "POST /api/scientific",
+				"numberTheory": "POST /api/number-theory",
+				"derivative":   "POST /api/derivative",
+				"integrate":    "POST /api/integrate",
+				"solve":        "POST /api/solve",
+				"polynomial":   "POST /api/polynomial/{arithmetic,evaluate,roots,factor}",
+				"matrix":       "POST /api/matrix",
 				"constants":    "/api/constants",
+				"functions":    "/api/functions",
 				"convertAngle": "/api/convert-angle",
+				"convertBase":  "POST /api/convert-base",
+				"history":      "/api/history",
+				"sessions":     "POST /api/sessions",
 			},
 		})
 	})
@@ -1,17 +1,67 @@
 package models
 
+import "encoding/json"
+
 // CalculationRequest represents the request payload for calculations
 type CalculationRequest struct {
-	Expression string `json:"expression" binding:"required"`
-	Mode       string `json:"mode,omitempty"` // "degree" or "radian" for trigonometric functions
+	Expression string             `json:"expression" binding:"required"`
+	Mode       string             `json:"mode,omitempty"`      // "degree" or "radian" for trigonometric functions
+	Digits     int                `json:"digits,omitempty"`    // significant digits to round the result to
+	Variables  map[string]float64 `json:"variables,omitempty"` // values for free identifiers such as x
+	Precision  uint               `json:"precision,omitempty"` // mantissa bits; enables arbitrary-precision evaluation
+	Number     string             `json:"number,omitempty"`    // "float" (default), "rational", "complex" or "int"
+	Width      int                `json:"width,omitempty"`     // bits of the integer type in int mode: 8, 16, 32 or 64 (default)
+	Signed     *bool              `json:"signed,omitempty"`    // whether the integer type is signed in int mode; default true
+	Overflow   string             `json:"overflow,omitempty"`  // "wrap" (default) or "error" when int arithmetic overflows
+	Base       int                `json:"base,omitempty"`      // also write the result in this base, 2 to 36
+	SessionID  string             `json:"sessionId,omitempty"` // alternative to the X-Session-ID header
 }
 
 // CalculationResponse represents the response payload for calculations
 type CalculationResponse struct {
-	Result   float64 `json:"result"`
-	Original string  `json:"original"`
-	Success  bool    `json:"success"`
-	Error    string  `json:"error,omitempty"`
+	Result     float64         `json:"result"`
+	Decimal    string          `json:"decimal,omitempty"`    // full-precision result in arbitrary-precision mode
+	Rational   *RationalResult `json:"rational,omitempty"`   // exact result in rational mode
+	Complex    *ComplexResult  `json:"complex,omitempty"`    // real and imaginary parts in complex mode
+	Integer    *IntegerResult  `json:"integer,omitempty"`    // the result in several bases in int mode
+	Radix      *RadixResult    `json:"radix,omitempty"`      // the result in the requested base
+	Fallback   string          `json:"fallback,omitempty"`   // why rational mode fell back to floating point
+	Definition string          `json:"definition,omitempty"` // variable or function signature defined by the input
+	Matrix     *MatrixResult   `json:"matrix,omitempty"`     // the result of an expression with matrices, when it is a matrix
+	Original   string          `json:"original"`
+	Success    bool            `json:"success"`
+	Error      string          `json:"error,omitempty"`
+	ErrorCode  string          `json:"errorCode,omitempty"` // stable machine-readable kind of a failure
+	Span       *Span           `json:"span,omitempty"`      // offending part of the expression, in characters
+	Condition  *float64        `json:"condition,omitempty"` // condition number of a singular or ill-conditioned matrix
+}
+
+// RationalResult is an exact fraction; components are strings because
+// they may exceed the range of JSON numbers
+type RationalResult struct {
+	Numerator   string `json:"numerator"`
+	Denominator string `json:"denominator"`
+	Fraction    string `json:"fraction"`
+	Mixed       string `json:"mixed"`
+}
+
+// ComplexResult is a complex number in rectangular and polar form
+type ComplexResult struct {
+	Real     float64 `json:"real"`
+	Imag     float64 `json:"imag"`
+	Modulus  float64 `json:"modulus"`
+	Argument float64 `json:"argument"` // in the request's angle mode
+	Text     string  `json:"text"`
+}
+
+// IntegerResult is a fixed-width integer in the bases of a programmer's
+// calculator. Hex, octal and binary show the two's-complement bit pattern.
+type IntegerResult struct {
+	Type    string `json:"type"` // such as "int32" or "uint8"
+	Decimal string `json:"decimal"`
+	Hex     string `json:"hex"`
+	Octal   string `json:"octal"`
+	Binary  string `json:"binary"`
 }
 
 // BasicOperationRequest for simple operations
@@ -23,9 +73,312 @@ type BasicOperationRequest struct {
 
 // ScientificOperationRequest for scientific functions
 type ScientificOperationRequest struct {
-	Value    float64 `json:"value" binding:"required"`
-	Function string  `json:"function" binding:"required"`
-	Mode     string  `json:"mode,omitempty"` // "degree" or "radian"
+	Value    float64   `json:"value" binding:"required"`
+	Args     []float64 `json:"args,omitempty"` // arguments after value, for functions such as beta(value, b)
+	Function string    `json:"function" binding:"required"`
+	Mode     string    `json:"mode,omitempty"` // "degree" or "radian"
+}
+
+// NumberTheoryRequest applies an integer operation, such as "gcd",
+// "factor" or "modpow", to exact arguments
+type NumberTheoryRequest struct {
+	Operation string        `json:"operation" binding:"required"`
+	Args      []json.Number `json:"args"` // integers; send strings for values beyond 2^53
+}
+
+// NumberTheoryResponse is the exact result of an integer operation
+type NumberTheoryResponse struct {
+	Operation string        `json:"operation"`
+	Result    string        `json:"result,omitempty"`  // integer result; 1 or 0 for isPrime
+	Factors   []PrimeFactor `json:"factors,omitempty"` // prime factorization for "factor"
+	Success   bool          `json:"success"`
+}
+
+// PrimeFactor is a prime and its exponent in a factorization
+type PrimeFactor struct {
+	Prime    string `json:"prime"`
+	Exponent int    `json:"exponent"`
+}
+
+// BaseConversionRequest converts a number between bases 2 to 36
+type BaseConversionRequest struct {
+	Value string `json:"value" binding:"required"` // digits in base from, such as "-1A.8" or "0.(3)"
+	From  int    `json:"from" binding:"required"`
+	To    int    `json:"to" binding:"required"`
+	// Digits limits the fraction digits written; 0 means 64
+	Digits int `json:"digits,omitempty"`
+	// Representation is "sign-magnitude" (default) or "twos-complement",
+	// which reads and writes Width-bit patterns
+	Representation string `json:"representation,omitempty"`
+	Width          int    `json:"width,omitempty"`
+}
+
+// BaseConversionResponse is a number written in the requested base
+type BaseConversionResponse struct {
+	Original string       `json:"original"`
+	From     int          `json:"from"`
+	To       int          `json:"to"`
+	Result   string       `json:"result"`
+	Radix    *RadixResult `json:"radix"`
+	Rational string       `json:"rational"` // exact value as a decimal fraction, such as "1/3"
+	Success  bool         `json:"success"`
+}
+
+// RadixResult is a number written in a base other than ten. A fraction
+// that repeats is split into the digits before the cycle and the cycle.
+type RadixResult struct {
+	Base      int    `json:"base"`
+	Text      string `json:"text"` // the repeating cycle, if any, in parentheses: "0.0(0011)"
+	Negative  bool   `json:"negative"`
+	Integer   string `json:"integer"`
+	Fraction  string `json:"fraction,omitempty"`
+	Repeating string `json:"repeating,omitempty"`
+	Exact     bool   `json:"exact"` // false when the fraction was cut off at the digit limit
+}
+
+// DerivativeRequest differentiates an expression symbolically
+type DerivativeRequest struct {
+	Expression string             `json:"expression" binding:"required"`
+	Variable   string             `json:"variable,omitempty"`  // defaults to x
+	At         *float64           `json:"at,omitempty"`        // also evaluate the derivative here
+	Mode       string             `json:"mode,omitempty"`      // "degree" or "radian" for trigonometric functions
+	Variables  map[string]float64 `json:"variables,omitempty"` // values for other identifiers when evaluating at a point
+}
+
+// DerivativeResponse is a simplified symbolic derivative
+type DerivativeResponse struct {
+	Original   string   `json:"original"`
+	Variable   string   `json:"variable"`
+	Derivative string   `json:"derivative"`
+	Value      *float64 `json:"value,omitempty"` // the derivative at the requested point
+	Success    bool     `json:"success"`
+}
+
+// IntegralRequest evaluates a definite integral numerically. Bounds are
+// expressions, or "inf" and "-inf" for an infinite range.
+type IntegralRequest struct {
+	Expression     string             `json:"expression" binding:"required"`
+	Variable       string             `json:"variable,omitempty"` // defaults to x
+	Lower          string             `json:"lower" binding:"required"`
+	Upper          string             `json:"upper" binding:"required"`
+	Tolerance      float64            `json:"tolerance,omitempty"`      // absolute error to aim for; defaults to 1e-10
+	MaxEvaluations int                `json:"maxEvaluations,omitempty"` // limit on integrand evaluations, from 15 to 1000000; defaults to 100000
+	Mode           string             `json:"mode,omitempty"`           // "degree" or "radian" for trigonometric functions
+	Variables      map[string]float64 `json:"variables,omitempty"`      // values for other identifiers
+}
+
+// IntegralResponse is the value of a definite integral with its
+// estimated error
+type IntegralResponse struct {
+	Original      string  `json:"original"`
+	Variable      string  `json:"variable"`
+	Lower         string  `json:"lower"`
+	Upper         string  `json:"upper"`
+	Result        float64 `json:"result"`
+	ErrorEstimate float64 `json:"errorEstimate"`
+	Evaluations   int     `json:"evaluations"`
+	Intervals     int     `json:"intervals"`
+	Converged     bool    `json:"converged"` // false when the evaluation limit was reached before the tolerance
+	Success       bool    `json:"success"`
+}
+
+// SolveRequest finds the real roots of an equation lhs = rhs in an
+// interval. Bounds are expressions.
+type SolveRequest struct {
+	Equation      string             `json:"equation" binding:"required"`
+	Variable      string             `json:"variable,omitempty"` // defaults to x
+	Lower         string             `json:"lower" binding:"required"`
+	Upper         string             `json:"upper" binding:"required"`
+	Tolerance     float64            `json:"tolerance,omitempty"`     // accuracy of each root; defaults to 1e-12
+	MaxIterations int                `json:"maxIterations,omitempty"` // iterations per root, at most 10000; defaults to 100
+	Samples       int                `json:"samples,omitempty"`       // subintervals scanned for sign changes; defaults to 1000
+	Mode          string             `json:"mode,omitempty"`          // "degree" or "radian" for trigonometric functions
+	Variables     map[string]float64 `json:"variables,omitempty"`     // values for other identifiers
+}
+
+// SolveResponse lists the roots found in increasing order
+type SolveResponse struct {
+	Original    string      `json:"original"`
+	Variable    string      `json:"variable"`
+	Lower       float64     `json:"lower"`
+	Upper       float64     `json:"upper"`
+	Roots       []SolveRoot `json:"roots"`
+	Converged   bool        `json:"converged"` // whether every root converged
+	Evaluations int         `json:"evaluations"`
+	Success     bool        `json:"success"`
+}
+
+// SolveRoot is one root of an equation
+type SolveRoot struct {
+	Value      float64 `json:"value"`
+	Residual   float64 `json:"residual"` // lhs - rhs at the root
+	Iterations int     `json:"iterations"`
+	Converged  bool    `json:"converged"`
+	Method     string  `json:"method"` // "brent", "newton" or "exact"
+}
+
+// PolynomialRequest finds the roots or factors of a polynomial, or
+// evaluates it at a point
+type PolynomialRequest struct {
+	Polynomial string `json:"polynomial" binding:"required"`
+	Variable   string `json:"variable,omitempty"` // the polynomial's only identifier unless given
+	At         string `json:"at,omitempty"`       // expression for the point to evaluate at
+}
+
+// PolynomialArithmeticRequest combines two polynomials
+type PolynomialArithmeticRequest struct {
+	Operation string `json:"operation" binding:"required"` // "add", "subtract", "multiply" or "divide"
+	A         string `json:"a" binding:"required"`
+	B         string `json:"b" binding:"required"`
+	Variable  string `json:"variable,omitempty"`
+}
+
+// PolynomialResult is a polynomial in normal form
+type PolynomialResult struct {
+	Expression   string   `json:"expression"`
+	Variable     string   `json:"variable"`
+	Degree       int      `json:"degree"`       // -1 for the zero polynomial
+	Coefficients []string `json:"coefficients"` // exact, constant term first
+}
+
+// PolynomialResponse carries the result of one polynomial operation
+type PolynomialResponse struct {
+	Polynomial PolynomialResult   `json:"polynomial"`
+	Roots      []PolynomialRoot   `json:"roots,omitempty"`
+	Factors    *PolynomialFactors `json:"factors,omitempty"`
+	Value      *float64           `json:"value,omitempty"` // the polynomial at the requested point
+	Exact      string             `json:"exact,omitempty"` // the value as a fraction, when the point is rational
+	Success    bool               `json:"success"`
+}
+
+// PolynomialRoot is a complex root with its multiplicity
+type PolynomialRoot struct {
+	Real         float64 `json:"real"`
+	Imag         float64 `json:"imag"`
+	Multiplicity int     `json:"multiplicity"`
+	Exact        string  `json:"exact,omitempty"` // the root as a fraction, when it is rational
+}
+
+// PolynomialFactors is a factorization over the rationals
+type PolynomialFactors struct {
+	Factored string             `json:"factored"` // the whole product as an expression
+	Content  string             `json:"content"`
+	Factors  []PolynomialFactor `json:"factors"`
+	Complete bool               `json:"complete"` // whether every factor is known to be irreducible
+}
+
+// PolynomialFactor is one factor and its multiplicity
+type PolynomialFactor struct {
+	Factor       string `json:"factor"`
+	Multiplicity int    `json:"multiplicity"`
+	Irreducible  bool   `json:"irreducible"`
+}
+
+// PolynomialArithmeticResponse is the result of combining two polynomials
+type PolynomialArithmeticResponse struct {
+	Operation string            `json:"operation"`
+	A         PolynomialResult  `json:"a"`
+	B         PolynomialResult  `json:"b"`
+	Result    *PolynomialResult `json:"result,omitempty"`    // sum, difference or product
+	Quotient  *PolynomialResult `json:"quotient,omitempty"`  // for division
+	Remainder *PolynomialResult `json:"remainder,omitempty"` // for division
+	Success   bool              `json:"success"`
+}
+
+// MatrixRequest applies one operation to matrices given as rows
+type MatrixRequest struct {
+	// "add", "subtract", "multiply", "transpose", "determinant",
+	// "inverse", "rank", "lu", "qr" or "solve"
+	Operation string      `json:"operation" binding:"required"`
+	A         [][]float64 `json:"a" binding:"required"`
+	B         [][]float64 `json:"b,omitempty"` // second operand, or the right-hand side of solve
+}
+
+// MatrixResult is a matrix and its dimensions
+type MatrixResult struct {
+	Rows    int         `json:"rows"`
+	Cols    int         `json:"cols"`
+	Entries [][]float64 `json:"entries"`
+	Text    string      `json:"text"` // the matrix as a literal, e.g. "[1, 2; 3, 4]"
+}
+
+// MatrixResponse is the result of a matrix operation
+type MatrixResponse struct {
+	Operation string        `json:"operation"`
+	Result    *MatrixResult `json:"result,omitempty"`    // matrix result of arithmetic, transpose, inverse and solve
+	Value     *float64      `json:"value,omitempty"`     // determinant or rank
+	L         *MatrixResult `json:"l,omitempty"`         // for lu, with P·A = L·U
+	U         *MatrixResult `json:"u,omitempty"`         // for lu
+	P         *MatrixResult `json:"p,omitempty"`         // for lu
+	Q         *MatrixResult `json:"q,omitempty"`         // for qr, with A = Q·R
+	R         *MatrixResult `json:"r,omitempty"`         // for qr
+	Condition *float64      `json:"condition,omitempty"` // 1-norm condition number for inverse and solve
+	Success   bool          `json:"success"`
+}
+
+// SessionRequest creates a session or updates its preferences
+type SessionRequest struct {
+	Mode   string `json:"mode,omitempty"`   // "degree" or "radian"
+	Digits *int   `json:"digits,omitempty"` // significant digits to display; 0 keeps full precision
+}
+
+// SessionResponse describes a session's state
+type SessionResponse struct {
+	ID        string             `json:"id"`
+	Ans       *float64           `json:"ans"` // null until the first successful calculation
+	Memory    map[string]float64 `json:"memory"`
+	Variables map[string]float64 `json:"variables"`
+	Mode      string             `json:"mode"` // "degree", "radian" or "default" for the server's default
+	Digits    int                `json:"digits"`
+	ExpiresAt string             `json:"expiresAt"`
+}
+
+// MemoryRequest applies a memory key (M+, M-, MR, MC or MS) to a register
+type MemoryRequest struct {
+	Operation string   `json:"operation" binding:"required"`
+	Register  string   `json:"register,omitempty"` // defaults to "mem"
+	Value     *float64 `json:"value,omitempty"`    // defaults to the last answer
+}
+
+// MemoryResponse reports a register's value after a memory operation
+type MemoryResponse struct {
+	Register string  `json:"register"`
+	Value    float64 `json:"value"`
+}
+
+// DefinitionsResponse lists a session's user-defined variables and functions
+type DefinitionsResponse struct {
+	Variables map[string]float64 `json:"variables"`
+	Functions []FunctionResponse `json:"functions"`
+}
+
+// FunctionResponse describes a user-defined function
+type FunctionResponse struct {
+	Name      string   `json:"name"`
+	Params    []string `json:"params"`
+	Body      string   `json:"body"`
+	Signature string   `json:"signature"` // such as area(r)
+}
+
+// CatalogResponse lists the operators and functions the engine supports
+type CatalogResponse struct {
+	Language  string         `json:"language"` // "en" or "id"
+	Operators []CatalogEntry `json:"operators"`
+	Functions []CatalogEntry `json:"functions"`
+}
+
+// CatalogEntry describes one operator or function
+type CatalogEntry struct {
+	Name        string   `json:"name"`
+	Notation    string   `json:"notation,omitempty"` // "infix", "prefix" or "postfix" for operators
+	Category    string   `json:"category,omitempty"`
+	MinArgs     int      `json:"minArgs"`
+	MaxArgs     int      `json:"maxArgs"` // -1 for variadic functions
+	Domain      string   `json:"domain,omitempty"`
+	Angle       string   `json:"angle"` // "argument" or "result" when the angle mode applies, else "none"
+	Description string   `json:"description"`
+	Examples    []string `json:"examples"`
+	Modes       []string `json:"modes"` // number modes that evaluate it natively
 }
 
 // HistoryResponse for calculation history
@@ -33,12 +386,33 @@ type HistoryResponse struct {
 	ID         int     `json:"id"`
 	Expression string  `json:"expression"`
 	Result     float64 `json:"result"`
+	Display    string  `json:"display,omitempty"` // exact, complex or full-precision form of the result
+	Error      string  `json:"error,omitempty"`   // set when the evaluation failed
 	Timestamp  string  `json:"timestamp"`
 }
 
+// HistoryListResponse is one page of calculation history
+type HistoryListResponse struct {
+	Entries []HistoryResponse `json:"entries"`
+	Total   int               `json:"total"` // matching entries across all pages
+	Offset  int               `json:"offset"`
+	Limit   int               `json:"limit"`
+}
+
 // ErrorResponse for error handling
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-}
\ No newline at end of file
+	Error     string `json:"error"`
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	ErrorCode string `json:"errorCode,omitempty"` // stable machine-readable kind, e.g. "division_by_zero"
+	Span      *Span  `json:"span,omitempty"`      // offending part of the expression, in characters
+	// Condition is the condition number of a singular or ill-conditioned
+	// matrix; it is absent when a pivot vanished exactly
+	Condition *float64 `json:"condition,omitempty"`
+}
+
+// Span is a half-open range of character offsets in an expression
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
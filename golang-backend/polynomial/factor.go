@@ -0,0 +1,378 @@
+package polynomial
+
+import (
+	"calculator-backend/calculator"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxFactorCandidates bounds the subsets of roots tried when searching for
+// the factors of one degree
+const maxFactorCandidates = 20000
+
+// Factor is a factor of a polynomial and the power it appears to
+type Factor struct {
+	Polynomial   *Polynomial // primitive, with integer coefficients and a positive leading one
+	Multiplicity int
+	// Irreducible is false when the factor was too large to search
+	// completely, so it may still split over the rationals
+	Irreducible bool
+}
+
+// Factorization writes a polynomial as Content times the product of its
+// factors raised to their multiplicities
+type Factorization struct {
+	Content *big.Rat
+	Factors []Factor
+}
+
+// Complete reports whether every factor is known to be irreducible
+func (f *Factorization) Complete() bool {
+	for _, factor := range f.Factors {
+		if !factor.Irreducible {
+			return false
+		}
+	}
+	return true
+}
+
+// String writes the factorization as an expression: "2*(x - 1)^2*(x^2 + 1)"
+func (f *Factorization) String() string {
+	var parts []string
+	one := big.NewRat(1, 1)
+	switch {
+	case len(f.Factors) == 0 || (f.Content.Cmp(one) != 0 && f.Content.Cmp(big.NewRat(-1, 1)) != 0):
+		parts = append(parts, f.Content.RatString())
+	case f.Content.Sign() < 0:
+		parts = append(parts, "-")
+	}
+	for _, factor := range f.Factors {
+		text := factor.Polynomial.String()
+		if len(factor.Polynomial.terms()) > 1 {
+			text = "(" + text + ")"
+		}
+		if factor.Multiplicity > 1 {
+			text += "^" + strconv.Itoa(factor.Multiplicity)
+		}
+		parts = append(parts, text)
+	}
+
+	var b strings.Builder
+	for i, part := range parts {
+		if i > 0 && parts[i-1] != "-" {
+			b.WriteString("*")
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+// terms returns the powers with nonzero coefficients
+func (p *Polynomial) terms() []int {
+	var powers []int
+	for i, c := range p.coeffs {
+		if c.Sign() != 0 {
+			powers = append(powers, i)
+		}
+	}
+	return powers
+}
+
+// Factor factors p over the rationals. Repeated factors are separated
+// exactly by square-free decomposition; each square-free part is then
+// split by testing products of its numerical roots, rounded to integer
+// coefficients, as exact divisors.
+func (p *Polynomial) Factor() (*Factorization, error) {
+	parts, content, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	result := &Factorization{Content: content, Factors: []Factor{}}
+	for _, part := range parts {
+		result.Factors = append(result.Factors, Factor{
+			Polynomial:   part.poly,
+			Multiplicity: part.multiplicity,
+			Irreducible:  part.irreducible,
+		})
+	}
+	return result, nil
+}
+
+// factorPart is a factor together with its numerical roots
+type factorPart struct {
+	poly         *Polynomial
+	multiplicity int
+	irreducible  bool
+	roots        []complex128
+}
+
+// factor splits p into primitive factors ordered by degree, returning
+// them with the rational content left over
+func (p *Polynomial) factor() ([]factorPart, *big.Rat, error) {
+	if p.IsZero() {
+		return nil, nil, calculator.NewError(calculator.ErrDomain, "the zero polynomial has no roots or factors")
+	}
+	if p.Degree() > MaxRootDegree {
+		return nil, nil, calculator.NewError(calculator.ErrUnsupported, "roots and factors are limited to degree %d", MaxRootDegree)
+	}
+
+	var parts []factorPart
+	product := constant(p.Variable, big.NewRat(1, 1))
+	for _, sf := range p.squareFree() {
+		_, prim := sf.poly.primitive()
+		split, err := prim.split()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, part := range split {
+			part.multiplicity = sf.multiplicity
+			parts = append(parts, part)
+			power, err := part.poly.Pow(part.multiplicity)
+			if err != nil {
+				return nil, nil, err
+			}
+			if product, err = Mul(product, power); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	sort.SliceStable(parts, func(i, j int) bool {
+		if parts[i].poly.Degree() != parts[j].poly.Degree() {
+			return parts[i].poly.Degree() < parts[j].poly.Degree()
+		}
+		return parts[i].poly.String() < parts[j].poly.String()
+	})
+	content := new(big.Rat).Quo(p.leading(), product.leading())
+	return parts, content, nil
+}
+
+// squareFreePart is a square-free factor and its multiplicity
+type squareFreePart struct {
+	poly         *Polynomial
+	multiplicity int
+}
+
+// squareFree splits p into coprime square-free monic factors a_i with
+// p = c·a_1·a_2²·a_3³…, by Yun's algorithm
+func (p *Polynomial) squareFree() []squareFreePart {
+	var parts []squareFreePart
+	f := p.Monic()
+	df := f.Derivative()
+	a := GCD(f, df)
+	b, _, _ := DivMod(f, a)
+	c, _, _ := DivMod(df, a)
+	d := Sub(c, b.Derivative())
+	for i := 1; b.Degree() > 0; i++ {
+		a = GCD(b, d)
+		b, _, _ = DivMod(b, a)
+		c, _, _ = DivMod(d, a)
+		d = Sub(c, b.Derivative())
+		if a.Degree() > 0 {
+			parts = append(parts, squareFreePart{poly: a, multiplicity: i})
+		}
+	}
+	return parts
+}
+
+// primitive splits p into a rational content and a polynomial with
+// coprime integer coefficients and a positive leading coefficient
+func (p *Polynomial) primitive() (*big.Rat, *Polynomial) {
+	lcm := big.NewInt(1)
+	for _, c := range p.coeffs {
+		g := new(big.Int).GCD(nil, nil, lcm, c.Denom())
+		lcm.Mul(lcm, new(big.Int).Quo(c.Denom(), g))
+	}
+	gcd := new(big.Int)
+	for _, c := range p.coeffs {
+		n := new(big.Int).Mul(c.Num(), new(big.Int).Quo(lcm, c.Denom()))
+		gcd.GCD(nil, nil, gcd, n.Abs(n))
+	}
+	content := new(big.Rat).SetFrac(gcd, lcm)
+	if p.leading().Sign() < 0 {
+		content.Neg(content)
+	}
+	return content, p.Scale(new(big.Rat).Inv(content))
+}
+
+// split factors a primitive square-free polynomial. Factors of each
+// degree are sought in turn, so a factor found is irreducible as long as
+// every smaller degree was searched completely.
+func (p *Polynomial) split() ([]factorPart, error) {
+	if p.Degree() == 1 {
+		return []factorPart{{poly: p, irreducible: true}}, nil
+	}
+	roots, err := p.numericRoots()
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []factorPart
+	complete := true
+	for k := 1; 2*k <= len(roots); {
+		if binomial(len(roots), k) > maxFactorCandidates {
+			complete = false
+			break
+		}
+		factor, used, exhaustive := p.findFactor(roots, k)
+		if factor == nil {
+			complete = complete && exhaustive
+			k++
+			continue
+		}
+		parts = append(parts, factorPart{poly: factor, irreducible: complete, roots: pick(roots, used, true)})
+		p, _, _ = DivMod(p, factor)
+		roots = pick(roots, used, false)
+	}
+	if p.Degree() > 0 {
+		parts = append(parts, factorPart{poly: p, irreducible: complete, roots: roots})
+	}
+	return parts, nil
+}
+
+// findFactor looks for a factor of p whose roots are k of the given
+// roots. It reports whether the search was exhaustive, which it is not
+// when candidates were too large to round reliably.
+func (p *Polynomial) findFactor(roots []complex128, k int) (*Polynomial, []int, bool) {
+	lead := p.leading().Num()
+	divisors, exhaustive := positiveDivisors(lead)
+
+	idx := make([]int, k)
+	for i := range idx {
+		idx[i] = i
+	}
+	for {
+		// The monic polynomial with the chosen roots; a factor over the
+		// integers is it times a divisor of p's leading coefficient
+		monic := []complex128{1}
+		for _, i := range idx {
+			next := make([]complex128, len(monic)+1)
+			for j, c := range monic {
+				next[j+1] += c
+				next[j] -= c * roots[i]
+			}
+			monic = next
+		}
+
+		if coeffs := realCoefficients(monic); coeffs != nil {
+			for _, d := range divisors {
+				factor, ok := roundFactor(p.Variable, coeffs, float64(d))
+				if !ok {
+					exhaustive = false
+					continue
+				}
+				if factor == nil {
+					continue
+				}
+				if _, rem, _ := DivMod(p, factor); rem.IsZero() {
+					_, prim := factor.primitive()
+					return prim, append([]int(nil), idx...), exhaustive
+				}
+			}
+		}
+
+		// Advance to the next combination of k indices
+		i := k - 1
+		for i >= 0 && idx[i] == len(roots)-k+i {
+			i--
+		}
+		if i < 0 {
+			return nil, nil, exhaustive
+		}
+		idx[i]++
+		for j := i + 1; j < k; j++ {
+			idx[j] = idx[j-1] + 1
+		}
+	}
+}
+
+// realCoefficients returns the real parts of coefficients that are real
+// to within rounding, or nil when the roots chosen are not closed under
+// conjugation
+func realCoefficients(coeffs []complex128) []float64 {
+	var scale float64
+	for _, c := range coeffs {
+		scale = math.Max(scale, math.Abs(real(c)))
+	}
+	result := make([]float64, len(coeffs))
+	for i, c := range coeffs {
+		if math.Abs(imag(c)) > 1e-6*(1+scale) {
+			return nil
+		}
+		result[i] = real(c)
+	}
+	return result
+}
+
+// roundFactor scales coefficients by d and rounds them to integers. It
+// returns nil when they are not close to integers, and false when they
+// are too large for float64 rounding to be trusted.
+func roundFactor(variable string, coeffs []float64, d float64) (*Polynomial, bool) {
+	var scale float64
+	for _, c := range coeffs {
+		scale = math.Max(scale, math.Abs(c*d))
+	}
+	if scale > 1<<50 {
+		return nil, false
+	}
+	rounded := make([]*big.Rat, len(coeffs))
+	for i, c := range coeffs {
+		r := math.Round(c * d)
+		if math.Abs(c*d-r) > 1e-6*(1+scale) {
+			return nil, true
+		}
+		rounded[i] = new(big.Rat).SetFloat64(r)
+	}
+	return New(variable, rounded...), true
+}
+
+// positiveDivisors lists the divisors of n by trial division. It reports
+// false, with only 1 and n, when n is too large to factor quickly.
+func positiveDivisors(n *big.Int) ([]int64, bool) {
+	if n.BitLen() > 40 {
+		if n.IsInt64() {
+			return []int64{1, n.Int64()}, false
+		}
+		return []int64{1}, false
+	}
+	v := new(big.Int).Abs(n).Int64()
+	var small, large []int64
+	for d := int64(1); d*d <= v; d++ {
+		if v%d == 0 {
+			small = append(small, d)
+			if d*d != v {
+				large = append([]int64{v / d}, large...)
+			}
+		}
+	}
+	return append(small, large...), true
+}
+
+// pick returns the roots at the used indices, or those not at them
+func pick(roots []complex128, used []int, chosen bool) []complex128 {
+	in := make(map[int]bool, len(used))
+	for _, i := range used {
+		in[i] = true
+	}
+	var result []complex128
+	for i, z := range roots {
+		if in[i] == chosen {
+			result = append(result, z)
+		}
+	}
+	return result
+}
+
+// binomial returns n choose k, saturating well above any useful bound
+func binomial(n, k int) int {
+	result := 1
+	for i := 1; i <= k; i++ {
+		result = result * (n - k + i) / i
+		if result > 1<<40 {
+			return result
+		}
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+package polynomial
+
+import (
+	"calculator-backend/calculator"
+	"errors"
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestFactor(t *testing.T) {
+	cases := []struct {
+		expression string
+		want       string
+	}{
+		{"x^4 + 4", "(x^2 + 2*x + 2)*(x^2 - 2*x + 2)"},
+		{"x^6 - 1", "(x + 1)*(x - 1)*(x^2 + x + 1)*(x^2 - x + 1)"},
+		{"(x-1)^3(x+2)", "(x + 2)*(x - 1)^3"},
+		{"6x^2 + 3x", "3*(2*x + 1)*x"},
+		{"x^2/2 - 1/8", "1/8*(2*x + 1)*(2*x - 1)"},
+		{"(2x-1)^2/4", "1/4*(2*x - 1)^2"},
+		{"-x^2 + 2", "-(x^2 - 2)"},
+		{"x^5 - x - 1", "(x^5 - x - 1)"},
+		{"5", "5"},
+	}
+	for _, tc := range cases {
+		f, err := mustParse(t, tc.expression).Factor()
+		if err != nil {
+			t.Errorf("factor %s: %v", tc.expression, err)
+			continue
+		}
+		if got := f.String(); got != tc.want {
+			t.Errorf("factor %s = %s, want %s", tc.expression, got, tc.want)
+		}
+		if !f.Complete() {
+			t.Errorf("factor %s: incomplete", tc.expression)
+		}
+	}
+}
+
+func TestFactorErrors(t *testing.T) {
+	cases := []struct {
+		expression string
+		kind       error
+	}{
+		{"0", calculator.ErrDomain},
+		{"x^101 + 1", calculator.ErrUnsupported},
+	}
+	for _, tc := range cases {
+		p := mustParse(t, tc.expression)
+		if _, err := p.Factor(); !errors.Is(err, tc.kind) {
+			t.Errorf("factor %s: error %v, want %v", tc.expression, err, tc.kind)
+		}
+		if _, err := p.Roots(); !errors.Is(err, tc.kind) {
+			t.Errorf("roots of %s: error %v, want %v", tc.expression, err, tc.kind)
+		}
+	}
+}
+
+func TestRoots(t *testing.T) {
+	cases := []struct {
+		expression string
+		want       []complex128
+		mult       []int
+	}{
+		{"x^4 + 4", []complex128{-1 - 1i, -1 + 1i, 1 - 1i, 1 + 1i}, []int{1, 1, 1, 1}},
+		{"(x-1)^3(x+2)", []complex128{-2, 1}, []int{1, 3}},
+		{"x^2 - 2", []complex128{-math.Sqrt2, math.Sqrt2}, []int{1, 1}},
+		{"(2x-1)^2", []complex128{0.5}, []int{2}},
+	}
+	for _, tc := range cases {
+		roots, err := mustParse(t, tc.expression).Roots()
+		if err != nil {
+			t.Errorf("roots of %s: %v", tc.expression, err)
+			continue
+		}
+		if len(roots) != len(tc.want) {
+			t.Errorf("roots of %s: %v, want %v", tc.expression, roots, tc.want)
+			continue
+		}
+		for i, root := range roots {
+			if cmplx.Abs(root.Value-tc.want[i]) > 1e-12 || root.Multiplicity != tc.mult[i] {
+				t.Errorf("roots of %s: root %d = %v ×%d, want %v ×%d", tc.expression, i, root.Value, root.Multiplicity, tc.want[i], tc.mult[i])
+			}
+		}
+	}
+
+	// Linear factors give exact rational roots
+	roots, err := mustParse(t, "6x^2 + 3x").Roots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roots[0].Rational == nil || roots[0].Rational.RatString() != "-1/2" {
+		t.Errorf("rational root = %v, want -1/2", roots[0].Rational)
+	}
+}
@@ -0,0 +1,205 @@
+package polynomial
+
+import (
+	"calculator-backend/calculator"
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// engine evaluates the constant parts of polynomial expressions
+var engine = calculator.NewExpressionParser()
+
+// Variable returns the one identifier the expressions share that is not a
+// built-in constant, or x when they have none
+func Variable(expressions ...string) (string, error) {
+	seen := make(map[string]bool)
+	for _, expression := range expressions {
+		prog, err := calculator.Compile(expression)
+		if err != nil {
+			return "", err
+		}
+		for _, name := range prog.Variables() {
+			seen[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	switch len(names) {
+	case 0:
+		return "x", nil
+	case 1:
+		return names[0], nil
+	}
+	return "", calculator.NewError(calculator.ErrUnsupported,
+		"a polynomial has one variable, found %s", strings.Join(names, ", "))
+}
+
+// Parse reads a polynomial written as an expression in variable, such as
+// "(x - 1)(x + 2)^2/3". Parts not involving the variable must have exact
+// rational values. When variable is empty it is found with Variable.
+func Parse(expression, variable string) (*Polynomial, error) {
+	if variable == "" {
+		var err error
+		if variable, err = Variable(expression); err != nil {
+			return nil, err
+		}
+	}
+	prog, err := calculator.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	b := &builder{variable: variable}
+	return b.build(prog.Tree())
+}
+
+// builder converts an expression tree into a polynomial
+type builder struct {
+	variable string
+}
+
+func (b *builder) build(node calculator.Node) (*Polynomial, error) {
+	if !b.depends(node) {
+		c, err := b.constant(node)
+		if err != nil {
+			return nil, err
+		}
+		return constant(b.variable, c), nil
+	}
+
+	switch n := node.(type) {
+	case *calculator.IdentNode:
+		return monomial(b.variable, big.NewRat(1, 1), 1), nil
+
+	case *calculator.UnaryNode:
+		operand, err := b.build(n.Operand)
+		if err != nil {
+			return nil, err
+		}
+		switch n.Op {
+		case "+":
+			return operand, nil
+		case "-":
+			return operand.Scale(big.NewRat(-1, 1)), nil
+		}
+
+	case *calculator.BinaryNode:
+		return b.buildBinary(n)
+	}
+	return nil, b.notPolynomial(node)
+}
+
+func (b *builder) buildBinary(n *calculator.BinaryNode) (*Polynomial, error) {
+	switch n.Op {
+	case "/":
+		// Only division by a constant keeps a polynomial
+		if b.depends(n.Right) {
+			return nil, b.notPolynomial(n)
+		}
+		divisor, err := b.constant(n.Right)
+		if err != nil {
+			return nil, err
+		}
+		if divisor.Sign() == 0 {
+			return nil, calculator.ErrorAt(calculator.ErrDivisionByZero, n.Span(), "division by zero")
+		}
+		left, err := b.build(n.Left)
+		if err != nil {
+			return nil, err
+		}
+		return left.Scale(new(big.Rat).Inv(divisor)), nil
+
+	case "^":
+		if b.depends(n.Right) {
+			return nil, b.notPolynomial(n)
+		}
+		exponent, err := b.constant(n.Right)
+		if err != nil {
+			return nil, err
+		}
+		if !exponent.IsInt() || exponent.Sign() < 0 || !exponent.Num().IsInt64() {
+			return nil, calculator.ErrorAt(calculator.ErrDomain, n.Right.Span(),
+				"exponent of a polynomial must be a non-negative integer")
+		}
+		base, err := b.build(n.Left)
+		if err != nil {
+			return nil, err
+		}
+		if exponent.Num().Int64() > MaxDegree {
+			return nil, calculator.ErrorAt(calculator.ErrOverflow, n.Span(), "degree exceeds %d", MaxDegree)
+		}
+		result, err := base.Pow(int(exponent.Num().Int64()))
+		return result, b.locate(err, n.Span())
+
+	case "+", "-", "*":
+		left, err := b.build(n.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := b.build(n.Right)
+		if err != nil {
+			return nil, err
+		}
+		switch n.Op {
+		case "+":
+			return Add(left, right), nil
+		case "-":
+			return Sub(left, right), nil
+		}
+		result, err := Mul(left, right)
+		return result, b.locate(err, n.Span())
+	}
+	return nil, b.notPolynomial(n)
+}
+
+// depends reports whether node refers to the variable
+func (b *builder) depends(node calculator.Node) bool {
+	found := false
+	calculator.Walk(node, func(n calculator.Node) {
+		if ident, ok := n.(*calculator.IdentNode); ok && ident.Name == b.variable {
+			found = true
+		}
+	})
+	return found
+}
+
+// constant evaluates a part of the expression without the variable, which
+// must have an exact rational value
+func (b *builder) constant(node calculator.Node) (*big.Rat, error) {
+	text := calculator.FormatExpression(node)
+	result, err := engine.EvaluateRational(text, calculator.EvalOptions{})
+	if err != nil {
+		// Offsets into the reformatted text mean nothing to the caller
+		var calcErr *calculator.Error
+		if errors.As(err, &calcErr) {
+			return nil, calculator.ErrorAt(calcErr.Kind, node.Span(), "%s", calcErr.Message)
+		}
+		return nil, err
+	}
+	if !result.Exact() {
+		return nil, calculator.ErrorAt(calculator.ErrDomain, node.Span(),
+			"coefficient '%s' is not rational", text)
+	}
+	return result.Value, nil
+}
+
+// notPolynomial reports a part of the expression outside polynomial
+// arithmetic, such as a function of the variable
+func (b *builder) notPolynomial(node calculator.Node) error {
+	return calculator.ErrorAt(calculator.ErrUnsupported, node.Span(),
+		"'%s' is not a polynomial in %s", calculator.FormatExpression(node), b.variable)
+}
+
+// locate attaches span to an unlocated error from polynomial arithmetic
+func (b *builder) locate(err error, span calculator.Span) error {
+	var calcErr *calculator.Error
+	if errors.As(err, &calcErr) && !calcErr.Located() {
+		return calculator.ErrorAt(calcErr.Kind, span, "%s", calcErr.Message)
+	}
+	return err
+}
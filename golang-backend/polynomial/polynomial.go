@@ -0,0 +1,281 @@
+// Package polynomial implements polynomials in one variable with exact
+// rational coefficients: arithmetic, evaluation, complex roots and
+// factoring over the rationals.
+package polynomial
+
+import (
+	"calculator-backend/calculator"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MaxDegree bounds the degree of any polynomial built by this package
+const MaxDegree = 1000
+
+// Polynomial is a polynomial with rational coefficients. It is immutable;
+// every operation returns a new value.
+type Polynomial struct {
+	Variable string
+	// coeffs[i] is the coefficient of Variable^i. The last is nonzero, so
+	// the zero polynomial has no coefficients.
+	coeffs []*big.Rat
+}
+
+// New builds a polynomial from its coefficients, constant term first
+func New(variable string, coeffs ...*big.Rat) *Polynomial {
+	p := &Polynomial{Variable: variable, coeffs: make([]*big.Rat, len(coeffs))}
+	for i, c := range coeffs {
+		p.coeffs[i] = new(big.Rat).Set(c)
+	}
+	return p.trim()
+}
+
+// constant returns the polynomial c
+func constant(variable string, c *big.Rat) *Polynomial {
+	return New(variable, c)
+}
+
+// monomial returns c·x^n
+func monomial(variable string, c *big.Rat, n int) *Polynomial {
+	coeffs := make([]*big.Rat, n+1)
+	for i := range coeffs {
+		coeffs[i] = new(big.Rat)
+	}
+	coeffs[n].Set(c)
+	return New(variable, coeffs...)
+}
+
+// trim drops zero leading coefficients
+func (p *Polynomial) trim() *Polynomial {
+	n := len(p.coeffs)
+	for n > 0 && p.coeffs[n-1].Sign() == 0 {
+		n--
+	}
+	p.coeffs = p.coeffs[:n]
+	return p
+}
+
+// Degree returns the degree, or -1 for the zero polynomial
+func (p *Polynomial) Degree() int {
+	return len(p.coeffs) - 1
+}
+
+// IsZero reports whether p is the zero polynomial
+func (p *Polynomial) IsZero() bool {
+	return len(p.coeffs) == 0
+}
+
+// Coefficients returns a copy of the coefficients, constant term first
+func (p *Polynomial) Coefficients() []*big.Rat {
+	coeffs := make([]*big.Rat, len(p.coeffs))
+	for i, c := range p.coeffs {
+		coeffs[i] = new(big.Rat).Set(c)
+	}
+	return coeffs
+}
+
+// coefficient returns the coefficient of x^i, which is zero past the degree
+func (p *Polynomial) coefficient(i int) *big.Rat {
+	if i < len(p.coeffs) {
+		return p.coeffs[i]
+	}
+	return new(big.Rat)
+}
+
+// leading returns the leading coefficient, zero for the zero polynomial
+func (p *Polynomial) leading() *big.Rat {
+	return p.coefficient(p.Degree())
+}
+
+// variable picks the variable of a result from two operands, preferring
+// one that is not a constant
+func variable(a, b *Polynomial) string {
+	if a.Degree() < 1 && b.Degree() >= 1 {
+		return b.Variable
+	}
+	return a.Variable
+}
+
+// Add returns a + b
+func Add(a, b *Polynomial) *Polynomial {
+	n := max(len(a.coeffs), len(b.coeffs))
+	coeffs := make([]*big.Rat, n)
+	for i := range coeffs {
+		coeffs[i] = new(big.Rat).Add(a.coefficient(i), b.coefficient(i))
+	}
+	return (&Polynomial{Variable: variable(a, b), coeffs: coeffs}).trim()
+}
+
+// Sub returns a - b
+func Sub(a, b *Polynomial) *Polynomial {
+	return Add(a, b.Scale(big.NewRat(-1, 1)))
+}
+
+// Mul returns a·b
+func Mul(a, b *Polynomial) (*Polynomial, error) {
+	if a.IsZero() || b.IsZero() {
+		return &Polynomial{Variable: variable(a, b)}, nil
+	}
+	if a.Degree()+b.Degree() > MaxDegree {
+		return nil, calculator.NewError(calculator.ErrOverflow, "degree exceeds %d", MaxDegree)
+	}
+	coeffs := make([]*big.Rat, a.Degree()+b.Degree()+1)
+	for i := range coeffs {
+		coeffs[i] = new(big.Rat)
+	}
+	term := new(big.Rat)
+	for i, x := range a.coeffs {
+		for j, y := range b.coeffs {
+			coeffs[i+j].Add(coeffs[i+j], term.Mul(x, y))
+		}
+	}
+	return &Polynomial{Variable: variable(a, b), coeffs: coeffs}, nil
+}
+
+// Pow returns p^n for n ≥ 0
+func (p *Polynomial) Pow(n int) (*Polynomial, error) {
+	if p.Degree() > 0 && n > MaxDegree/p.Degree() {
+		return nil, calculator.NewError(calculator.ErrOverflow, "degree exceeds %d", MaxDegree)
+	}
+	result := constant(p.Variable, big.NewRat(1, 1))
+	base := p
+	for ; n > 0; n >>= 1 {
+		var err error
+		if n&1 == 1 {
+			if result, err = Mul(result, base); err != nil {
+				return nil, err
+			}
+		}
+		if n > 1 {
+			if base, err = Mul(base, base); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// Scale returns c·p
+func (p *Polynomial) Scale(c *big.Rat) *Polynomial {
+	coeffs := make([]*big.Rat, len(p.coeffs))
+	for i, x := range p.coeffs {
+		coeffs[i] = new(big.Rat).Mul(x, c)
+	}
+	return (&Polynomial{Variable: p.Variable, coeffs: coeffs}).trim()
+}
+
+// DivMod divides a by b with long division, returning the quotient and a
+// remainder of lower degree than b
+func DivMod(a, b *Polynomial) (quotient, remainder *Polynomial, err error) {
+	if b.IsZero() {
+		return nil, nil, calculator.NewError(calculator.ErrDivisionByZero, "division by the zero polynomial")
+	}
+	v := variable(a, b)
+	rem := a.Coefficients()
+	if a.Degree() < b.Degree() {
+		return &Polynomial{Variable: v}, &Polynomial{Variable: v, coeffs: rem}, nil
+	}
+
+	quo := make([]*big.Rat, a.Degree()-b.Degree()+1)
+	lead := b.leading()
+	term := new(big.Rat)
+	for i := len(quo) - 1; i >= 0; i-- {
+		q := new(big.Rat).Quo(rem[i+b.Degree()], lead)
+		quo[i] = q
+		if q.Sign() == 0 {
+			continue
+		}
+		for j, c := range b.coeffs {
+			rem[i+j].Sub(rem[i+j], term.Mul(q, c))
+		}
+	}
+	return (&Polynomial{Variable: v, coeffs: quo}).trim(), (&Polynomial{Variable: v, coeffs: rem}).trim(), nil
+}
+
+// Derivative returns dp/dx
+func (p *Polynomial) Derivative() *Polynomial {
+	if p.Degree() < 1 {
+		return &Polynomial{Variable: p.Variable}
+	}
+	coeffs := make([]*big.Rat, p.Degree())
+	for i := range coeffs {
+		coeffs[i] = new(big.Rat).Mul(p.coeffs[i+1], big.NewRat(int64(i+1), 1))
+	}
+	return &Polynomial{Variable: p.Variable, coeffs: coeffs}
+}
+
+// Monic returns p divided by its leading coefficient
+func (p *Polynomial) Monic() *Polynomial {
+	if p.IsZero() {
+		return p
+	}
+	return p.Scale(new(big.Rat).Inv(p.leading()))
+}
+
+// GCD returns the monic greatest common divisor of a and b, which is zero
+// only when both are
+func GCD(a, b *Polynomial) *Polynomial {
+	for !b.IsZero() {
+		_, rem, _ := DivMod(a, b)
+		a, b = b, rem.Monic()
+	}
+	return a.Monic()
+}
+
+// Evaluate returns p(x) exactly by Horner's method
+func (p *Polynomial) Evaluate(x *big.Rat) *big.Rat {
+	result := new(big.Rat)
+	for i := len(p.coeffs) - 1; i >= 0; i-- {
+		result.Mul(result, x).Add(result, p.coeffs[i])
+	}
+	return result
+}
+
+// EvaluateFloat returns p(x) by Horner's method in float64
+func (p *Polynomial) EvaluateFloat(x float64) float64 {
+	var result float64
+	for i := len(p.coeffs) - 1; i >= 0; i-- {
+		c, _ := p.coeffs[i].Float64()
+		result = result*x + c
+	}
+	return result
+}
+
+// String writes p as an expression the calculator accepts, highest power
+// first: "2*x^3 - 3/2*x + 1"
+func (p *Polynomial) String() string {
+	if p.IsZero() {
+		return "0"
+	}
+	var b strings.Builder
+	for i := p.Degree(); i >= 0; i-- {
+		c := p.coeffs[i]
+		if c.Sign() == 0 {
+			continue
+		}
+		switch {
+		case b.Len() == 0 && c.Sign() < 0:
+			b.WriteString("-")
+		case b.Len() > 0 && c.Sign() < 0:
+			b.WriteString(" - ")
+		case b.Len() > 0:
+			b.WriteString(" + ")
+		}
+
+		abs := new(big.Rat).Abs(c)
+		if i == 0 || !abs.IsInt() || abs.Num().Cmp(big.NewInt(1)) != 0 {
+			b.WriteString(abs.RatString())
+			if i > 0 {
+				b.WriteString("*")
+			}
+		}
+		switch {
+		case i == 1:
+			b.WriteString(p.Variable)
+		case i > 1:
+			b.WriteString(p.Variable + "^" + strconv.Itoa(i))
+		}
+	}
+	return b.String()
+}
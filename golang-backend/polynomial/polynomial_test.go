@@ -0,0 +1,128 @@
+package polynomial
+
+import (
+	"calculator-backend/calculator"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func mustParse(t *testing.T, expression string) *Polynomial {
+	t.Helper()
+	p, err := Parse(expression, "")
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", expression, err)
+	}
+	return p
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		expression string
+		want       string
+	}{
+		{"(x-1)^3(x+2)", "x^4 - x^3 - 3*x^2 + 5*x - 2"},
+		{"x^2/2 - 1/8", "1/2*x^2 - 1/8"},
+		{"(2x-1)^2/4", "x^2 - x + 1/4"},
+		{"y^3 - y", "y^3 - y"},
+		{"x - x", "0"},
+		{"7", "7"},
+	}
+	for _, tc := range cases {
+		if got := mustParse(t, tc.expression).String(); got != tc.want {
+			t.Errorf("Parse(%q) = %s, want %s", tc.expression, got, tc.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		expression string
+		kind       error
+	}{
+		{"x + y", calculator.ErrUnsupported},
+		{"x*y^2", calculator.ErrUnsupported},
+		{"sin(x)", calculator.ErrUnsupported},
+		{"2^x", calculator.ErrUnsupported},
+		{"1/x", calculator.ErrUnsupported},
+		{"x^0.5", calculator.ErrDomain},
+		{"x^1001", calculator.ErrOverflow},
+		{"(x^500 + 1)*(x^501 + 1)", calculator.ErrOverflow},
+		{"x +", calculator.ErrSyntax},
+	}
+	for _, tc := range cases {
+		if _, err := Parse(tc.expression, ""); !errors.Is(err, tc.kind) {
+			t.Errorf("Parse(%q): error %v, want %v", tc.expression, err, tc.kind)
+		}
+	}
+}
+
+func TestVariable(t *testing.T) {
+	if v, err := Variable("t^2 + 1", "2t"); err != nil || v != "t" {
+		t.Errorf("Variable = %q, %v; want t", v, err)
+	}
+	if v, err := Variable("pi*2"); err != nil || v != "x" {
+		t.Errorf("Variable of a constant = %q, %v; want x", v, err)
+	}
+	if _, err := Variable("x^2", "y + 1"); !errors.Is(err, calculator.ErrUnsupported) {
+		t.Errorf("mixed variables: error %v, want %v", err, calculator.ErrUnsupported)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a := mustParse(t, "x^2 - 1")
+	b := mustParse(t, "x + 1")
+
+	if got := Add(a, b).String(); got != "x^2 + x" {
+		t.Errorf("a + b = %s", got)
+	}
+	if got := Sub(a, a).String(); got != "0" {
+		t.Errorf("a - a = %s", got)
+	}
+	product, err := Mul(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := product.String(); got != "x^3 + x^2 - x - 1" {
+		t.Errorf("a·b = %s", got)
+	}
+	if got := product.Derivative().String(); got != "3*x^2 + 2*x - 1" {
+		t.Errorf("d/dx a·b = %s", got)
+	}
+	if got := GCD(a, product).String(); got != "x^2 - 1" {
+		t.Errorf("gcd = %s", got)
+	}
+	if got := product.Evaluate(big.NewRat(1, 2)).RatString(); got != "-9/8" {
+		t.Errorf("a·b at 1/2 = %s", got)
+	}
+	if _, err := mustParse(t, "x^2").Pow(501); !errors.Is(err, calculator.ErrOverflow) {
+		t.Errorf("degree 1002: error %v, want %v", err, calculator.ErrOverflow)
+	}
+}
+
+func TestDivMod(t *testing.T) {
+	cases := []struct {
+		a, b                string
+		quotient, remainder string
+	}{
+		{"x^3 - 2x + 5", "x^2 + 1", "x", "-3*x + 5"},
+		{"x^4 - 1", "x - 1", "x^3 + x^2 + x + 1", "0"},
+		{"x^2 + 1", "2x", "1/2*x", "1"},
+		{"3", "x^2", "0", "3"},
+		{"x^2 - 1/4", "1/2", "2*x^2 - 1/2", "0"},
+	}
+	for _, tc := range cases {
+		q, r, err := DivMod(mustParse(t, tc.a), mustParse(t, tc.b))
+		if err != nil {
+			t.Errorf("(%s)/(%s): %v", tc.a, tc.b, err)
+			continue
+		}
+		if q.String() != tc.quotient || r.String() != tc.remainder {
+			t.Errorf("(%s)/(%s) = %s remainder %s, want %s remainder %s", tc.a, tc.b, q, r, tc.quotient, tc.remainder)
+		}
+	}
+
+	if _, _, err := DivMod(mustParse(t, "x"), mustParse(t, "0")); !errors.Is(err, calculator.ErrDivisionByZero) {
+		t.Errorf("division by zero: error %v, want %v", err, calculator.ErrDivisionByZero)
+	}
+}
@@ -0,0 +1,152 @@
+package polynomial
+
+import (
+	"calculator-backend/calculator"
+	"math"
+	"math/big"
+	"math/cmplx"
+	"sort"
+)
+
+// MaxRootDegree bounds the degree of polynomials whose roots and factors
+// are computed
+const MaxRootDegree = 100
+
+// durandKernerIterations bounds the simultaneous iterations for the roots
+// of one factor
+const durandKernerIterations = 1000
+
+// Root is a complex root of a polynomial
+type Root struct {
+	Value        complex128
+	Multiplicity int
+	Rational     *big.Rat // the root exactly, when it is rational
+}
+
+// Roots returns every complex root of p, each once with its multiplicity,
+// ordered by real and then imaginary part. Multiplicities come from the
+// square-free decomposition of p, so they are exact; the roots of each
+// irreducible factor are found together by Durand–Kerner iteration and
+// polished with Newton's method.
+func (p *Polynomial) Roots() ([]Root, error) {
+	parts, _, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	roots := []Root{}
+	for _, part := range parts {
+		if part.poly.Degree() == 1 {
+			// a·x + b = 0 has the rational root -b/a
+			r := new(big.Rat).Quo(part.poly.coeffs[0], part.poly.coeffs[1])
+			r.Neg(r)
+			f, _ := r.Float64()
+			roots = append(roots, Root{Value: complex(f, 0), Multiplicity: part.multiplicity, Rational: r})
+			continue
+		}
+		for _, z := range part.roots {
+			roots = append(roots, Root{Value: z, Multiplicity: part.multiplicity})
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		a, b := roots[i].Value, roots[j].Value
+		if real(a) != real(b) {
+			return real(a) < real(b)
+		}
+		return imag(a) < imag(b)
+	})
+	return roots, nil
+}
+
+// floatCoefficients converts the coefficients of p to float64
+func (p *Polynomial) floatCoefficients() ([]complex128, error) {
+	coeffs := make([]complex128, len(p.coeffs))
+	for i, c := range p.coeffs {
+		f, _ := c.Float64()
+		if math.IsInf(f, 0) || (f == 0 && c.Sign() != 0) {
+			return nil, calculator.NewError(calculator.ErrOverflow, "coefficients are too large or too small to find roots")
+		}
+		coeffs[i] = complex(f, 0)
+	}
+	return coeffs, nil
+}
+
+// horner returns the value and derivative of the polynomial with the
+// given coefficients at z
+func horner(coeffs []complex128, z complex128) (value, slope complex128) {
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		slope = slope*z + value
+		value = value*z + coeffs[i]
+	}
+	return value, slope
+}
+
+// numericRoots finds the roots of a square-free polynomial, whose roots
+// are all simple, so the iteration converges quickly
+func (p *Polynomial) numericRoots() ([]complex128, error) {
+	coeffs, err := p.floatCoefficients()
+	if err != nil {
+		return nil, err
+	}
+	n := p.Degree()
+	lead := coeffs[n]
+	monic := make([]complex128, n+1)
+	for i, c := range coeffs {
+		monic[i] = c / lead
+	}
+
+	// Start on a circle of the Fujiwara bound on the roots' size, rotated
+	// off the real axis so conjugate pairs can separate
+	var radius float64
+	for k := 1; k <= n; k++ {
+		radius = math.Max(radius, math.Pow(cmplx.Abs(monic[n-k]), 1/float64(k)))
+	}
+	radius = math.Max(2*radius, 1e-3)
+	z := make([]complex128, n)
+	for k := range z {
+		z[k] = cmplx.Rect(radius, 2*math.Pi*float64(k)/float64(n)+0.4)
+	}
+
+	for iter := 0; iter < durandKernerIterations; iter++ {
+		change := 0.0
+		for i := range z {
+			value, _ := horner(monic, z[i])
+			den := complex(1, 0)
+			for j := range z {
+				if j != i {
+					den *= z[i] - z[j]
+				}
+			}
+			if den == 0 {
+				den = complex(1e-300, 0)
+			}
+			delta := value / den
+			z[i] -= delta
+			change = math.Max(change, cmplx.Abs(delta)/math.Max(1, cmplx.Abs(z[i])))
+		}
+		if change < 1e-15 {
+			break
+		}
+	}
+
+	for i := range z {
+		z[i] = polish(coeffs, z[i])
+		if math.Abs(imag(z[i])) <= 1e-12*math.Max(1, cmplx.Abs(z[i])) {
+			z[i] = complex(real(z[i]), 0)
+		}
+	}
+	return z, nil
+}
+
+// polish refines a root with Newton's method while the residual shrinks
+func polish(coeffs []complex128, z complex128) complex128 {
+	value, slope := horner(coeffs, z)
+	for i := 0; i < 10 && value != 0 && slope != 0; i++ {
+		next := z - value/slope
+		nextValue, nextSlope := horner(coeffs, next)
+		if cmplx.Abs(nextValue) >= cmplx.Abs(value) {
+			break
+		}
+		z, value, slope = next, nextValue, nextSlope
+	}
+	return z
+}
@@ -0,0 +1,46 @@
+package session
+
+import (
+	"calculator-backend/calculator"
+	"fmt"
+)
+
+// Assign binds a session variable
+func (s *Session) Assign(name string, value float64) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	if _, ok := s.Memory[name]; ok {
+		return fmt.Errorf("%w: '%s' is a memory register", ErrReservedName, name)
+	}
+	s.Variables[name] = value
+	return nil
+}
+
+// Define adds or replaces a user-defined function
+func (s *Session) Define(fn *calculator.UserFunction) error {
+	if err := checkName(fn.Name); err != nil {
+		return err
+	}
+	s.Functions[fn.Name] = fn
+	return nil
+}
+
+// Undefine removes the variable and the function with the given name.
+// Functions that call a removed function fail when next evaluated.
+func (s *Session) Undefine(name string) error {
+	_, isVar := s.Variables[name]
+	_, isFunc := s.Functions[name]
+	if !isVar && !isFunc {
+		return ErrUndefined
+	}
+	delete(s.Variables, name)
+	delete(s.Functions, name)
+	return nil
+}
+
+// ClearDefinitions removes every session variable and function
+func (s *Session) ClearDefinitions() {
+	s.Variables = map[string]float64{}
+	s.Functions = map[string]*calculator.UserFunction{}
+}
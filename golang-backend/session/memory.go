@@ -0,0 +1,87 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+)
+
+// MemoryOp is a calculator memory key
+type MemoryOp string
+
+// Memory operations
+const (
+	MemoryAdd      MemoryOp = "M+"
+	MemorySubtract MemoryOp = "M-"
+	MemoryRecall   MemoryOp = "MR"
+	MemoryClear    MemoryOp = "MC"
+	MemoryStore    MemoryOp = "MS"
+)
+
+// reservedNames cannot be used as memory registers because expressions
+// already give them a meaning
+var reservedNames = map[string]bool{
+	"ans": true, "pi": true, "π": true, "e": true, "i": true,
+}
+
+// ApplyMemory performs op on the named register and returns its new value.
+// M+, M- and MS use value, or the last answer when value is nil.
+func (s *Session) ApplyMemory(op MemoryOp, register string, value *float64) (float64, error) {
+	if register == "" {
+		register = DefaultRegister
+	}
+	if err := checkName(register); err != nil {
+		return 0, err
+	}
+	if _, ok := s.Variables[register]; ok {
+		return 0, fmt.Errorf("%w: '%s' is a session variable", ErrReservedName, register)
+	}
+	if _, ok := s.Functions[register]; ok {
+		return 0, fmt.Errorf("%w: '%s' is a session function", ErrReservedName, register)
+	}
+
+	switch op {
+	case MemoryRecall:
+		return s.Memory[register], nil
+	case MemoryClear:
+		if register == DefaultRegister {
+			s.Memory[register] = 0
+		} else {
+			delete(s.Memory, register)
+		}
+		return 0, nil
+	case MemoryAdd, MemorySubtract, MemoryStore:
+	default:
+		return 0, errors.New("unknown memory operation " + string(op))
+	}
+
+	operand := s.Ans
+	if value != nil {
+		operand = *value
+	} else if !s.HasAns {
+		return 0, ErrNoAnswer
+	}
+
+	switch op {
+	case MemoryAdd:
+		s.Memory[register] += operand
+	case MemorySubtract:
+		s.Memory[register] -= operand
+	default:
+		s.Memory[register] = operand
+	}
+	return s.Memory[register], nil
+}
+
+// checkName verifies that name is an identifier expressions can refer to
+func checkName(name string) error {
+	if reservedNames[name] {
+		return fmt.Errorf("%w: '%s'", ErrReservedName, name)
+	}
+	for i, ch := range name {
+		if !(ch == '_' || unicode.IsLetter(ch) || (i > 0 && unicode.IsDigit(ch))) {
+			return ErrBadName
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package session
+
+import (
+	"calculator-backend/calculator"
+	"errors"
+	"testing"
+)
+
+func newTestSession(t *testing.T) *Session {
+	t.Helper()
+	s, err := NewStore(0, 0).Create(calculator.AngleDefault, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestApplyMemory(t *testing.T) {
+	s := newTestSession(t)
+	five, two := 5.0, 2.0
+
+	steps := []struct {
+		op       MemoryOp
+		register string
+		value    *float64
+		want     float64
+	}{
+		{MemoryStore, "", &five, 5},
+		{MemoryAdd, "", &two, 7},
+		{MemorySubtract, "", &five, 2},
+		{MemoryRecall, "mem", nil, 2},
+		{MemoryStore, "r1", &two, 2},
+		{MemoryClear, "", nil, 0},
+		{MemoryRecall, "r1", nil, 2},
+	}
+	for i, step := range steps {
+		got, err := s.ApplyMemory(step.op, step.register, step.value)
+		if err != nil {
+			t.Fatalf("step %d: %s %q: %v", i, step.op, step.register, err)
+		}
+		if got != step.want {
+			t.Errorf("step %d: %s %q = %v, want %v", i, step.op, step.register, got, step.want)
+		}
+	}
+}
+
+func TestApplyMemoryErrors(t *testing.T) {
+	s := newTestSession(t)
+	if err := s.Assign("x", 1); err != nil {
+		t.Fatal(err)
+	}
+	fn, err := calculator.New().DefineFunction(mustParse(t, "f(t) = t^2"), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Define(fn); err != nil {
+		t.Fatal(err)
+	}
+	one := 1.0
+
+	cases := []struct {
+		name     string
+		op       MemoryOp
+		register string
+		value    *float64
+		want     error
+	}{
+		{"reserved constant", MemoryStore, "pi", &one, ErrReservedName},
+		{"ans", MemoryRecall, "ans", nil, ErrReservedName},
+		{"not an identifier", MemoryStore, "2x", &one, ErrBadName},
+		{"session variable", MemoryStore, "x", &one, ErrReservedName},
+		{"session function", MemoryAdd, "f", &one, ErrReservedName},
+		{"no answer yet", MemoryAdd, "", nil, ErrNoAnswer},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := s.ApplyMemory(tc.op, tc.register, tc.value); !errors.Is(err, tc.want) {
+				t.Errorf("%s %q: error %v, want %v", tc.op, tc.register, err, tc.want)
+			}
+		})
+	}
+	if _, err := s.ApplyMemory("M*", "", &one); err == nil {
+		t.Error("unknown operation: expected an error")
+	}
+}
+
+func TestAssignMemoryRegister(t *testing.T) {
+	s := newTestSession(t)
+	if err := s.Assign(DefaultRegister, 1); !errors.Is(err, ErrReservedName) {
+		t.Errorf("Assign(%q): error %v, want %v", DefaultRegister, err, ErrReservedName)
+	}
+}
+
+func mustParse(t *testing.T, input string) *calculator.Statement {
+	t.Helper()
+	stmt, err := calculator.ParseStatement(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stmt
+}
@@ -0,0 +1,187 @@
+package session
+
+import (
+	"calculator-backend/calculator"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultRegister is the memory register used by M+, M-, MR and MC when
+// none is named; expressions read it as mem
+const DefaultRegister = "mem"
+
+// DefaultTTL is how long an idle session lives
+const DefaultTTL = 30 * time.Minute
+
+// DefaultLimit is how many live sessions a store holds by default
+const DefaultLimit = 10000
+
+// Errors returned by Store
+var (
+	ErrNotFound     = errors.New("session not found or expired")
+	ErrFull         = errors.New("too many active sessions")
+	ErrNoAnswer     = errors.New("session has no previous answer")
+	ErrBadName      = errors.New("name must be an identifier")
+	ErrReservedName = errors.New("name is reserved")
+	ErrUndefined    = errors.New("no variable or function with that name")
+)
+
+// Session is the calculator state shared by requests carrying its ID
+type Session struct {
+	ID        string
+	Ans       float64
+	HasAns    bool
+	Memory    map[string]float64 // named memory registers
+	Variables map[string]float64
+	Functions map[string]*calculator.UserFunction
+	AngleMode calculator.AngleMode
+	Digits    int // significant digits for display; 0 keeps full precision
+	ExpiresAt time.Time
+}
+
+// Lookup resolves ans, memory registers and session variables, so a
+// Session can serve as a calculator.Scope
+func (s *Session) Lookup(name string) (float64, bool) {
+	if name == "ans" {
+		return s.Ans, s.HasAns
+	}
+	if value, ok := s.Memory[name]; ok {
+		return value, true
+	}
+	value, ok := s.Variables[name]
+	return value, ok
+}
+
+// Function resolves a user-defined function
+func (s *Session) Function(name string) (*calculator.UserFunction, bool) {
+	fn, ok := s.Functions[name]
+	return fn, ok
+}
+
+// clone returns a copy that shares no maps with s
+func (s *Session) clone() *Session {
+	c := *s
+	c.Memory = make(map[string]float64, len(s.Memory))
+	for name, value := range s.Memory {
+		c.Memory[name] = value
+	}
+	c.Variables = make(map[string]float64, len(s.Variables))
+	for name, value := range s.Variables {
+		c.Variables[name] = value
+	}
+	// Function bodies are immutable, so the copy may share them
+	c.Functions = make(map[string]*calculator.UserFunction, len(s.Functions))
+	for name, fn := range s.Functions {
+		c.Functions[name] = fn
+	}
+	return &c
+}
+
+// Store keeps at most limit sessions in memory. A session expires once it
+// has been idle for the store's TTL; every access renews it.
+type Store struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+	ttl      time.Duration
+	limit    int
+}
+
+// NewStore creates a session store, using DefaultTTL when ttl is not
+// positive and DefaultLimit when limit is not positive
+func NewStore(ttl time.Duration, limit int) *Store {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	return &Store{sessions: make(map[string]*Session), ttl: ttl, limit: limit}
+}
+
+// Create starts a new session with the given preferences. It returns
+// ErrFull when the store already holds its limit of live sessions.
+func (st *Store) Create(mode calculator.AngleMode, digits int) (*Session, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.sweep()
+	if len(st.sessions) >= st.limit {
+		return nil, ErrFull
+	}
+
+	s := &Session{
+		ID:        id,
+		Memory:    map[string]float64{DefaultRegister: 0},
+		Variables: map[string]float64{},
+		Functions: map[string]*calculator.UserFunction{},
+		AngleMode: mode,
+		Digits:    digits,
+		ExpiresAt: time.Now().Add(st.ttl),
+	}
+	st.sessions[id] = s
+	return s.clone(), nil
+}
+
+// Get returns a snapshot of the session, renewing its TTL
+func (st *Store) Get(id string) (*Session, error) {
+	var snapshot *Session
+	err := st.Update(id, func(s *Session) error {
+		snapshot = s.clone()
+		return nil
+	})
+	return snapshot, err
+}
+
+// Update applies fn to the session under the store's lock, renewing its
+// TTL. It returns fn's error, or ErrNotFound for an unknown or expired ID.
+func (st *Store) Update(id string, fn func(s *Session) error) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	s, ok := st.sessions[id]
+	now := time.Now()
+	if !ok || now.After(s.ExpiresAt) {
+		delete(st.sessions, id)
+		return ErrNotFound
+	}
+	s.ExpiresAt = now.Add(st.ttl)
+	return fn(s)
+}
+
+// Delete ends a session
+func (st *Store) Delete(id string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if _, ok := st.sessions[id]; !ok {
+		return ErrNotFound
+	}
+	delete(st.sessions, id)
+	return nil
+}
+
+// sweep drops expired sessions; callers hold the lock
+func (st *Store) sweep() {
+	now := time.Now()
+	for id, s := range st.sessions {
+		if now.After(s.ExpiresAt) {
+			delete(st.sessions, id)
+		}
+	}
+}
+
+// newID returns a random 128-bit session ID in hex
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
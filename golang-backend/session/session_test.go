@@ -0,0 +1,131 @@
+package session
+
+import (
+	"calculator-backend/calculator"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStoreTTL(t *testing.T) {
+	const ttl = 50 * time.Millisecond
+	st := NewStore(ttl, 0)
+	s, err := st.Create(calculator.AngleRadian, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each access renews the session, so it outlives its first TTL
+	for i := 0; i < 4; i++ {
+		time.Sleep(ttl / 2)
+		if _, err := st.Get(s.ID); err != nil {
+			t.Fatalf("access %d: %v", i, err)
+		}
+	}
+
+	time.Sleep(2 * ttl)
+	if _, err := st.Get(s.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("idle session: error %v, want %v", err, ErrNotFound)
+	}
+	if err := st.Update(s.ID, func(*Session) error { return nil }); !errors.Is(err, ErrNotFound) {
+		t.Errorf("update after expiry: error %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestStoreSweep(t *testing.T) {
+	st := NewStore(10*time.Millisecond, 0)
+	old, _ := st.Create(calculator.AngleDefault, 0)
+	time.Sleep(20 * time.Millisecond)
+
+	// Creating a session drops the expired ones
+	if _, err := st.Create(calculator.AngleDefault, 0); err != nil {
+		t.Fatal(err)
+	}
+	st.mu.Lock()
+	_, kept := st.sessions[old.ID]
+	count := len(st.sessions)
+	st.mu.Unlock()
+	if kept || count != 1 {
+		t.Errorf("after sweep: %d sessions, expired kept %v", count, kept)
+	}
+}
+
+func TestStoreLimit(t *testing.T) {
+	st := NewStore(10*time.Millisecond, 3)
+	for i := 0; i < 3; i++ {
+		if _, err := st.Create(calculator.AngleDefault, 0); err != nil {
+			t.Fatalf("session %d: %v", i+1, err)
+		}
+	}
+	if _, err := st.Create(calculator.AngleDefault, 0); !errors.Is(err, ErrFull) {
+		t.Errorf("fourth session: error %v, want %v", err, ErrFull)
+	}
+
+	// Expired sessions no longer count against the limit
+	time.Sleep(20 * time.Millisecond)
+	if _, err := st.Create(calculator.AngleDefault, 0); err != nil {
+		t.Errorf("after expiry: %v", err)
+	}
+}
+
+func TestStoreSnapshots(t *testing.T) {
+	st := NewStore(0, 0)
+	s, err := st.Create(calculator.AngleDegree, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.AngleMode != calculator.AngleDegree || s.Memory[DefaultRegister] != 0 || s.HasAns {
+		t.Errorf("new session = %+v", s)
+	}
+
+	// Changing a snapshot does not change the stored session
+	s.Memory[DefaultRegister] = 5
+	s.Variables["x"] = 1
+	stored, _ := st.Get(s.ID)
+	if stored.Memory[DefaultRegister] != 0 || len(stored.Variables) != 0 {
+		t.Errorf("snapshot shares state with the store: %+v", stored)
+	}
+
+	err = st.Update(s.ID, func(s *Session) error {
+		s.Ans, s.HasAns = 42, true
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, _ = st.Get(s.ID)
+	if value, ok := stored.Lookup("ans"); !ok || value != 42 {
+		t.Errorf("ans = %v, %v; want 42", value, ok)
+	}
+
+	if err := st.Delete(s.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Delete(s.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete: error %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	s := newTestSession(t)
+	if _, ok := s.Lookup("ans"); ok {
+		t.Error("ans is defined before any answer")
+	}
+	s.Memory["r1"] = 3
+	s.Variables["x"] = 7
+	cases := []struct {
+		name  string
+		value float64
+		ok    bool
+	}{
+		{"mem", 0, true},
+		{"r1", 3, true},
+		{"x", 7, true},
+		{"y", 0, false},
+	}
+	for _, tc := range cases {
+		if value, ok := s.Lookup(tc.name); value != tc.value || ok != tc.ok {
+			t.Errorf("Lookup(%q) = %v, %v; want %v, %v", tc.name, value, ok, tc.value, tc.ok)
+		}
+	}
+}
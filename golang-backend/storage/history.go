@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"calculator-backend/models"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrHistoryNotFound is returned when no entry has the requested ID
+var ErrHistoryNotFound = errors.New("history entry not found")
+
+// DefaultHistoryLimit is the number of entries kept before the oldest are
+// discarded
+const DefaultHistoryLimit = 1000
+
+// HistoryFilter selects and paginates history entries. Zero values match
+// everything; a zero Limit returns all remaining entries.
+type HistoryFilter struct {
+	Query  string    // case-insensitive substring of the expression
+	Since  time.Time // entries recorded at or after this time
+	Until  time.Time // entries recorded before this time
+	Failed *bool     // only failed (true) or successful (false) evaluations
+	Offset int
+	Limit  int
+}
+
+// HistoryStore persists evaluated expressions
+type HistoryStore interface {
+	// Add stores an entry, assigning its ID and, if empty, its timestamp
+	Add(entry models.HistoryResponse) (models.HistoryResponse, error)
+	// List returns matching entries, newest first, and the total number of
+	// matches before pagination
+	List(filter HistoryFilter) ([]models.HistoryResponse, int, error)
+	// Get returns the entry with the given ID
+	Get(id int) (models.HistoryResponse, error)
+	// Delete removes the entry with the given ID
+	Delete(id int) error
+	// Clear removes every entry
+	Clear() error
+}
+
+// MemoryHistoryStore keeps history in memory; it is lost on restart
+type MemoryHistoryStore struct {
+	mu      sync.RWMutex
+	entries []models.HistoryResponse // oldest first
+	nextID  int
+	limit   int
+}
+
+// NewMemoryHistoryStore creates an in-memory store holding at most limit
+// entries, or DefaultHistoryLimit when limit is not positive
+func NewMemoryHistoryStore(limit int) *MemoryHistoryStore {
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	return &MemoryHistoryStore{nextID: 1, limit: limit}
+}
+
+// Add stores an entry
+func (s *MemoryHistoryStore) Add(entry models.HistoryResponse) (models.HistoryResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.add(entry), nil
+}
+
+func (s *MemoryHistoryStore) add(entry models.HistoryResponse) models.HistoryResponse {
+	entry.ID = s.nextID
+	s.nextID++
+	if entry.Timestamp == "" {
+		entry.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+
+	s.entries = append(s.entries, entry)
+	if len(s.entries) > s.limit {
+		s.entries = append([]models.HistoryResponse(nil), s.entries[len(s.entries)-s.limit:]...)
+	}
+	return entry
+}
+
+// List returns matching entries, newest first
+func (s *MemoryHistoryStore) List(filter HistoryFilter) ([]models.HistoryResponse, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query := strings.ToLower(filter.Query)
+	matches := []models.HistoryResponse{}
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		if filter.matches(s.entries[i], query) {
+			matches = append(matches, s.entries[i])
+		}
+	}
+
+	total := len(matches)
+	start := min(max(filter.Offset, 0), total)
+	end := total
+	if filter.Limit > 0 {
+		end = min(start+filter.Limit, total)
+	}
+	return matches[start:end], total, nil
+}
+
+// matches reports whether entry passes the filter; query is pre-lowered
+func (f HistoryFilter) matches(entry models.HistoryResponse, query string) bool {
+	if query != "" && !strings.Contains(strings.ToLower(entry.Expression), query) {
+		return false
+	}
+	if f.Failed != nil && *f.Failed != (entry.Error != "") {
+		return false
+	}
+	if f.Since.IsZero() && f.Until.IsZero() {
+		return true
+	}
+
+	recorded, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+	if err != nil {
+		return false
+	}
+	if !f.Since.IsZero() && recorded.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !recorded.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// Get returns the entry with the given ID
+func (s *MemoryHistoryStore) Get(id int) (models.HistoryResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if i := s.index(id); i >= 0 {
+		return s.entries[i], nil
+	}
+	return models.HistoryResponse{}, ErrHistoryNotFound
+}
+
+// Delete removes the entry with the given ID
+func (s *MemoryHistoryStore) Delete(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.delete(id)
+}
+
+func (s *MemoryHistoryStore) delete(id int) error {
+	i := s.index(id)
+	if i < 0 {
+		return ErrHistoryNotFound
+	}
+	s.entries = append(s.entries[:i], s.entries[i+1:]...)
+	return nil
+}
+
+// Clear removes every entry. IDs are not reused.
+func (s *MemoryHistoryStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = nil
+	return nil
+}
+
+// index finds an entry by ID; entries are in ascending ID order
+func (s *MemoryHistoryStore) index(id int) int {
+	i := sort.Search(len(s.entries), func(i int) bool { return s.entries[i].ID >= id })
+	if i < len(s.entries) && s.entries[i].ID == id {
+		return i
+	}
+	return -1
+}
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"calculator-backend/models"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// FileHistoryStore keeps history in memory and appends every change to a
+// log file, so entries survive restarts. The log is compacted into a single
+// snapshot when the store opens and whenever it outgrows the history limit.
+type FileHistoryStore struct {
+	*MemoryHistoryStore
+	path    string
+	file    *os.File // the log, opened for appending
+	records int      // records appended since the last snapshot
+}
+
+// historyRecord is one line of the log. A snapshot sets NextID and Entries,
+// and is the only line after compaction; later lines each record one change.
+type historyRecord struct {
+	NextID  int                      `json:"nextId,omitempty"`
+	Entries []models.HistoryResponse `json:"entries,omitempty"`
+	Add     *models.HistoryResponse  `json:"add,omitempty"`
+	Delete  int                      `json:"delete,omitempty"`
+	Clear   bool                     `json:"clear,omitempty"`
+}
+
+// NewFileHistoryStore opens the store at path, replaying any changes saved
+// by a previous run. A missing file starts an empty history.
+func NewFileHistoryStore(path string, limit int) (*FileHistoryStore, error) {
+	s := &FileHistoryStore{MemoryHistoryStore: NewMemoryHistoryStore(limit), path: path}
+
+	f, err := os.Open(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		err = s.replay(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// replay applies the records in r to the in-memory history
+func (s *FileHistoryStore) replay(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	for {
+		var record historyRecord
+		err := dec.Decode(&record)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if record.Entries != nil || record.NextID != 0 {
+			s.entries = nil
+			for _, entry := range record.Entries {
+				s.restore(entry)
+			}
+			s.nextID = max(s.nextID, record.NextID)
+		}
+		switch {
+		case record.Add != nil:
+			s.restore(*record.Add)
+		case record.Delete != 0:
+			s.delete(record.Delete)
+		case record.Clear:
+			s.entries = nil
+		}
+	}
+}
+
+// restore adds a saved entry, keeping its ID
+func (s *FileHistoryStore) restore(entry models.HistoryResponse) {
+	s.entries = append(s.entries, entry)
+	if len(s.entries) > s.limit {
+		s.entries = s.entries[1:]
+	}
+	s.nextID = max(s.nextID, entry.ID+1)
+}
+
+// Close closes the log file
+func (s *FileHistoryStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// Add stores an entry and appends it to the log
+func (s *FileHistoryStore) Add(entry models.HistoryResponse) (models.HistoryResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry = s.add(entry)
+	return entry, s.append(historyRecord{Add: &entry})
+}
+
+// Delete removes the entry with the given ID and appends the removal to
+// the log
+func (s *FileHistoryStore) Delete(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.delete(id); err != nil {
+		return err
+	}
+	return s.append(historyRecord{Delete: id})
+}
+
+// Clear removes every entry and appends the removal to the log
+func (s *FileHistoryStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = nil
+	return s.append(historyRecord{Clear: true})
+}
+
+// append writes one record to the end of the log, compacting it once it
+// holds more records than the history limit, so each change costs O(1)
+// amortized. Callers hold the write lock.
+func (s *FileHistoryStore) append(record historyRecord) error {
+	if s.records >= s.limit {
+		return s.compact()
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	s.records++
+	return nil
+}
+
+// compact writes a snapshot of the history to a temporary file, renames it
+// over the log, so a crash never leaves a truncated file, and reopens the
+// log for appending. Callers hold the write lock.
+func (s *FileHistoryStore) compact() error {
+	data, err := json.Marshal(historyRecord{NextID: s.nextID, Entries: s.entries})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return err
+	}
+
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file, err = os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	s.records = 0
+	return nil
+}
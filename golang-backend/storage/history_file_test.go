@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileHistoryStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	s, err := NewFileHistoryStore(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(t, s, time.Now(), "1+1", "2+2", "3+3")
+	if err := s.Delete(2); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewFileHistoryStore(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, _, _ := reopened.List(HistoryFilter{})
+	if got := expressions(entries); !equal(got, []string{"3+3", "1+1"}) {
+		t.Errorf("reloaded %v", got)
+	}
+	// IDs continue after the highest ever assigned, even if it was deleted
+	if err := reopened.Delete(3); err != nil {
+		t.Fatal(err)
+	}
+	again, err := NewFileHistoryStore(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(t, again, time.Now(), "4+4")
+	entries, _, _ = again.List(HistoryFilter{Limit: 1})
+	if len(entries) != 1 || entries[0].ID != 4 {
+		t.Errorf("new entry = %+v, want ID 4", entries)
+	}
+
+	if err := again.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	cleared, err := NewFileHistoryStore(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, total, _ := cleared.List(HistoryFilter{}); total != 0 {
+		t.Errorf("%d entries after Clear", total)
+	}
+
+	// Saving leaves no temporary files behind
+	files, _ := os.ReadDir(filepath.Dir(path))
+	if len(files) != 1 {
+		t.Errorf("directory holds %d files, want 1", len(files))
+	}
+}
+
+func TestFileHistoryStoreLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	s, err := NewFileHistoryStore(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(t, s, time.Now(), "1", "2", "3", "4")
+
+	// Reopening with a smaller limit keeps the newest entries
+	small, err := NewFileHistoryStore(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, _, _ := small.List(HistoryFilter{})
+	if got := expressions(entries); !equal(got, []string{"4", "3"}) {
+		t.Errorf("reloaded %v, want the newest two", got)
+	}
+}
+
+func TestFileHistoryStoreErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := NewFileHistoryStore(filepath.Join(dir, "missing.json"), 0)
+	if err != nil {
+		t.Fatalf("missing file: %v", err)
+	}
+	if _, total, _ := s.List(HistoryFilter{}); total != 0 {
+		t.Errorf("missing file gave %d entries", total)
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileHistoryStore(corrupt, 0); err == nil {
+		t.Error("corrupt file: expected an error")
+	}
+}
+
+func TestFileHistoryStoreAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	s, err := NewFileHistoryStore(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// Each change adds one line after the snapshot, until the log holds
+	// more changes than the limit and is compacted back to a snapshot
+	lines := func() int {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bytes.Count(data, []byte("\n"))
+	}
+	fill(t, s, time.Now(), "1", "2")
+	if err := s.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := lines(); got != 4 {
+		t.Errorf("log holds %d lines, want 4", got)
+	}
+	fill(t, s, time.Now(), "3")
+	if got := lines(); got != 1 {
+		t.Errorf("after compaction: %d lines, want 1", got)
+	}
+
+	reopened, err := NewFileHistoryStore(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	entries, _, _ := reopened.List(HistoryFilter{})
+	if got := expressions(entries); !equal(got, []string{"3", "2"}) {
+		t.Errorf("reloaded %v", got)
+	}
+}
+
+func TestFileHistoryStoreSnapshot(t *testing.T) {
+	// A file holding only a snapshot, as earlier versions wrote, still loads
+	path := filepath.Join(t.TempDir(), "history.json")
+	snapshot := `{"nextId":8,"entries":[{"id":5,"expression":"1+1","result":2}]}`
+	if err := os.WriteFile(path, []byte(snapshot), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewFileHistoryStore(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	fill(t, s, time.Now(), "2+2")
+	entries, _, _ := s.List(HistoryFilter{})
+	if len(entries) != 2 || entries[0].ID != 8 || entries[1].Expression != "1+1" {
+		t.Errorf("entries = %+v", entries)
+	}
+}
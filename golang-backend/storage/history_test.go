@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"calculator-backend/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fill adds entries with the given expressions, one minute apart from
+// base, and a failure for any expression starting with "!"
+func fill(t *testing.T, s HistoryStore, base time.Time, expressions ...string) {
+	t.Helper()
+	for i, expr := range expressions {
+		entry := models.HistoryResponse{
+			Expression: expr,
+			Timestamp:  base.Add(time.Duration(i) * time.Minute).Format(time.RFC3339Nano),
+		}
+		if expr[0] == '!' {
+			entry.Error = "failed"
+		}
+		if _, err := s.Add(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func expressions(entries []models.HistoryResponse) []string {
+	result := make([]string, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.Expression
+	}
+	return result
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMemoryHistoryList(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryHistoryStore(0)
+	fill(t, s, base, "1+1", "sin(30)", "!1/0", "SIN(60)", "2*3")
+
+	yes, no := true, false
+	cases := []struct {
+		name   string
+		filter HistoryFilter
+		want   []string
+		total  int
+	}{
+		{"all, newest first", HistoryFilter{}, []string{"2*3", "SIN(60)", "!1/0", "sin(30)", "1+1"}, 5},
+		{"query ignores case", HistoryFilter{Query: "Sin"}, []string{"SIN(60)", "sin(30)"}, 2},
+		{"failed", HistoryFilter{Failed: &yes}, []string{"!1/0"}, 1},
+		{"succeeded", HistoryFilter{Failed: &no, Limit: 2}, []string{"2*3", "SIN(60)"}, 4},
+		{"since is inclusive", HistoryFilter{Since: base.Add(3 * time.Minute)}, []string{"2*3", "SIN(60)"}, 2},
+		{"until is exclusive", HistoryFilter{Until: base.Add(time.Minute)}, []string{"1+1"}, 1},
+		{"page", HistoryFilter{Offset: 1, Limit: 2}, []string{"SIN(60)", "!1/0"}, 5},
+		{"offset past the end", HistoryFilter{Offset: 10}, []string{}, 5},
+	}
+	for _, tc := range cases {
+		entries, total, err := s.List(tc.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := expressions(entries); !equal(got, tc.want) || total != tc.total {
+			t.Errorf("%s: %v (total %d), want %v (total %d)", tc.name, got, total, tc.want, tc.total)
+		}
+	}
+}
+
+func TestMemoryHistoryLimit(t *testing.T) {
+	s := NewMemoryHistoryStore(3)
+	fill(t, s, time.Now(), "1", "2", "3", "4", "5")
+
+	entries, total, _ := s.List(HistoryFilter{})
+	if got := expressions(entries); !equal(got, []string{"5", "4", "3"}) || total != 3 {
+		t.Errorf("kept %v, want the newest three", got)
+	}
+	if _, err := s.Get(1); !errors.Is(err, ErrHistoryNotFound) {
+		t.Errorf("Get(1) after eviction: error %v", err)
+	}
+	if entry, err := s.Get(5); err != nil || entry.Expression != "5" {
+		t.Errorf("Get(5) = %+v, %v", entry, err)
+	}
+}
+
+func TestMemoryHistoryDelete(t *testing.T) {
+	s := NewMemoryHistoryStore(0)
+	fill(t, s, time.Now(), "1", "2", "3")
+
+	if err := s.Delete(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(2); !errors.Is(err, ErrHistoryNotFound) {
+		t.Errorf("second Delete(2): error %v", err)
+	}
+	if err := s.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	// IDs are not reused after Clear
+	entry, _ := s.Add(models.HistoryResponse{Expression: "4"})
+	if entry.ID != 4 || entry.Timestamp == "" {
+		t.Errorf("entry after Clear = %+v, want ID 4 with a timestamp", entry)
+	}
+}